@@ -0,0 +1,294 @@
+// Package dbtest menyiapkan database untuk test yang harus jalan di semua driver (SQLite, Postgres, MySQL).
+// Dipakai test package database & repository; hanya boleh di-import dari file _test.go.
+package dbtest
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Nama driver, sama dengan database.Driver* (package ini tidak meng-import database supaya
+// test internal package database tetap bisa memakainya)
+const (
+	driverSQLite   = "sqlite"
+	driverPostgres = "postgres"
+	driverMySQL    = "mysql"
+)
+
+// OpenFunc: database.Open
+type OpenFunc func(driver, dsn string, config *gorm.Config) (*gorm.DB, error)
+
+// Test dijalankan di SQLite (selalu) + Postgres kalau tersedia:
+//   - TEST_POSTGRES_DSN diisi, atau
+//   - docker ada di PATH (container postgres dinyalakan sementara), atau
+//   - initdb & pg_ctl ada di PATH (cluster sementara di folder temp).
+//
+// MySQL ikut dites kalau TEST_MYSQL_DSN diisi (database khusus test, isinya dihapus).
+var (
+	open        OpenFunc
+	postgresDSN string
+	mysqlDSN    = os.Getenv("TEST_MYSQL_DSN")
+)
+
+// Main dipanggil dari TestMain: menyalakan Postgres (kalau bisa), menjalankan test, lalu mematikannya.
+//
+//	func TestMain(m *testing.M) { os.Exit(dbtest.Main(m, database.Open)) }
+func Main(m *testing.M, openFunc OpenFunc) int {
+	open = openFunc
+	stop := setupPostgres()
+	defer stop()
+	return m.Run()
+}
+
+type DB struct {
+	Driver string
+	DB     *gorm.DB
+}
+
+// ForEach menjalankan fn untuk setiap database yang tersedia, masing-masing dengan skema kosong
+func ForEach(t *testing.T, fn func(t *testing.T, tdb DB)) {
+	t.Helper()
+	t.Run(driverSQLite, func(t *testing.T) {
+		fn(t, DB{Driver: driverSQLite, DB: openSQLiteTestDB(t)})
+	})
+	t.Run(driverPostgres, func(t *testing.T) {
+		if postgresDSN == "" {
+			t.Skip("postgres tidak tersedia")
+		}
+		fn(t, DB{Driver: driverPostgres, DB: openPostgresTestDB(t)})
+	})
+	t.Run(driverMySQL, func(t *testing.T) {
+		if mysqlDSN == "" {
+			t.Skip("TEST_MYSQL_DSN tidak diisi")
+		}
+		fn(t, DB{Driver: driverMySQL, DB: openMySQLTestDB(t)})
+	})
+}
+
+func openSQLiteTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := open(driverSQLite, ":memory:", &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gagal buka sqlite: %v", err)
+	}
+	// :memory: dibuat per koneksi, jadi paksa satu koneksi saja
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// Setiap test Postgres dapat schema sendiri lewat search_path, lalu di-drop setelah selesai
+func openPostgresTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	schemaName := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	admin, err := open(driverPostgres, postgresDSN, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gagal konek postgres: %v", err)
+	}
+	if err := admin.Exec("CREATE SCHEMA " + schemaName).Error; err != nil {
+		t.Fatalf("gagal membuat schema: %v", err)
+	}
+
+	db, err := open(driverPostgres, withSearchPath(postgresDSN, schemaName), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gagal konek postgres: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schemaName + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func withSearchPath(dsn, schemaName string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + schemaName
+	}
+	return dsn + " search_path=" + schemaName
+}
+
+// MySQL tidak punya schema terpisah, jadi kosongkan database test sebelum & sesudah dipakai
+func openMySQLTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := open(driverMySQL, mysqlDSN, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gagal konek mysql: %v", err)
+	}
+	// Satu koneksi supaya SET FOREIGN_KEY_CHECKS berlaku untuk semua DROP
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	dropAllTables(t, db)
+	t.Cleanup(func() {
+		dropAllTables(t, db)
+		sqlDB.Close()
+	})
+	return db
+}
+
+func dropAllTables(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatalf("gagal membaca daftar tabel: %v", err)
+	}
+	db.Exec("SET FOREIGN_KEY_CHECKS = 0")
+	for _, table := range tables {
+		if err := db.Migrator().DropTable(table); err != nil {
+			t.Fatalf("gagal drop %s: %v", table, err)
+		}
+	}
+	db.Exec("SET FOREIGN_KEY_CHECKS = 1")
+}
+
+// --- Menyalakan Postgres lokal untuk test ---
+
+func setupPostgres() (stop func()) {
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		postgresDSN = dsn
+		return func() {}
+	}
+
+	if _, err := exec.LookPath("docker"); err == nil {
+		dsn, stop, err := startPostgresContainer()
+		if err == nil {
+			postgresDSN = dsn
+			return stop
+		}
+		log.Printf("[TEST] postgres via docker gagal: %v", err)
+	}
+
+	if _, err := exec.LookPath("pg_ctl"); err == nil {
+		dsn, stop, err := startPostgresBinary()
+		if err == nil {
+			postgresDSN = dsn
+			return stop
+		}
+		log.Printf("[TEST] postgres via pg_ctl gagal: %v", err)
+	}
+
+	return func() {}
+}
+
+func startPostgresContainer() (string, func(), error) {
+	out, err := exec.Command("docker", "run", "-d", "--rm",
+		"-e", "POSTGRES_PASSWORD=test",
+		"-e", "POSTGRES_DB=moneybot_test",
+		"-p", "127.0.0.1::5432",
+		"postgres:16-alpine").Output()
+	if err != nil {
+		return "", nil, err
+	}
+	id := strings.TrimSpace(string(out))
+	stop := func() { exec.Command("docker", "rm", "-f", id).Run() }
+
+	out, err = exec.Command("docker", "port", id, "5432/tcp").Output()
+	if err != nil {
+		stop()
+		return "", nil, err
+	}
+	// Contoh output: "127.0.0.1:49153"
+	hostPort := strings.TrimSpace(strings.Split(string(out), "\n")[0])
+	_, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		stop()
+		return "", nil, err
+	}
+
+	dsn := fmt.Sprintf("host=127.0.0.1 port=%s user=postgres password=test dbname=moneybot_test sslmode=disable", port)
+	if err := waitForPostgres(dsn, 60*time.Second); err != nil {
+		stop()
+		return "", nil, err
+	}
+	return dsn, stop, nil
+}
+
+func startPostgresBinary() (string, func(), error) {
+	dir, err := os.MkdirTemp("", "moneybot-pg-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	dataDir := filepath.Join(dir, "data")
+
+	if out, err := exec.Command("initdb", "-D", dataDir, "-U", "postgres", "-A", "trust").CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("initdb: %v: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1", port, dir)
+	if out, err := exec.Command("pg_ctl", "-D", dataDir, "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+	stop := func() {
+		exec.Command("pg_ctl", "-D", dataDir, "-m", "immediate", "stop").Run()
+		cleanup()
+	}
+
+	dsn := fmt.Sprintf("host=127.0.0.1 port=%d user=postgres dbname=postgres sslmode=disable", port)
+	if err := waitForPostgres(dsn, 30*time.Second); err != nil {
+		stop()
+		return "", nil, err
+	}
+	return dsn, stop, nil
+}
+
+func waitForPostgres(dsn string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		db, err := open(driverPostgres, dsn, &gorm.Config{Logger: logger.Discard})
+		if err == nil {
+			sqlDB, _ := db.DB()
+			err = sqlDB.Ping()
+			sqlDB.Close()
+			if err == nil {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("postgres belum siap setelah %s: %w", timeout, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Migration adalah satu perubahan skema yang punya versi.
+// ID diurutkan secara leksikografis, jadi pakai prefix angka: "0001_baseline".
+// Setelah dirilis, JANGAN ubah isi migration lama — buat migration baru.
+type Migration struct {
+	ID          string
+	Description string
+	Up          func(tx *gorm.DB) error
+	Down        func(tx *gorm.DB) error
+}
+
+// SchemaMigration mencatat migration yang sudah dijalankan
+type SchemaMigration struct {
+	ID        string    `gorm:"primaryKey;size:191"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationResult: hasil satu migration (beserta SQL yang dijalankan / akan dijalankan)
+type MigrationResult struct {
+	ID          string
+	Description string
+	SQL         []string
+}
+
+// MigrationState: status satu migration untuk perintah "migrate status"
+type MigrationState struct {
+	ID          string
+	Description string
+	AppliedAt   *time.Time // NULL = belum dijalankan
+}
+
+// errDryRun dipakai untuk membatalkan transaksi dry-run setelah SQL terkumpul
+var errDryRun = errors.New("dry run")
+
+var ErrDryRunUnsupported = errors.New("dry-run tidak didukung di MySQL (DDL tidak bisa di-rollback)")
+
+// MigrateUp menjalankan semua migration yang belum pernah dijalankan, urut berdasarkan ID.
+// dryRun = true: semua dijalankan dalam transaksi lalu di-rollback, hanya SQL-nya yang dikembalikan.
+func MigrateUp(db *gorm.DB, dryRun bool) ([]MigrationResult, error) {
+	return runMigrations(db, dryRun, func(tx *gorm.DB) ([]MigrationResult, error) {
+		applied, err := appliedMigrations(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		var results []MigrationResult
+		for _, m := range sortedMigrations() {
+			if _, done := applied[m.ID]; done {
+				continue
+			}
+			result, err := applyMigration(tx, m, m.Up, func(tx *gorm.DB) error {
+				return tx.Create(&SchemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	})
+}
+
+// MigrateDown membatalkan `steps` migration terakhir yang sudah dijalankan
+func MigrateDown(db *gorm.DB, steps int, dryRun bool) ([]MigrationResult, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("jumlah langkah rollback minimal 1, dapat %d", steps)
+	}
+
+	return runMigrations(db, dryRun, func(tx *gorm.DB) ([]MigrationResult, error) {
+		applied, err := appliedMigrations(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations := sortedMigrations()
+		var results []MigrationResult
+		for i := len(migrations) - 1; i >= 0 && len(results) < steps; i-- {
+			m := migrations[i]
+			if _, done := applied[m.ID]; !done {
+				continue
+			}
+			if m.Down == nil {
+				return results, fmt.Errorf("migration %s tidak bisa di-rollback (Down kosong)", m.ID)
+			}
+			result, err := applyMigration(tx, m, m.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&SchemaMigration{ID: m.ID}).Error
+			})
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	})
+}
+
+// MigrationStatus mengembalikan semua migration beserta waktu dijalankannya
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	// Database baru: tabel schema_migrations belum ada, berarti semua masih pending
+	applied := map[string]time.Time{}
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if applied, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	}
+
+	var states []MigrationState
+	for _, m := range sortedMigrations() {
+		state := MigrationState{ID: m.ID, Description: m.Description}
+		if appliedAt, done := applied[m.ID]; done {
+			t := appliedAt
+			state.AppliedAt = &t
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// Helper: Siapkan tabel schema_migrations, lalu jalankan fn (di-rollback kalau dry-run)
+func runMigrations(db *gorm.DB, dryRun bool, fn func(tx *gorm.DB) ([]MigrationResult, error)) ([]MigrationResult, error) {
+	if !dryRun {
+		if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+			return nil, fmt.Errorf("gagal menyiapkan schema_migrations: %w", err)
+		}
+		return fn(db)
+	}
+
+	// MySQL langsung commit setiap DDL, jadi rollback tidak bisa membatalkan perubahan skema
+	if db.Dialector.Name() == DriverMySQL {
+		return nil, ErrDryRunUnsupported
+	}
+
+	var results []MigrationResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+		var err error
+		results, err = fn(tx)
+		if err != nil {
+			return err
+		}
+		return errDryRun
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return results, err
+}
+
+// Helper: Jalankan satu arah migration (up/down) + catat di schema_migrations dalam satu transaksi
+func applyMigration(db *gorm.DB, m Migration, step func(tx *gorm.DB) error, record func(tx *gorm.DB) error) (MigrationResult, error) {
+	result := MigrationResult{ID: m.ID, Description: m.Description}
+	recorder := &sqlRecorder{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := step(tx.Session(&gorm.Session{Logger: recorder})); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+	result.SQL = recorder.statements
+	if err != nil {
+		return result, fmt.Errorf("migration %s gagal: %w", m.ID, err)
+	}
+	return result, nil
+}
+
+func appliedMigrations(db *gorm.DB) (map[string]time.Time, error) {
+	var rows []SchemaMigration
+	if err := db.Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.ID] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+func sortedMigrations() []Migration {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// sqlRecorder adalah logger GORM yang menyimpan SQL yang mengubah skema/data.
+// Query baca (SELECT / PRAGMA untuk cek tabel & kolom) dan savepoint transaksi tidak dicatat.
+type sqlRecorder struct {
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface      { return r }
+func (r *sqlRecorder) Info(context.Context, string, ...interface{})  {}
+func (r *sqlRecorder) Warn(context.Context, string, ...interface{})  {}
+func (r *sqlRecorder) Error(context.Context, string, ...interface{}) {}
+
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	upper := strings.ToUpper(strings.TrimSpace(sql))
+	for _, prefix := range []string{"SELECT", "PRAGMA", "SAVEPOINT", "RELEASE", "ROLLBACK"} {
+		if strings.HasPrefix(upper, prefix) {
+			return
+		}
+	}
+	if upper == "" {
+		return
+	}
+	r.statements = append(r.statements, sql)
+}
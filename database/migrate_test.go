@@ -0,0 +1,216 @@
+package database
+
+import (
+	"backend-gin/models"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Semua model yang tabelnya harus dibuat oleh migration
+var migratedModels = []interface{}{
+	&models.User{},
+	&models.Transaction{},
+	&models.PaymentLog{},
+	&models.PasswordReset{},
+	&models.AuditLog{},
+	&models.Wallet{},
+	&models.ExchangeRate{},
+	&models.BankStatement{},
+	&models.StatementEntry{},
+	&models.TransactionSplit{},
+	&models.Tag{},
+	&models.TransactionTag{},
+	&models.SavingsGoal{},
+}
+
+// Skema hasil migration harus cocok dengan struct di package models (tabel, kolom, index)
+func assertSchemaMatchesModels(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	m := db.Migrator()
+	for _, model := range migratedModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("gagal parse model %T: %v", model, err)
+		}
+		s := stmt.Schema
+		if !m.HasTable(model) {
+			t.Errorf("tabel %s tidak ada", s.Table)
+			continue
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !m.HasColumn(model, field.DBName) {
+				t.Errorf("kolom %s.%s tidak ada", s.Table, field.DBName)
+			}
+		}
+		for _, idx := range s.ParseIndexes() {
+			if !m.HasIndex(model, idx.Name) {
+				t.Errorf("index %s tidak ada", idx.Name)
+			}
+		}
+		for _, field := range s.Fields {
+			if field.Unique && !hasUniqueOn(t, db, s, field) {
+				t.Errorf("kolom %s.%s harus unique", s.Table, field.DBName)
+			}
+		}
+	}
+}
+
+func hasUniqueOn(t *testing.T, db *gorm.DB, s *schema.Schema, field *schema.Field) bool {
+	t.Helper()
+	columns, err := db.Migrator().ColumnTypes(s.Table)
+	if err != nil {
+		t.Fatalf("gagal baca kolom %s: %v", s.Table, err)
+	}
+	for _, col := range columns {
+		if col.Name() == field.DBName {
+			unique, ok := col.Unique()
+			return ok && unique
+		}
+	}
+	return false
+}
+
+func TestMigrateUpFromEmptyDatabase(t *testing.T) {
+	forEachDB(t, func(t *testing.T, tdb testDB) {
+		db := tdb.DB
+
+		results, err := MigrateUp(db, false)
+		if err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+		if len(results) != len(migrations) {
+			t.Fatalf("harusnya %d migration dijalankan, dapat %d", len(migrations), len(results))
+		}
+		assertSchemaMatchesModels(t, db)
+
+		var count int64
+		db.Model(&SchemaMigration{}).Count(&count)
+		if int(count) != len(migrations) {
+			t.Errorf("schema_migrations berisi %d baris, harusnya %d", count, len(migrations))
+		}
+
+		// Jalankan ulang: tidak ada yang pending
+		results, err = MigrateUp(db, false)
+		if err != nil {
+			t.Fatalf("MigrateUp kedua: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("MigrateUp kedua harusnya kosong, dapat %d", len(results))
+		}
+	})
+}
+
+func TestMigrateDownAndUpAgain(t *testing.T) {
+	forEachDB(t, func(t *testing.T, tdb testDB) {
+		db := tdb.DB
+		if _, err := MigrateUp(db, false); err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+
+		if _, err := MigrateDown(db, len(migrations), false); err != nil {
+			t.Fatalf("MigrateDown: %v", err)
+		}
+		for _, model := range migratedModels {
+			if db.Migrator().HasTable(model) {
+				t.Errorf("tabel %T masih ada setelah rollback semua", model)
+			}
+		}
+
+		if _, err := MigrateUp(db, false); err != nil {
+			t.Fatalf("MigrateUp ulang: %v", err)
+		}
+		assertSchemaMatchesModels(t, db)
+	})
+}
+
+func TestMigrateDownOneStep(t *testing.T) {
+	forEachDB(t, func(t *testing.T, tdb testDB) {
+		db := tdb.DB
+		if _, err := MigrateUp(db, false); err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+
+		results, err := MigrateDown(db, 1, false)
+		if err != nil {
+			t.Fatalf("MigrateDown: %v", err)
+		}
+		last := sortedMigrations()[len(migrations)-1]
+		if len(results) != 1 || results[0].ID != last.ID {
+			t.Fatalf("harusnya rollback %s, dapat %+v", last.ID, results)
+		}
+
+		states, err := MigrationStatus(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range states {
+			pending := s.AppliedAt == nil
+			if pending != (s.ID == last.ID) {
+				t.Errorf("status %s salah (pending=%v)", s.ID, pending)
+			}
+		}
+	})
+}
+
+func TestMigrateDryRunDoesNotChangeDatabase(t *testing.T) {
+	forEachDB(t, func(t *testing.T, tdb testDB) {
+		db := tdb.DB
+
+		results, err := MigrateUp(db, true)
+		if tdb.Driver == DriverMySQL {
+			if !errors.Is(err, ErrDryRunUnsupported) {
+				t.Fatalf("dry-run di MySQL harusnya ditolak, dapat %v", err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("MigrateUp dry-run: %v", err)
+		}
+		if len(results) != len(migrations) {
+			t.Fatalf("dry-run harusnya menampilkan %d migration, dapat %d", len(migrations), len(results))
+		}
+		if len(results[0].SQL) == 0 {
+			t.Error("dry-run harusnya mengembalikan SQL")
+		}
+
+		tables, err := db.Migrator().GetTables()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tables) != 0 {
+			t.Errorf("dry-run tidak boleh membuat tabel, ada: %v", tables)
+		}
+	})
+}
+
+// Database lama yang dibuat AutoMigrate (sebelum ada migration) harus bisa di-upgrade
+func TestMigrateUpOnLegacyAutoMigratedDatabase(t *testing.T) {
+	forEachDB(t, func(t *testing.T, tdb testDB) {
+		db := tdb.DB
+		if err := db.AutoMigrate(&userV1{}, &transactionV1{}, &paymentLogV1{}, &passwordResetV1{}, &auditLogV1{}); err != nil {
+			t.Fatalf("AutoMigrate skema lama: %v", err)
+		}
+		if err := db.Create(&userV1{Username: "lama"}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := MigrateUp(db, false); err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+		assertSchemaMatchesModels(t, db)
+
+		var user models.User
+		if err := db.Where("username = ?", "lama").First(&user).Error; err != nil {
+			t.Fatalf("data lama hilang: %v", err)
+		}
+		if user.Language != "id" {
+			t.Errorf("default language user lama harusnya 'id', dapat %q", user.Language)
+		}
+	})
+}
@@ -0,0 +1,763 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Daftar migration skema, urut berdasarkan ID.
+//
+// Setiap migration memakai struct "snapshot" sendiri (bukan struct di package models),
+// supaya perubahan model di masa depan tidak ikut mengubah hasil migration lama.
+// Semua langkah dicek dulu (HasTable / HasColumn / HasIndex), jadi database lama
+// yang dulu dibuat lewat AutoMigrate bisa langsung di-upgrade tanpa error.
+var migrations = []Migration{
+	{
+		ID:          "0001_baseline",
+		Description: "Tabel users, transactions, payment_logs",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &userV1{}, &transactionV1{}, &paymentLogV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&paymentLogV1{}, &transactionV1{}, &userV1{})
+		},
+	},
+	{
+		ID:          "0002_password_reset_and_sessions",
+		Description: "Kolom users.language & users.session_version, tabel password_resets",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &userSessionV2{}, "Language", "SessionVersion"); err != nil {
+				return err
+			}
+			return createTables(tx, &passwordResetV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&passwordResetV1{}); err != nil {
+				return err
+			}
+			return dropColumns(tx, &userSessionV2{}, "Language", "SessionVersion")
+		},
+	},
+	{
+		ID:          "0003_audit_logs",
+		Description: "Tabel audit_logs (append-only)",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &auditLogV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&auditLogV1{})
+		},
+	},
+	{
+		ID:          "0004_soft_delete",
+		Description: "Kolom deleted_at (tong sampah) di users & transactions",
+		Up: func(tx *gorm.DB) error {
+			if err := addIndexedColumn(tx, &userSoftDeleteV3{}, "DeletedAt"); err != nil {
+				return err
+			}
+			return addIndexedColumn(tx, &transactionSoftDeleteV2{}, "DeletedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexedColumn(tx, &transactionSoftDeleteV2{}, "DeletedAt"); err != nil {
+				return err
+			}
+			return dropIndexedColumn(tx, &userSoftDeleteV3{}, "DeletedAt")
+		},
+	},
+	{
+		ID:          "0005_multi_currency",
+		Description: "Mata uang per transaksi, dompet, mata uang utama user, tabel kurs",
+		Up: func(tx *gorm.DB) error {
+			// amount & daily_limit sudah BIGINT sejak awal (Go int = 64 bit), cukup tambah kolom mata uang
+			if err := createTables(tx, &walletV1{}, &exchangeRateV1{}); err != nil {
+				return err
+			}
+			if err := addColumns(tx, &transactionCurrencyV3{}, "Currency"); err != nil {
+				return err
+			}
+			if err := addIndexedColumn(tx, &transactionCurrencyV3{}, "WalletID"); err != nil {
+				return err
+			}
+			if err := addColumns(tx, &userCurrencyV4{}, "BaseCurrency"); err != nil {
+				return err
+			}
+			// Data lama semuanya Rupiah
+			if err := tx.Exec("UPDATE transactions SET currency = ? WHERE currency IS NULL OR currency = ''", "IDR").Error; err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE users SET base_currency = ? WHERE base_currency IS NULL OR base_currency = ''", "IDR").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropColumns(tx, &userCurrencyV4{}, "BaseCurrency"); err != nil {
+				return err
+			}
+			if err := dropIndexedColumn(tx, &transactionCurrencyV3{}, "WalletID"); err != nil {
+				return err
+			}
+			if err := dropColumns(tx, &transactionCurrencyV3{}, "Currency"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&exchangeRateV1{}, &walletV1{})
+		},
+	},
+	{
+		ID:          "0006_transaction_checks",
+		Description: "CHECK constraint tipe, nominal & kategori transaksi",
+		Up: func(tx *gorm.DB) error {
+			// Data lama yang melanggar harus dibereskan dulu, constraint tidak bisa dipasang di atasnya
+			var invalid int64
+			if err := tx.Raw("SELECT COUNT(*) FROM transactions WHERE " + InvalidTransactionCondition).Scan(&invalid).Error; err != nil {
+				return err
+			}
+			if invalid > 0 {
+				return fmt.Errorf("ada %d transaksi tidak valid, jalankan \"go run . repair-transactions --fix\" dulu", invalid)
+			}
+			return addCheckConstraints(tx, &transactionChecksV4{}, transactionChecks...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropCheckConstraints(tx, &transactionChecksV4{}, transactionChecks...)
+		},
+	},
+	{
+		ID:          "0007_transaction_user_created_index",
+		Description: "Index (user_id, created_at) untuk ringkasan & grafik",
+		Up: func(tx *gorm.DB) error {
+			return createIndexes(tx, &transactionUserCreatedV5{}, "idx_transactions_user_created")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropIndexes(tx, &transactionUserCreatedV5{}, "idx_transactions_user_created")
+		},
+	},
+	{
+		ID:          "0008_user_month_start_day",
+		Description: "Tanggal awal bulan laporan per user",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &userMonthStartV5{}, "MonthStartDay")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &userMonthStartV5{}, "MonthStartDay")
+		},
+	},
+	{
+		ID:          "0009_transaction_search",
+		Description: "Full-text search kategori & catatan transaksi",
+		Up:          createTransactionSearch,
+		Down:        dropTransactionSearch,
+	},
+	{
+		ID:          "0010_user_timezone",
+		Description: "Zona waktu per user",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &userTimezoneV6{}, "Timezone")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &userTimezoneV6{}, "Timezone")
+		},
+	},
+	{
+		ID:          "0011_bank_statements",
+		Description: "Rekening koran bank & mutasinya untuk rekonsiliasi",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &bankStatementV1{}, &statementEntryV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&statementEntryV1{}, &bankStatementV1{})
+		},
+	},
+	{
+		ID:          "0012_user_monthly_report",
+		Description: "Kirim laporan bulanan otomatis lewat Telegram",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &userMonthlyReportV7{}, "MonthlyReport", "LastMonthlyReport")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &userMonthlyReportV7{}, "MonthlyReport", "LastMonthlyReport")
+		},
+	},
+	{
+		ID:          "0013_user_delete_request",
+		Description: "Permintaan hapus akun oleh user dengan masa tunggu",
+		Up: func(tx *gorm.DB) error {
+			return addIndexedColumn(tx, &userDeleteRequestV8{}, "DeleteAfter")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropIndexedColumn(tx, &userDeleteRequestV8{}, "DeleteAfter")
+		},
+	},
+	{
+		ID:          "0014_transaction_splits",
+		Description: "Rincian transaksi split per kategori",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &transactionSplitV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&transactionSplitV1{})
+		},
+	},
+	{
+		ID:          "0015_transaction_tags",
+		Description: "Tag (hashtag) transaksi, many-to-many",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &tagV1{}, &transactionTagV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&transactionTagV1{}, &tagV1{})
+		},
+	},
+	{
+		ID:          "0016_savings_goals",
+		Description: "Target tabungan (dompet / kategori tertaut)",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &savingsGoalV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&savingsGoalV1{})
+		},
+	},
+	{
+		ID:          "0017_transaction_split_search",
+		Description: "Full-text search kategori & catatan baris rincian split",
+		Up:          createSplitSearch,
+		Down:        dropSplitSearch,
+	},
+	{
+		ID:          "0018_password_reset_ip",
+		Description: "IP peminta kode reset password",
+		Up: func(tx *gorm.DB) error {
+			return addIndexedColumn(tx, &passwordResetIPV2{}, "IP")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropIndexedColumn(tx, &passwordResetIPV2{}, "IP")
+		},
+	},
+}
+
+// InvalidTransactionCondition: kebalikan dari CHECK constraint transactions
+// (dipakai migration 0006 dan perintah repair-transactions)
+const InvalidTransactionCondition = "type IS NULL OR type NOT IN ('income', 'expense') OR " +
+	"amount IS NULL OR amount <= 0 OR category IS NULL OR TRIM(category) = ''"
+
+var transactionChecks = []string{"chk_transactions_type", "chk_transactions_amount", "chk_transactions_category"}
+
+// Full-text search beda di tiap database (lihat repository.transactionRepository.searchCondition):
+// SQLite = tabel FTS5 yang disinkronkan trigger, Postgres = index GIN tsvector, MySQL = index FULLTEXT
+const transactionSearchIndex = "idx_transactions_search"
+
+var sqliteTransactionSearch = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS transactions_fts USING fts5(category, note, content='transactions', content_rowid='id')`,
+	`CREATE TRIGGER IF NOT EXISTS transactions_fts_ai AFTER INSERT ON transactions BEGIN
+		INSERT INTO transactions_fts(rowid, category, note) VALUES (new.id, new.category, new.note);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS transactions_fts_ad AFTER DELETE ON transactions BEGIN
+		INSERT INTO transactions_fts(transactions_fts, rowid, category, note) VALUES ('delete', old.id, old.category, old.note);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS transactions_fts_au AFTER UPDATE OF category, note ON transactions BEGIN
+		INSERT INTO transactions_fts(transactions_fts, rowid, category, note) VALUES ('delete', old.id, old.category, old.note);
+		INSERT INTO transactions_fts(rowid, category, note) VALUES (new.id, new.category, new.note);
+	END`,
+	// Isi index dari data yang sudah ada
+	`INSERT INTO transactions_fts(transactions_fts) VALUES ('rebuild')`,
+}
+
+func createTransactionSearch(tx *gorm.DB) error {
+	switch tx.Dialector.Name() {
+	case DriverPostgres:
+		return tx.Exec("CREATE INDEX IF NOT EXISTS " + transactionSearchIndex + " ON transactions " +
+			"USING GIN (to_tsvector('simple', COALESCE(category, '') || ' ' || COALESCE(note, '')))").Error
+	case DriverMySQL:
+		if tx.Migrator().HasIndex("transactions", transactionSearchIndex) {
+			return nil
+		}
+		return tx.Exec("CREATE FULLTEXT INDEX " + transactionSearchIndex + " ON transactions (category, note)").Error
+	default:
+		for _, stmt := range sqliteTransactionSearch {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func dropTransactionSearch(tx *gorm.DB) error {
+	switch tx.Dialector.Name() {
+	case DriverPostgres:
+		return tx.Exec("DROP INDEX IF EXISTS " + transactionSearchIndex).Error
+	case DriverMySQL:
+		if !tx.Migrator().HasIndex("transactions", transactionSearchIndex) {
+			return nil
+		}
+		return tx.Exec("DROP INDEX " + transactionSearchIndex + " ON transactions").Error
+	default:
+		for _, stmt := range []string{
+			"DROP TRIGGER IF EXISTS transactions_fts_ai",
+			"DROP TRIGGER IF EXISTS transactions_fts_ad",
+			"DROP TRIGGER IF EXISTS transactions_fts_au",
+			"DROP TABLE IF EXISTS transactions_fts",
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Search baris rincian split, cara sama dengan transactionSearchIndex
+const splitSearchIndex = "idx_transaction_splits_search"
+
+var sqliteSplitSearch = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS transaction_splits_fts USING fts5(category, note, content='transaction_splits', content_rowid='id')`,
+	`CREATE TRIGGER IF NOT EXISTS transaction_splits_fts_ai AFTER INSERT ON transaction_splits BEGIN
+		INSERT INTO transaction_splits_fts(rowid, category, note) VALUES (new.id, new.category, new.note);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS transaction_splits_fts_ad AFTER DELETE ON transaction_splits BEGIN
+		INSERT INTO transaction_splits_fts(transaction_splits_fts, rowid, category, note) VALUES ('delete', old.id, old.category, old.note);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS transaction_splits_fts_au AFTER UPDATE OF category, note ON transaction_splits BEGIN
+		INSERT INTO transaction_splits_fts(transaction_splits_fts, rowid, category, note) VALUES ('delete', old.id, old.category, old.note);
+		INSERT INTO transaction_splits_fts(rowid, category, note) VALUES (new.id, new.category, new.note);
+	END`,
+	`INSERT INTO transaction_splits_fts(transaction_splits_fts) VALUES ('rebuild')`,
+}
+
+func createSplitSearch(tx *gorm.DB) error {
+	switch tx.Dialector.Name() {
+	case DriverPostgres:
+		return tx.Exec("CREATE INDEX IF NOT EXISTS " + splitSearchIndex + " ON transaction_splits " +
+			"USING GIN (to_tsvector('simple', COALESCE(category, '') || ' ' || COALESCE(note, '')))").Error
+	case DriverMySQL:
+		if tx.Migrator().HasIndex("transaction_splits", splitSearchIndex) {
+			return nil
+		}
+		return tx.Exec("CREATE FULLTEXT INDEX " + splitSearchIndex + " ON transaction_splits (category, note)").Error
+	default:
+		for _, stmt := range sqliteSplitSearch {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func dropSplitSearch(tx *gorm.DB) error {
+	switch tx.Dialector.Name() {
+	case DriverPostgres:
+		return tx.Exec("DROP INDEX IF EXISTS " + splitSearchIndex).Error
+	case DriverMySQL:
+		if !tx.Migrator().HasIndex("transaction_splits", splitSearchIndex) {
+			return nil
+		}
+		return tx.Exec("DROP INDEX " + splitSearchIndex + " ON transaction_splits").Error
+	default:
+		for _, stmt := range []string{
+			"DROP TRIGGER IF EXISTS transaction_splits_fts_ai",
+			"DROP TRIGGER IF EXISTS transaction_splits_fts_ad",
+			"DROP TRIGGER IF EXISTS transaction_splits_fts_au",
+			"DROP TABLE IF EXISTS transaction_splits_fts",
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// --- Helper langkah migration (semua aman dijalankan ulang) ---
+
+func createTables(tx *gorm.DB, models ...interface{}) error {
+	for _, model := range models {
+		if tx.Migrator().HasTable(model) {
+			continue
+		}
+		if err := tx.Migrator().CreateTable(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addColumns(tx *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if tx.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropColumns(tx *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if !tx.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := tx.Migrator().DropColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addIndexedColumn(tx *gorm.DB, model interface{}, field string) error {
+	if err := addColumns(tx, model, field); err != nil {
+		return err
+	}
+	if tx.Migrator().HasIndex(model, field) {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(model, field)
+}
+
+// addCheckConstraints: di SQLite constraint dipasang dengan membuat ulang tabel (index ikut hilang),
+// jadi index yang ada di snapshot dibuat lagi setelahnya
+func addCheckConstraints(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if tx.Migrator().HasConstraint(model, name) {
+			continue
+		}
+		if err := tx.Migrator().CreateConstraint(model, name); err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, model)
+}
+
+func dropCheckConstraints(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if !tx.Migrator().HasConstraint(model, name) {
+			continue
+		}
+		if err := tx.Migrator().DropConstraint(model, name); err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, model)
+}
+
+func createIndexes(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if tx.Migrator().HasIndex(model, name) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropIndexes(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if !tx.Migrator().HasIndex(model, name) {
+			continue
+		}
+		if err := tx.Migrator().DropIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreIndexes(tx *gorm.DB, model interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	for _, idx := range stmt.Schema.ParseIndexes() {
+		if tx.Migrator().HasIndex(model, idx.Name) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, idx.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropIndexedColumn(tx *gorm.DB, model interface{}, field string) error {
+	if tx.Migrator().HasIndex(model, field) {
+		if err := tx.Migrator().DropIndex(model, field); err != nil {
+			return err
+		}
+	}
+	return dropColumns(tx, model, field)
+}
+
+// --- Snapshot skema per versi ---
+
+// 0001: skema awal aplikasi
+type userV1 struct {
+	ID                uint   `gorm:"primaryKey"`
+	Username          string `gorm:"unique"`
+	Password          string
+	TelegramID        *int64 `gorm:"unique"`
+	LastTransactionAt *time.Time
+	Role              string
+	Status            string `gorm:"default:'trial'"`
+	TrialEndsAt       time.Time
+	DailyLimit        int
+	AlertMessage      string
+	CreatedAt         time.Time
+}
+
+func (userV1) TableName() string { return "users" }
+
+type transactionV1 struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint
+	Amount    int
+	Type      string
+	Category  string
+	Note      string
+	CreatedAt time.Time
+	User      userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (transactionV1) TableName() string { return "transactions" }
+
+type paymentLogV1 struct {
+	ID             uint `gorm:"primaryKey"`
+	UserID         uint
+	Username       string
+	ImagePath      string
+	DetectedBank   string
+	DetectedAmount int64
+	RawOCRResponse string
+	CreatedAt      time.Time
+	User           userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (paymentLogV1) TableName() string { return "payment_logs" }
+
+// 0002: bahasa bot, versi sesi, reset password
+type userSessionV2 struct {
+	Language       string `gorm:"default:'id'"`
+	SessionVersion uint   `gorm:"default:0"`
+}
+
+func (userSessionV2) TableName() string { return "users" }
+
+type passwordResetV1 struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"index"`
+	CodeHash  string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+	User      userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (passwordResetV1) TableName() string { return "password_resets" }
+
+// 0003: audit log
+type auditLogV1 struct {
+	ID            uint  `gorm:"primaryKey"`
+	ActorID       *uint `gorm:"index"`
+	ActorUsername string
+	ActorRole     string
+	Action        string `gorm:"index"`
+	TargetType    string `gorm:"index"`
+	TargetID      string `gorm:"index"`
+	Before        string
+	After         string
+	Changes       string
+	IP            string
+	UserAgent     string
+	CreatedAt     time.Time `gorm:"index"`
+}
+
+func (auditLogV1) TableName() string { return "audit_logs" }
+
+// 0004: soft delete
+type userSoftDeleteV3 struct {
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (userSoftDeleteV3) TableName() string { return "users" }
+
+type transactionSoftDeleteV2 struct {
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (transactionSoftDeleteV2) TableName() string { return "transactions" }
+
+// 0005: multi mata uang
+type transactionCurrencyV3 struct {
+	Currency string `gorm:"size:3;default:'IDR'"`
+	WalletID *uint  `gorm:"index"`
+}
+
+func (transactionCurrencyV3) TableName() string { return "transactions" }
+
+type userCurrencyV4 struct {
+	BaseCurrency string `gorm:"size:3;default:'IDR'"`
+}
+
+func (userCurrencyV4) TableName() string { return "users" }
+
+type walletV1 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	Name      string `gorm:"size:100"`
+	Currency  string `gorm:"size:3"`
+	CreatedAt time.Time
+	User      userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (walletV1) TableName() string { return "wallets" }
+
+type exchangeRateV1 struct {
+	ID            uint      `gorm:"primaryKey"`
+	BaseCurrency  string    `gorm:"size:3;uniqueIndex:idx_exchange_rates_pair_date"`
+	QuoteCurrency string    `gorm:"size:3;uniqueIndex:idx_exchange_rates_pair_date"`
+	Rate          string    `gorm:"size:40"`
+	EffectiveDate time.Time `gorm:"uniqueIndex:idx_exchange_rates_pair_date"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (exchangeRateV1) TableName() string { return "exchange_rates" }
+
+// 0006: CHECK constraint transaksi (index ikut dicatat untuk dibuat ulang di SQLite)
+type transactionChecksV4 struct {
+	Type      string         `gorm:"check:chk_transactions_type,type IN ('income', 'expense')"`
+	Amount    int64          `gorm:"check:chk_transactions_amount,amount > 0"`
+	Category  string         `gorm:"check:chk_transactions_category,TRIM(category) <> ''"`
+	WalletID  *uint          `gorm:"index"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (transactionChecksV4) TableName() string { return "transactions" }
+
+// 0007: index gabungan untuk query per user + rentang waktu
+type transactionUserCreatedV5 struct {
+	UserID    uint      `gorm:"index:idx_transactions_user_created,priority:1"`
+	CreatedAt time.Time `gorm:"index:idx_transactions_user_created,priority:2"`
+}
+
+func (transactionUserCreatedV5) TableName() string { return "transactions" }
+
+// 0008: awal bulan laporan (periode this_month / last_month / month+year)
+type userMonthStartV5 struct {
+	MonthStartDay int `gorm:"default:1"`
+}
+
+func (userMonthStartV5) TableName() string { return "users" }
+
+// 0010: zona waktu user
+type userTimezoneV6 struct {
+	Timezone string `gorm:"size:64;default:'Asia/Jakarta'"`
+}
+
+func (userTimezoneV6) TableName() string { return "users" }
+
+// 0011: rekening koran
+type bankStatementV1 struct {
+	ID            uint   `gorm:"primaryKey"`
+	UserID        uint   `gorm:"index"`
+	Bank          string `gorm:"size:20"`
+	AccountNumber string `gorm:"size:40"`
+	FileName      string `gorm:"size:255"`
+	Currency      string `gorm:"size:3"`
+	PeriodFrom    time.Time
+	PeriodTo      time.Time
+	CreatedAt     time.Time
+	User          userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (bankStatementV1) TableName() string { return "bank_statements" }
+
+type statementEntryV1 struct {
+	ID            uint `gorm:"primaryKey"`
+	StatementID   uint `gorm:"index"`
+	Line          int
+	PostedAt      time.Time
+	Description   string `gorm:"size:255"`
+	Type          string `gorm:"size:10"`
+	Amount        int64
+	Currency      string `gorm:"size:3"`
+	Balance       *int64
+	Pending       bool
+	TransactionID *uint           `gorm:"index"`
+	Statement     bankStatementV1 `gorm:"foreignKey:StatementID"`
+}
+
+func (statementEntryV1) TableName() string { return "statement_entries" }
+
+// 0012: laporan bulanan otomatis via bot
+type userMonthlyReportV7 struct {
+	MonthlyReport     bool   `gorm:"default:false"`
+	LastMonthlyReport string `gorm:"size:10"`
+}
+
+func (userMonthlyReportV7) TableName() string { return "users" }
+
+// 0013: hapus akun atas permintaan user sendiri
+type userDeleteRequestV8 struct {
+	DeleteAfter *time.Time `gorm:"index"`
+}
+
+func (userDeleteRequestV8) TableName() string { return "users" }
+
+// 0014: transaksi split
+type transactionSplitV1 struct {
+	ID            uint `gorm:"primaryKey"`
+	TransactionID uint `gorm:"index"`
+	Category      string
+	Note          string
+	Amount        int64
+	Transaction   transactionV1 `gorm:"foreignKey:TransactionID"`
+}
+
+func (transactionSplitV1) TableName() string { return "transaction_splits" }
+
+// 0015: tag transaksi
+type tagV1 struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"uniqueIndex:idx_tags_user_name,priority:1"`
+	Name   string `gorm:"size:50;uniqueIndex:idx_tags_user_name,priority:2"`
+	User   userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (tagV1) TableName() string { return "tags" }
+
+type transactionTagV1 struct {
+	TransactionID uint          `gorm:"primaryKey"`
+	TagID         uint          `gorm:"primaryKey;index"`
+	Transaction   transactionV1 `gorm:"foreignKey:TransactionID"`
+	Tag           tagV1         `gorm:"foreignKey:TagID"`
+}
+
+func (transactionTagV1) TableName() string { return "transaction_tags" }
+
+// 0016: target tabungan
+type savingsGoalV1 struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       uint   `gorm:"index"`
+	Name         string `gorm:"size:100"`
+	TargetAmount int64
+	Currency     string `gorm:"size:3"`
+	Deadline     *time.Time
+	WalletID     *uint  `gorm:"index"`
+	Category     string `gorm:"size:100"`
+	ReachedAt    *time.Time
+	CreatedAt    time.Time
+	User         userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (savingsGoalV1) TableName() string { return "savings_goals" }
+
+// 0018: batas permintaan kode reset per IP
+type passwordResetIPV2 struct {
+	IP string `gorm:"size:45;index"`
+}
+
+func (passwordResetIPV2) TableName() string { return "password_resets" }
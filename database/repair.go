@@ -0,0 +1,125 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Kategori pengganti untuk transaksi lama yang kategorinya kosong
+const RepairFallbackCategory = "Lainnya"
+
+// Tipe lama yang masih bisa ditebak artinya (huruf kecil, tanpa spasi)
+var transactionTypeAliases = map[string]string{
+	"income": "income", "pemasukan": "income", "masuk": "income", "in": "income",
+	"expense": "expense", "pengeluaran": "expense", "keluar": "expense", "out": "expense",
+}
+
+// TransactionIssue: satu transaksi yang melanggar aturan + perbaikan yang (akan) dilakukan.
+// Delete true berarti baris dihapus permanen (nominal 0 / kosong tidak bisa ditebak nilainya).
+type TransactionIssue struct {
+	ID       uint
+	UserID   uint
+	Problems []string
+	Updates  map[string]interface{}
+	Delete   bool
+}
+
+func (i TransactionIssue) String() string {
+	action := "hapus permanen"
+	if !i.Delete {
+		var changes []string
+		for _, col := range []string{"type", "amount", "category"} {
+			if v, ok := i.Updates[col]; ok {
+				changes = append(changes, fmt.Sprintf("%s=%v", col, v))
+			}
+		}
+		action = "ubah " + strings.Join(changes, ", ")
+	}
+	return fmt.Sprintf("#%d (user %d): %s -> %s", i.ID, i.UserID, strings.Join(i.Problems, "; "), action)
+}
+
+type rawTransaction struct {
+	ID       uint
+	UserID   uint
+	Type     *string
+	Amount   *int64
+	Category *string
+}
+
+// RepairTransactions mencari transaksi (termasuk yang di tong sampah) yang melanggar
+// CHECK constraint migration 0006. Kalau fix true, semua perbaikan dijalankan dalam satu DB transaction.
+//
+// Aturan perbaikan:
+//   - tipe dirapikan (huruf kecil, alias "pemasukan"/"pengeluaran"), tipe tak dikenal jadi expense
+//     (dulu grafik harian juga menghitungnya sebagai pengeluaran)
+//   - nominal negatif dibalik jadi positif, nominal 0 / kosong dihapus permanen
+//   - kategori kosong diisi "Lainnya"
+func RepairTransactions(db *gorm.DB, fix bool) ([]TransactionIssue, error) {
+	var rows []rawTransaction
+	err := db.Table("transactions").
+		Select("id, user_id, type, amount, category").
+		Where(InvalidTransactionCondition).
+		Order("id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]TransactionIssue, 0, len(rows))
+	for _, row := range rows {
+		issues = append(issues, diagnoseTransaction(row))
+	}
+	if !fix || len(issues) == 0 {
+		return issues, nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, issue := range issues {
+			var err error
+			if issue.Delete {
+				err = tx.Exec("DELETE FROM transactions WHERE id = ?", issue.ID).Error
+			} else {
+				err = tx.Table("transactions").Where("id = ?", issue.ID).Updates(issue.Updates).Error
+			}
+			if err != nil {
+				return fmt.Errorf("transaksi #%d: %w", issue.ID, err)
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+func diagnoseTransaction(row rawTransaction) TransactionIssue {
+	issue := TransactionIssue{ID: row.ID, UserID: row.UserID, Updates: map[string]interface{}{}}
+
+	rawType := ""
+	if row.Type != nil {
+		rawType = *row.Type
+	}
+	if rawType != "income" && rawType != "expense" {
+		fixed, known := transactionTypeAliases[strings.ToLower(strings.TrimSpace(rawType))]
+		if !known {
+			fixed = "expense"
+		}
+		issue.Problems = append(issue.Problems, fmt.Sprintf("tipe %q tidak valid", rawType))
+		issue.Updates["type"] = fixed
+	}
+
+	switch {
+	case row.Amount == nil || *row.Amount == 0:
+		issue.Problems = append(issue.Problems, "nominal kosong")
+		issue.Delete = true
+	case *row.Amount < 0:
+		issue.Problems = append(issue.Problems, fmt.Sprintf("nominal negatif (%d)", *row.Amount))
+		issue.Updates["amount"] = -*row.Amount
+	}
+
+	if row.Category == nil || strings.TrimSpace(*row.Category) == "" {
+		issue.Problems = append(issue.Problems, "kategori kosong")
+		issue.Updates["category"] = RepairFallbackCategory
+	}
+	return issue
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepairTransactionsBeforeCheckConstraints(t *testing.T) {
+	forEachDB(t, func(t *testing.T, tdb testDB) {
+		db := tdb.DB
+		// Skema lama tanpa constraint, berisi data yang dulu lolos validasi
+		if err := db.AutoMigrate(&userV1{}, &transactionV1{}); err != nil {
+			t.Fatalf("AutoMigrate skema lama: %v", err)
+		}
+		user := userV1{Username: "lama"}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		rows := []transactionV1{
+			{UserID: user.ID, Type: "income", Amount: 5000, Category: "Gaji"},      // valid
+			{UserID: user.ID, Type: "Pemasukan ", Amount: 7000, Category: "Bonus"}, // alias
+			{UserID: user.ID, Type: "transfer", Amount: -2500, Category: "Makan"},  // tipe asing + negatif
+			{UserID: user.ID, Type: "expense", Amount: 0, Category: "Makan"},       // nominal 0
+			{UserID: user.ID, Type: "expense", Amount: 1000, Category: "  "},       // kategori kosong
+		}
+		if err := db.Create(&rows).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		// Migration menolak jalan selama masih ada data tidak valid
+		_, err := MigrateUp(db, false)
+		if err == nil || !strings.Contains(err.Error(), "repair-transactions") {
+			t.Fatalf("MigrateUp dengan data tidak valid: err = %v, mau petunjuk repair-transactions", err)
+		}
+
+		// Tanpa --fix: hanya laporan
+		issues, err := RepairTransactions(db, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 4 {
+			t.Fatalf("jumlah masalah = %d, mau 4: %v", len(issues), issues)
+		}
+		var count int64
+		db.Table("transactions").Count(&count)
+		if count != 5 {
+			t.Fatalf("laporan saja tidak boleh mengubah data (%d baris)", count)
+		}
+
+		if _, err := RepairTransactions(db, true); err != nil {
+			t.Fatal(err)
+		}
+		var fixed []transactionV1
+		db.Order("id").Find(&fixed)
+		if len(fixed) != 4 {
+			t.Fatalf("setelah perbaikan %d baris, mau 4 (nominal 0 dihapus)", len(fixed))
+		}
+		if fixed[1].Type != "income" || fixed[2].Type != "expense" || fixed[2].Amount != 2500 || fixed[3].Category != RepairFallbackCategory {
+			t.Fatalf("hasil perbaikan = %+v", fixed)
+		}
+
+		if _, err := MigrateUp(db, false); err != nil {
+			t.Fatalf("MigrateUp setelah perbaikan: %v", err)
+		}
+		assertSchemaMatchesModels(t, db)
+
+		// Constraint aktif di database
+		for _, bad := range []map[string]interface{}{
+			{"user_id": user.ID, "type": "transfer", "amount": 1, "category": "Makan"},
+			{"user_id": user.ID, "type": "expense", "amount": -1, "category": "Makan"},
+			{"user_id": user.ID, "type": "expense", "amount": 1, "category": ""},
+		} {
+			if err := db.Table("transactions").Create(bad).Error; err == nil {
+				t.Errorf("insert %v harusnya ditolak CHECK constraint", bad)
+			}
+		}
+	})
+}
@@ -1,21 +1,80 @@
 package database
 
 import (
-	"backend-gin/models"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var DB *gorm.DB
+// Driver database yang didukung (DB_DRIVER di .env)
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
 
-func ConnectDatabase() {
-	database, err := gorm.Open(sqlite.Open("finance.db"), &gorm.Config{})
+// Lokasi file SQLite default, bisa diganti lewat DB_PATH di .env
+const DefaultDBPath = "finance.db"
 
-	if err != nil {
-		panic("Gagal konek ke database: " + err.Error())
+// DBDriver membaca DB_DRIVER (default sqlite)
+func DBDriver() string {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("DB_DRIVER")))
+	switch driver {
+	case "", "sqlite3":
+		return DriverSQLite
+	case "postgresql", "pgx":
+		return DriverPostgres
+	}
+	return driver
+}
+
+// DBDSN membaca DB_DSN. Untuk SQLite boleh kosong (pakai DB_PATH / finance.db).
+func DBDSN() string {
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		return dsn
+	}
+	if path := os.Getenv("DB_PATH"); path != "" {
+		return path
+	}
+	return DefaultDBPath
+}
+
+// Open membuka koneksi GORM sesuai driver & DSN
+//
+//	sqlite:   finance.db
+//	postgres: host=localhost user=moneybot password=secret dbname=moneybot port=5432 sslmode=disable
+//	mysql:    moneybot:secret@tcp(127.0.0.1:3306)/moneybot?charset=utf8mb4&parseTime=True&loc=Local
+func Open(driver, dsn string, config *gorm.Config) (*gorm.DB, error) {
+	if config == nil {
+		config = &gorm.Config{}
+	}
+
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverSQLite:
+		dialector = sqlite.Open(dsn)
+	case DriverPostgres:
+		dialector = postgres.Open(dsn)
+	case DriverMySQL:
+		dialector = mysql.Open(dsn)
+	default:
+		return nil, fmt.Errorf("DB_DRIVER %q tidak didukung (pilih: sqlite, postgres, mysql)", driver)
 	}
 
-database.AutoMigrate(&models.User{}, &models.Transaction{}, &models.PaymentLog{})
-	DB = database
-}
\ No newline at end of file
+	return gorm.Open(dialector, config)
+}
+
+// ConnectDatabase membuka koneksi sesuai DB_DRIVER / DB_DSN. Skema diurus oleh migration (lihat MigrateUp).
+// Koneksi dikembalikan ke pemanggil (main) untuk di-inject ke repository, tidak disimpan global.
+func ConnectDatabase() (*gorm.DB, error) {
+	db, err := Open(DBDriver(), DBDSN(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("gagal konek ke database: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"backend-gin/database/dbtest"
+	"os"
+	"testing"
+)
+
+// Database test (SQLite selalu, Postgres / MySQL kalau tersedia) disiapkan package dbtest
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m, Open))
+}
+
+type testDB = dbtest.DB
+
+// forEachDB menjalankan fn untuk setiap database yang tersedia, masing-masing dengan skema kosong
+func forEachDB(t *testing.T, fn func(t *testing.T, tdb testDB)) {
+	t.Helper()
+	dbtest.ForEach(t, fn)
+}
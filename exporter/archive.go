@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"archive/zip"
+	"backend-gin/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archive: semua data yang disimpan tentang satu akun, untuk diunduh pemiliknya
+type Archive struct {
+	User         models.User
+	Transactions []models.Transaction // termasuk yang ada di tong sampah
+	Wallets      []models.Wallet
+	Goals        []models.SavingsGoal
+	Statements   []ArchiveStatement
+	PaymentLogs  []models.PaymentLog
+	AuditLogs    []models.AuditLog // aksi yang dilakukan user sendiri
+	GeneratedAt  time.Time
+}
+
+// ArchiveStatement: rekening koran beserta mutasinya
+type ArchiveStatement struct {
+	models.BankStatement
+	Entries []models.StatementEntry `json:"entries"`
+}
+
+// ArchiveFileName: nama file unduhan, mis. Data_Akun_budi_20251130.zip
+func ArchiveFileName(username string, now time.Time) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '"' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, username)
+	return fmt.Sprintf("Data_Akun_%s_%s.zip", name, now.Format("20060102"))
+}
+
+// WriteArchive menulis arsip ZIP: file JSON per jenis data + gambar bukti pembayaran.
+// Gambar yang sudah tidak ada di disk dicatat di README.txt.
+func WriteArchive(w io.Writer, a *Archive) error {
+	u := a.User
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", map[string]interface{}{
+			"id":                  u.ID,
+			"username":            u.Username,
+			"role":                u.Role,
+			"status":              u.Status,
+			"telegram_id":         u.TelegramID,
+			"trial_ends_at":       u.TrialEndsAt,
+			"last_transaction_at": u.LastTransactionAt,
+			"created_at":          u.CreatedAt,
+			"delete_after":        u.DeleteAfter,
+		}},
+		{"settings.json", map[string]interface{}{
+			"daily_limit":     u.DailyLimit,
+			"alert_message":   u.AlertMessage,
+			"base_currency":   u.BaseCurrency,
+			"month_start_day": u.MonthStartDay,
+			"timezone":        u.Timezone,
+			"language":        u.Language,
+			"monthly_report":  u.MonthlyReport,
+		}},
+		{"transactions.json", nonNil(a.Transactions)},
+		{"wallets.json", nonNil(a.Wallets)},
+		{"goals.json", nonNil(a.Goals)},
+		{"statements.json", nonNil(a.Statements)},
+		{"payment_logs.json", nonNil(a.PaymentLogs)},
+		{"audit_logs.json", nonNil(a.AuditLogs)},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	for _, log := range a.PaymentLogs {
+		if log.ImagePath == "" {
+			continue
+		}
+		name := fmt.Sprintf("payment_images/%d_%s", log.ID, filepath.Base(log.ImagePath))
+		if err := copyFile(zw, name, log.ImagePath); errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, name)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	readme, err := zw.Create("README.txt")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(readme, archiveReadme(a, missing)); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func copyFile(zw *zip.Writer, name, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, src)
+	return err
+}
+
+func archiveReadme(a *Archive, missing []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Arsip data akun %s, dibuat %s\n\n", a.User.Username, a.GeneratedAt.Format("02-01-2006 15:04 MST"))
+	b.WriteString("profile.json       Profil akun\n")
+	b.WriteString("settings.json      Pengaturan (limit harian, mata uang, zona waktu, bahasa)\n")
+	fmt.Fprintf(&b, "transactions.json  %d transaksi, termasuk yang ada di tong sampah (nominal dalam minor unit)\n", len(a.Transactions))
+	fmt.Fprintf(&b, "wallets.json       %d dompet\n", len(a.Wallets))
+	fmt.Fprintf(&b, "goals.json         %d target tabungan (isinya dihitung dari transaksi)\n", len(a.Goals))
+	fmt.Fprintf(&b, "statements.json    %d rekening koran beserta mutasinya\n", len(a.Statements))
+	fmt.Fprintf(&b, "payment_logs.json  %d bukti pembayaran, gambarnya di folder payment_images/\n", len(a.PaymentLogs))
+	fmt.Fprintf(&b, "audit_logs.json    %d aktivitas akun\n", len(a.AuditLogs))
+	if len(missing) > 0 {
+		b.WriteString("\nGambar yang sudah tidak tersedia di server:\n")
+		for _, name := range missing {
+			b.WriteString("- " + name + "\n")
+		}
+	}
+	return b.String()
+}
+
+// nonNil: slice kosong ditulis sebagai [] (bukan null)
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,46 @@
+package exporter
+
+import (
+	"backend-gin/money"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// writeCSV: kolom sama dengan XLSX, nominal desimal bertitik ("12.50") supaya bisa diimport ulang
+func writeCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns(r)); err != nil {
+		return err
+	}
+	err := r.eachRow(func(i int, t Row) error {
+		return cw.Write([]string{
+			strconv.Itoa(i + 1),
+			t.Date.Format("2006-01-02"),
+			t.Date.Format("15:04"),
+			strings.ToUpper(string(t.Type)),
+			CSVCell(t.Category),
+			CSVCell(t.Note),
+			t.Currency,
+			money.Decimal(t.Amount, t.Currency),
+			money.Decimal(t.Base, r.Currency),
+			CSVCell(t.TagList()),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CSVCell menetralkan teks bebas dari user supaya tidak dijalankan spreadsheet sebagai rumus
+// (CSV injection): sel berawalan =, +, -, @, tab atau CR diberi awalan '.
+// Kolom angka dari aplikasi sendiri (nominal, ID, tanggal) tidak perlu lewat sini.
+func CSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
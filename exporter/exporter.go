@@ -0,0 +1,159 @@
+// Package exporter menulis laporan transaksi ke berbagai format file (XLSX, CSV, JSON lines, ODS, PDF)
+// dan arsip ZIP berisi semua data satu akun.
+// Data laporan (filter periode, konversi kurs, ringkasan) disiapkan di services, di sini hanya tata letak.
+package exporter
+
+import (
+	"backend-gin/models"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format file laporan
+type Format string
+
+const (
+	FormatXLSX  Format = "xlsx"
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatODS   Format = "ods"
+	FormatPDF   Format = "pdf"
+)
+
+// SheetName: nama sheet / judul laporan (dipakai juga importer untuk mengenali file hasil export)
+const SheetName = "Laporan Keuangan"
+
+var ErrFormatUnknown = errors.New("format laporan tidak dikenal")
+
+// ParseFormat: nilai query ?format= (kosong = xlsx, "json" / "ndjson" = JSON lines)
+func ParseFormat(value string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(value))); f {
+	case "":
+		return FormatXLSX, nil
+	case "json", "ndjson":
+		return FormatJSONL, nil
+	case FormatXLSX, FormatCSV, FormatJSONL, FormatODS, FormatPDF:
+		return f, nil
+	}
+	return "", ErrFormatUnknown
+}
+
+// ContentType untuk header response
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatODS:
+		return "application/vnd.oasis.opendocument.spreadsheet"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// FileName: nama file unduhan, mis. Laporan_Syukur_20251130.pdf
+func (f Format) FileName(now time.Time) string {
+	return fmt.Sprintf("Laporan_Syukur_%s.%s", now.Format("20060102"), f)
+}
+
+// Row: satu transaksi di laporan
+type Row struct {
+	ID       uint
+	Date     time.Time // sudah di zona waktu user
+	Type     models.TransactionType
+	Category string
+	Note     string
+	Currency string
+	Amount   int64 // minor unit, mata uang asli
+	Base     int64 // minor unit, hasil konversi ke Report.Currency
+	WalletID *uint
+	Split    bool     // Baris rincian transaksi split (ID = transaksi induk, bisa muncul beberapa kali)
+	Tags     []string // Nama tag tanpa '#'
+}
+
+// TagList: tag dipisah koma untuk kolom "Tag"
+func (r Row) TagList() string {
+	return strings.Join(r.Tags, ", ")
+}
+
+// RowIterator: sumber baris laporan, dibaca bertahap saat file ditulis supaya laporan besar tidak
+// dimuat sekaligus ke memori. each dipanggil per baris berurutan; error dari each atau dari
+// pembacaan data menghentikan iterasi dan dikembalikan.
+type RowIterator func(each func(Row) error) error
+
+// CategoryTotal: total satu kategori dalam mata uang dasar
+type CategoryTotal struct {
+	Type     models.TransactionType
+	Category string
+	Count    int
+	Total    int64
+}
+
+// Report: isi laporan satu periode
+type Report struct {
+	Owner       string
+	Currency    string    // mata uang dasar user
+	From        time.Time // [From, To), zero = tanpa batas
+	To          time.Time
+	Location    *time.Location // zona waktu user
+	GeneratedAt time.Time
+	Rows        RowIterator // terbaru dulu, nil = tanpa transaksi
+	Count       int         // Jumlah transaksi: transaksi split dihitung sekali walau Rows berisi satu baris per rincian
+	Income      int64
+	Expense     int64
+	Categories  []CategoryTotal // urut per tipe lalu total terbesar
+}
+
+// eachRow memanggil fn untuk tiap baris beserta nomor urutnya (mulai 0)
+func (r *Report) eachRow(fn func(i int, row Row) error) error {
+	if r.Rows == nil {
+		return nil
+	}
+	i := 0
+	return r.Rows(func(row Row) error {
+		err := fn(i, row)
+		i++
+		return err
+	})
+}
+
+// PeriodLabel: "01-11-2025 s/d 30-11-2025", "sejak ..." / "sampai ..." atau "Semua waktu"
+func (r *Report) PeriodLabel() string {
+	const layout = "02-01-2006"
+	switch {
+	case !r.From.IsZero() && !r.To.IsZero():
+		return r.From.Format(layout) + " s/d " + r.To.AddDate(0, 0, -1).Format(layout)
+	case !r.From.IsZero():
+		return "Sejak " + r.From.Format(layout)
+	case !r.To.IsZero():
+		return "Sampai " + r.To.AddDate(0, 0, -1).Format(layout)
+	}
+	return "Semua waktu"
+}
+
+// Write menulis laporan dalam format f
+func Write(w io.Writer, f Format, r *Report) error {
+	switch f {
+	case FormatXLSX:
+		return writeXLSX(w, r)
+	case FormatCSV:
+		return writeCSV(w, r)
+	case FormatJSONL:
+		return writeJSONL(w, r)
+	case FormatODS:
+		return writeODS(w, r)
+	case FormatPDF:
+		return writePDF(w, r)
+	}
+	return ErrFormatUnknown
+}
+
+// columns: judul kolom tabel transaksi (XLSX, CSV, ODS); sama dengan yang dikenali importer
+func columns(r *Report) []string {
+	return []string{"No", "Tanggal", "Jam", "Tipe", "Kategori", "Catatan", "Mata Uang", "Jumlah", fmt.Sprintf("Jumlah (%s)", r.Currency), "Tag"}
+}
@@ -0,0 +1,294 @@
+package exporter
+
+import (
+	"archive/zip"
+	"backend-gin/models"
+	"bytes"
+	"compress/zlib"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var wib = time.FixedZone("WIB", 7*3600)
+
+// rowsOf: RowIterator dari slice (perubahan isi slice sebelum Write ikut terbaca)
+func rowsOf(rows []Row) RowIterator {
+	return func(each func(Row) error) error {
+		for _, row := range rows {
+			if err := each(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func testReport(n int) (*Report, []Row) {
+	r := &Report{
+		Owner:       "budi",
+		Currency:    "IDR",
+		From:        time.Date(2025, 3, 1, 0, 0, 0, 0, wib),
+		To:          time.Date(2025, 4, 1, 0, 0, 0, 0, wib),
+		Location:    wib,
+		GeneratedAt: time.Date(2025, 4, 1, 9, 0, 0, 0, wib),
+	}
+	var rows []Row
+	for i := 0; i < n; i++ {
+		row := Row{
+			ID: uint(i + 1), Date: time.Date(2025, 3, 1+i%28, 12, 0, 0, 0, wib), Type: models.TypeExpense,
+			Category: "Makan", Note: "Kopi (susu) & roti \U0001F600", Currency: "USD", Amount: 250, Base: 40000,
+			Tags: []string{"kantor", "liburan-bali"},
+		}
+		rows = append(rows, row)
+		r.Count++
+		r.Expense += row.Base
+	}
+	r.Rows = rowsOf(rows)
+	r.Categories = []CategoryTotal{{Type: models.TypeExpense, Category: "Makan", Count: n, Total: r.Expense}}
+	return r, rows
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"": FormatXLSX, "PDF": FormatPDF, "json": FormatJSONL, "ods": FormatODS} {
+		if got, err := ParseFormat(input); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; mau %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("docx"); err != ErrFormatUnknown {
+		t.Errorf("ParseFormat(docx) err = %v", err)
+	}
+	if r, _ := testReport(0); r.PeriodLabel() != "01-03-2025 s/d 31-03-2025" {
+		t.Errorf("PeriodLabel = %q", r.PeriodLabel())
+	}
+}
+
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	r, rows := testReport(1)
+	rows[0].Category = "@SUM(A1:A9)"
+	rows[0].Note = `=HYPERLINK("http://evil.example","klik")`
+	rows[0].Tags = nil
+
+	var out bytes.Buffer
+	if err := Write(&out, FormatCSV, r); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := records[1]
+	if row[4] != "'@SUM(A1:A9)" || row[5] != `'=HYPERLINK("http://evil.example","klik")` || row[7] != "2.50" {
+		t.Errorf("baris CSV = %q", row)
+	}
+
+	for input, want := range map[string]string{"-5": "'-5", "+62": "'+62", "\tx": "'\tx", "Makan - siang": "Makan - siang", "": ""} {
+		if got := CSVCell(input); got != want {
+			t.Errorf("CSVCell(%q) = %q, mau %q", input, got, want)
+		}
+	}
+}
+
+func TestWriteODS(t *testing.T) {
+	var out bytes.Buffer
+	r, _ := testReport(2)
+	if err := Write(&out, FormatODS, r); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// mimetype wajib file pertama tanpa kompresi
+	if f := zr.File[0]; f.Name != "mimetype" || f.Method != zip.Store {
+		t.Fatalf("file pertama = %s (method %d)", f.Name, f.Method)
+	}
+	f, err := zr.Open("content.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(f)
+	for _, want := range []string{
+		`office:value="2.50"`, `office:value="40000"`, `office:date-value="2025-03-01"`,
+		"Kopi (susu) &amp; roti", `table:name="Laporan Keuangan"`, "Jumlah (IDR)",
+		"<text:p>kantor, liburan-bali</text:p>",
+	} {
+		if !bytes.Contains(content, []byte(want)) {
+			t.Errorf("content.xml tidak berisi %s", want)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var out bytes.Buffer
+	r, _ := testReport(120)
+	if err := Write(&out, FormatPDF, r); err != nil {
+		t.Fatal(err)
+	}
+	pdf := out.Bytes()
+
+	// Offset di tabel xref harus menunjuk ke awal tiap objek
+	start := bytes.LastIndex(pdf, []byte("startxref\n"))
+	xref, _ := strconv.Atoi(strings.Fields(string(pdf[start+len("startxref\n"):]))[0])
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Fatalf("xref objek %d menunjuk ke %q", i+1, pdf[offset:offset+10])
+		}
+	}
+
+	// 120 transaksi tidak muat satu halaman: judul tabel diulang di halaman berikutnya
+	pages := bytes.Count(pdf, []byte("/Type /Page /Parent"))
+	if pages < 3 {
+		t.Fatalf("jumlah halaman = %d, mau minimal 3", pages)
+	}
+	var text strings.Builder
+	for _, m := range regexp.MustCompile(`(?s)/FlateDecode >>\nstream\n(.*?)\nendstream`).FindAllSubmatch(pdf, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		page, _ := io.ReadAll(zr)
+		text.Write(page)
+	}
+	for _, want := range []string{
+		"(Laporan Keuangan) Tj", "(Rp 4800000) Tj", "(Kopi \\(susu\\) & roti ?) Tj",
+		fmt.Sprintf("(Halaman %d dari %d) Tj", pages, pages),
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("isi halaman tidak berisi %s", want)
+		}
+	}
+	if got := strings.Count(text.String(), "(Catatan) Tj"); got != pages {
+		t.Errorf("judul kolom transaksi muncul %d kali, mau %d (sekali per halaman)", got, pages)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	r, rows := testReport(3)
+	r.Rows = rowsOf(append([]Row{{
+		ID: 9, Date: time.Date(2025, 3, 5, 9, 30, 0, 0, wib), Type: models.TypeIncome,
+		Category: "Gaji", Currency: "IDR", Amount: 5000000, Base: 5000000,
+	}}, rows...))
+	r.Income = 5000000
+	r.Count++
+	r.Categories = append([]CategoryTotal{{Type: models.TypeIncome, Category: "Gaji", Count: 1, Total: 5000000}}, r.Categories...)
+
+	var out bytes.Buffer
+	if err := Write(&out, FormatXLSX, r); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if got := strings.Join(f.GetSheetList(), ","); got != "Laporan Keuangan,Ringkasan,Per Kategori,Harian" {
+		t.Fatalf("sheet = %s", got)
+	}
+	// Sheet transaksi: tanggal asli Excel, nominal berformat mata uang
+	if got, _ := f.GetCellValue(SheetName, "B2"); got != "05-03-2025" {
+		t.Errorf("tanggal = %q", got)
+	}
+	if got, _ := f.GetCellValue(SheetName, "H3"); got != `US$ 2.50` {
+		t.Errorf("nominal USD = %q", got)
+	}
+	if got, _ := f.GetCellValue(SheetName, "I2"); got != "Rp 5,000,000" {
+		t.Errorf("nominal IDR = %q", got)
+	}
+
+	// Ringkasan: rumus + nilai cache
+	for cell, want := range map[string][2]string{
+		"B8":  {`SUMIFS('Laporan Keuangan'!$I:$I,'Laporan Keuangan'!$D:$D,"INCOME")`, "5000000"},
+		"B9":  {`SUMIFS('Laporan Keuangan'!$I:$I,'Laporan Keuangan'!$D:$D,"EXPENSE")`, "120000"},
+		"B10": {"B8-B9", "4880000"},
+		"B11": {"", "4"},
+	} {
+		formula, _ := f.GetCellFormula(sheetSummary, cell)
+		value, _ := f.GetCellValue(sheetSummary, cell, excelize.Options{RawCellValue: true})
+		if formula != want[0] || value != want[1] {
+			t.Errorf("%s = %q (%s), mau %q (%s)", cell, formula, value, want[0], want[1])
+		}
+	}
+	pivot, _ := f.GetRows(sheetSummary, excelize.Options{RawCellValue: true})
+	if got := strings.Join(pivot[13], ","); got != "Gaji,5000000,0,5000000" {
+		t.Errorf("pivot Gaji = %s", got)
+	}
+	if got := strings.Join(pivot[15], ","); got != "Total,5000000,120000,4880000" {
+		t.Errorf("pivot total = %s", got)
+	}
+
+	categories, _ := f.GetRows(sheetCategory, excelize.Options{RawCellValue: true})
+	if got := strings.Join(categories[2], ","); got != "EXPENSE,Makan,3,120000,40000,1" {
+		t.Errorf("per kategori = %s", got)
+	}
+
+	// Harian: 1-5 Maret termasuk hari kosong, plus grafik
+	daily, _ := f.GetRows(sheetDaily)
+	if len(daily) != 6 || daily[1][2] != "Rp 40,000" || daily[4][2] != "Rp 0" {
+		t.Errorf("harian = %v", daily)
+	}
+	if _, err := f.GetCellFormula(sheetDaily, "C2"); err != nil {
+		t.Error(err)
+	}
+	zr, _ := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	chart, err := zr.Open("xl/charts/chart1.xml")
+	if err != nil {
+		t.Fatal("grafik harian tidak ada")
+	}
+	content, _ := io.ReadAll(chart)
+	// Seri pengeluaran (kolom C) untuk semua tanggal 1-5 Maret
+	for _, want := range []string{"'Harian'!$C$1", "'Harian'!$A$2:$A$6", "'Harian'!$C$2:$C$6", "Pengeluaran Harian (IDR)"} {
+		if !strings.Contains(html.UnescapeString(string(content)), want) {
+			t.Errorf("grafik tidak berisi %s", want)
+		}
+	}
+
+	// Filter otomatis di sheet transaksi & per kategori, sepanjang semua baris
+	filters := map[string]string{}
+	for _, name := range f.GetDefinedName() {
+		if name.Name == "_xlnm._FilterDatabase" {
+			filters[name.Scope] = name.RefersTo
+		}
+	}
+	for sheet, want := range map[string]string{
+		SheetName:     "'Laporan Keuangan'!$A$1:$J$5",
+		sheetCategory: "'Per Kategori'!$A$1:$F$3",
+	} {
+		if filters[sheet] != want {
+			t.Errorf("autofilter %s = %q, mau %q", sheet, filters[sheet], want)
+		}
+	}
+
+	// Judul kolom dibekukan, kecuali di ringkasan
+	for _, sheet := range []string{SheetName, sheetCategory, sheetDaily, sheetSummary} {
+		panes, err := f.GetPanes(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frozen := panes.Freeze && panes.YSplit == 1 && panes.TopLeftCell == "A2"
+		if frozen != (sheet != sheetSummary) {
+			t.Errorf("panes %s = %+v", sheet, panes)
+		}
+	}
+}
+
+// go test ./exporter -bench WriteXLSX -benchmem: laporan 50.000 transaksi
+func BenchmarkWriteXLSX(b *testing.B) {
+	r, _ := testReport(50000)
+	for i := 0; i < b.N; i++ {
+		if err := Write(io.Discard, FormatXLSX, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonRow: satu baris JSON lines, nominal minor unit seperti response API
+type jsonRow struct {
+	ID           uint      `json:"id"`
+	Date         time.Time `json:"date"`
+	Type         string    `json:"type"`
+	Category     string    `json:"category"`
+	Note         string    `json:"note"`
+	Currency     string    `json:"currency"`
+	Amount       int64     `json:"amount"`
+	BaseCurrency string    `json:"base_currency"`
+	BaseAmount   int64     `json:"base_amount"`
+	WalletID     *uint     `json:"wallet_id"`
+	Split        bool      `json:"split,omitempty"` // Baris rincian split, id = transaksi induk
+	Tags         []string  `json:"tags,omitempty"`
+}
+
+// writeJSONL: satu objek JSON per baris (terbaru dulu), cocok untuk diolah skrip
+func writeJSONL(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	return r.eachRow(func(_ int, t Row) error {
+		return enc.Encode(jsonRow{
+			ID:           t.ID,
+			Date:         t.Date,
+			Type:         string(t.Type),
+			Category:     t.Category,
+			Note:         t.Note,
+			Currency:     t.Currency,
+			Amount:       t.Amount,
+			BaseCurrency: r.Currency,
+			BaseAmount:   t.Base,
+			WalletID:     t.WalletID,
+			Split:        t.Split,
+			Tags:         t.Tags,
+		})
+	})
+}
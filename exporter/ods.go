@@ -0,0 +1,134 @@
+package exporter
+
+import (
+	"archive/zip"
+	"backend-gin/models"
+	"backend-gin/money"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+const odsManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">
+ <manifest:file-entry manifest:full-path="/" manifest:media-type="` + odsMimeType + `"/>
+ <manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>
+`
+
+const odsContentHead = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+	` xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"` +
+	` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"` +
+	` xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"` +
+	` xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" office:version="1.2">
+<office:automatic-styles>
+ <style:style style:name="co1" style:family="table-column"><style:table-column-properties style:column-width="1.2cm"/></style:style>
+ <style:style style:name="co2" style:family="table-column"><style:table-column-properties style:column-width="2.6cm"/></style:style>
+ <style:style style:name="co3" style:family="table-column"><style:table-column-properties style:column-width="5.5cm"/></style:style>
+ <style:style style:name="co4" style:family="table-column"><style:table-column-properties style:column-width="3.5cm"/></style:style>
+ <style:style style:name="head" style:family="table-cell"><style:table-cell-properties fo:background-color="#4F46E5"/><style:text-properties fo:font-weight="bold" fo:color="#FFFFFF"/></style:style>
+ <style:style style:name="income" style:family="table-cell"><style:text-properties fo:color="#10B981"/></style:style>
+ <style:style style:name="expense" style:family="table-cell"><style:text-properties fo:color="#EF4444"/></style:style>
+</office:automatic-styles>
+<office:body><office:spreadsheet>
+`
+
+const odsContentTail = "</office:spreadsheet></office:body></office:document-content>\n"
+
+// writeODS: OpenDocument spreadsheet (LibreOffice / Google Sheets), satu sheet dengan kolom sama seperti XLSX.
+// Nominal ditulis sebagai angka desimal persis (bukan float) supaya tidak ada selisih pembulatan.
+// content.xml ditulis langsung ke arsip sambil membaca baris, tidak disusun dulu di memori.
+func writeODS(w io.Writer, r *Report) error {
+	// mimetype wajib file pertama & tidak dikompres
+	zw := zip.NewWriter(w)
+	mime, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mime, odsMimeType); err != nil {
+		return err
+	}
+	manifest, err := zw.Create("META-INF/manifest.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(manifest, odsManifest); err != nil {
+		return err
+	}
+	file, err := zw.Create("content.xml")
+	if err != nil {
+		return err
+	}
+
+	content := bufio.NewWriter(file)
+	content.WriteString(odsContentHead)
+	content.WriteString(`<table:table table:name="` + xmlEscape(SheetName) + `">`)
+	content.WriteString(`<table:table-column table:style-name="co1"/>`)
+	content.WriteString(`<table:table-column table:style-name="co2" table:number-columns-repeated="4"/>`)
+	content.WriteString(`<table:table-column table:style-name="co3"/>`)
+	content.WriteString(`<table:table-column table:style-name="co2"/>`)
+	content.WriteString(`<table:table-column table:style-name="co4" table:number-columns-repeated="2"/>`)
+	content.WriteString(`<table:table-column table:style-name="co2"/>`)
+	content.WriteByte('\n')
+
+	content.WriteString("<table:table-row>")
+	for _, title := range columns(r) {
+		content.WriteString(`<table:table-cell table:style-name="head" office:value-type="string"><text:p>` +
+			xmlEscape(title) + "</text:p></table:table-cell>")
+	}
+	content.WriteString("</table:table-row>\n")
+
+	err = r.eachRow(func(i int, t Row) error {
+		typeStyle := "expense"
+		if t.Type == models.TypeIncome {
+			typeStyle = "income"
+		}
+		content.WriteString("<table:table-row>")
+		odsNumber(content, strconv.Itoa(i+1))
+		content.WriteString(`<table:table-cell office:value-type="date" office:date-value="` + t.Date.Format("2006-01-02") +
+			`"><text:p>` + t.Date.Format("02-01-2006") + "</text:p></table:table-cell>")
+		odsString(content, t.Date.Format("15:04"), "")
+		odsString(content, strings.ToUpper(string(t.Type)), typeStyle)
+		odsString(content, t.Category, "")
+		odsString(content, t.Note, "")
+		odsString(content, t.Currency, "")
+		odsNumber(content, money.Decimal(t.Amount, t.Currency))
+		odsNumber(content, money.Decimal(t.Base, r.Currency))
+		odsString(content, t.TagList(), "")
+		_, err := content.WriteString("</table:table-row>\n")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	content.WriteString("</table:table>\n")
+	content.WriteString(odsContentTail)
+	if err := content.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func odsString(b *bufio.Writer, value, style string) {
+	b.WriteString("<table:table-cell")
+	if style != "" {
+		b.WriteString(` table:style-name="` + style + `"`)
+	}
+	b.WriteString(` office:value-type="string"><text:p>` + xmlEscape(value) + "</text:p></table:table-cell>")
+}
+
+func odsNumber(b *bufio.Writer, value string) {
+	b.WriteString(`<table:table-cell office:value-type="float" office:value="` + value + `"><text:p>` +
+		value + "</text:p></table:table-cell>")
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
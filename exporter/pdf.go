@@ -0,0 +1,357 @@
+package exporter
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ukuran kertas A4 dalam point (1/72 inci)
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	pageMargin = 40.0
+	footerSize = 24.0 // ruang nomor halaman di bawah
+)
+
+type pdfColor [3]float64
+
+var (
+	colorText    = pdfColor{0.13, 0.13, 0.16}
+	colorMuted   = pdfColor{0.42, 0.45, 0.5}
+	colorHeader  = pdfColor{0.31, 0.27, 0.9} // #4F46E5, sama dengan header XLSX
+	colorWhite   = pdfColor{1, 1, 1}
+	colorStripe  = pdfColor{0.95, 0.96, 0.98}
+	colorIncome  = pdfColor{0.06, 0.55, 0.4}
+	colorExpense = pdfColor{0.8, 0.2, 0.2}
+)
+
+type pdfColumn struct {
+	title string
+	width float64
+	right bool // rata kanan (nominal)
+}
+
+// pdfDoc: penyusun halaman sederhana, koordinat PDF (0,0 di kiri bawah), font standar Helvetica
+type pdfDoc struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64 // posisi baris berikutnya (turun terus sampai batas bawah)
+}
+
+func (d *pdfDoc) newPage() {
+	d.page = new(bytes.Buffer)
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - pageMargin
+}
+
+// ensure: pindah halaman kalau sisa ruang kurang dari height. true = halaman baru.
+func (d *pdfDoc) ensure(height float64) bool {
+	if d.y-height >= pageMargin+footerSize {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+func (d *pdfDoc) text(x, y, size float64, bold bool, color pdfColor, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %s Tf %s rg 1 0 0 1 %s %s Tm (%s) Tj ET\n",
+		font, num(size), color.String(), num(x), num(y), pdfString(s))
+}
+
+// textRight: teks rata kanan dengan ujung di x
+func (d *pdfDoc) textRight(x, y, size float64, bold bool, color pdfColor, s string) {
+	d.text(x-textWidth(s, size), y, size, bold, color, s)
+}
+
+func (d *pdfDoc) rect(x, y, w, h float64, color pdfColor) {
+	fmt.Fprintf(d.page, "%s rg %s %s %s %s re f\n", color.String(), num(x), num(y), num(w), num(h))
+}
+
+func (d *pdfDoc) line(x1, y1, x2, y2 float64, color pdfColor) {
+	fmt.Fprintf(d.page, "%s RG 0.5 w %s %s m %s %s l S\n", color.String(), num(x1), num(y1), num(x2), num(y2))
+}
+
+// tableRow: satu baris tabel setinggi 14pt. colors boleh nil (warna teks biasa).
+func (d *pdfDoc) tableRow(cols []pdfColumn, cells []string, colors []pdfColor, bold bool, background *pdfColor) {
+	const height, size = 14.0, 8.0
+	if background != nil {
+		d.rect(pageMargin, d.y-height, pageWidth-2*pageMargin, height, *background)
+	}
+	x := pageMargin
+	for i, col := range cols {
+		color := colorText
+		if bold && background != nil {
+			color = colorWhite
+		} else if colors != nil {
+			color = colors[i]
+		}
+		cell := fitText(cells[i], col.width-8, size)
+		if col.right {
+			d.textRight(x+col.width-4, d.y-10, size, bold, color, cell)
+		} else {
+			d.text(x+4, d.y-10, size, bold, color, cell)
+		}
+		x += col.width
+	}
+	d.y -= height
+}
+
+func (d *pdfDoc) heading(title string) {
+	d.ensure(50)
+	d.y -= 14
+	d.text(pageMargin, d.y, 12, true, colorText, title)
+	d.y -= 8
+}
+
+// writePDF: laporan bulanan siap cetak: ringkasan, rincian per kategori, lalu daftar transaksi.
+// Teks di luar Latin-1 (emoji, aksara lain) diganti "?" karena memakai font standar PDF.
+func writePDF(w io.Writer, r *Report) error {
+	d := &pdfDoc{}
+	d.newPage()
+
+	// Kepala laporan
+	d.text(pageMargin, d.y-16, 18, true, colorHeader, SheetName)
+	d.y -= 34
+	info := [][2]string{
+		{"Periode", r.PeriodLabel()},
+		{"Pemilik", r.Owner},
+		{"Mata uang", r.Currency},
+		{"Dibuat", r.GeneratedAt.Format("02-01-2006 15:04") + " (" + r.Location.String() + ")"},
+	}
+	for _, item := range info {
+		d.text(pageMargin, d.y, 9, false, colorMuted, item[0])
+		d.text(pageMargin+70, d.y, 9, false, colorText, item[1])
+		d.y -= 13
+	}
+
+	// Ringkasan: tiga kotak pemasukan / pengeluaran / selisih
+	d.y -= 8
+	boxWidth := (pageWidth - 2*pageMargin - 20) / 3
+	summary := []struct {
+		label  string
+		amount int64
+		color  pdfColor
+	}{
+		{"Pemasukan", r.Income, colorIncome},
+		{"Pengeluaran", r.Expense, colorExpense},
+		{"Selisih", r.Income - r.Expense, colorText},
+	}
+	for i, s := range summary {
+		x := pageMargin + float64(i)*(boxWidth+10)
+		d.rect(x, d.y-44, boxWidth, 44, colorStripe)
+		d.text(x+10, d.y-16, 9, false, colorMuted, s.label)
+		d.text(x+10, d.y-34, 13, true, s.color, fitText(money.Format(s.amount, r.Currency), boxWidth-20, 13))
+	}
+	d.y -= 56
+	d.text(pageMargin, d.y, 9, false, colorMuted, fmt.Sprintf("%d transaksi", r.Count))
+	d.y -= 10
+
+	// Rincian per kategori (persen terhadap total tipenya)
+	d.heading("Rincian per Kategori")
+	catCols := []pdfColumn{
+		{"Tipe", 90, false}, {"Kategori", 195, false}, {"Transaksi", 70, true}, {"Jumlah", 110, true}, {"%", 50, true},
+	}
+	d.tableRow(catCols, titles(catCols), nil, true, &colorHeader)
+	for i, cat := range r.Categories {
+		if d.ensure(14) {
+			d.tableRow(catCols, titles(catCols), nil, true, &colorHeader)
+		}
+		total := r.Expense
+		if cat.Type == models.TypeIncome {
+			total = r.Income
+		}
+		percent := "-"
+		if total > 0 {
+			percent = strconv.FormatFloat(float64(cat.Total)*100/float64(total), 'f', 1, 64)
+		}
+		d.tableRow(catCols, []string{
+			typeLabel(cat.Type), cat.Category, strconv.Itoa(cat.Count), money.Format(cat.Total, r.Currency), percent,
+		}, nil, false, stripe(i))
+	}
+	if len(r.Categories) == 0 {
+		d.text(pageMargin+4, d.y-10, 8, false, colorMuted, "Tidak ada transaksi di periode ini")
+		d.y -= 14
+	}
+
+	// Daftar transaksi, judul kolom diulang di tiap halaman baru
+	d.heading("Daftar Transaksi")
+	trxCols := []pdfColumn{
+		{"Tanggal", 52, false}, {"Jam", 30, false}, {"Tipe", 56, false}, {"Kategori", 80, false},
+		{"Catatan", 137, false}, {"Jumlah", 80, true}, {"Jumlah (" + r.Currency + ")", 80, true},
+	}
+	d.tableRow(trxCols, titles(trxCols), nil, true, &colorHeader)
+	err := r.eachRow(func(i int, t Row) error {
+		if d.ensure(14) {
+			d.tableRow(trxCols, titles(trxCols), nil, true, &colorHeader)
+		}
+		amountColor := colorExpense
+		if t.Type == models.TypeIncome {
+			amountColor = colorIncome
+		}
+		d.tableRow(trxCols, []string{
+			t.Date.Format("02-01-2006"), t.Date.Format("15:04"), typeLabel(t.Type), t.Category, t.Note,
+			money.Format(t.Amount, t.Currency), money.Format(t.Base, r.Currency),
+		}, []pdfColor{colorText, colorText, amountColor, colorText, colorText, amountColor, amountColor}, false, stripe(i))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Nomor halaman
+	for i, page := range d.pages {
+		d.page = page
+		d.line(pageMargin, pageMargin+12, pageWidth-pageMargin, pageMargin+12, colorStripe)
+		d.text(pageMargin, pageMargin, 8, false, colorMuted, "Syukur - "+SheetName+" "+r.PeriodLabel())
+		d.textRight(pageWidth-pageMargin, pageMargin, 8, false, colorMuted, fmt.Sprintf("Halaman %d dari %d", i+1, len(d.pages)))
+	}
+
+	return d.writeTo(w, r)
+}
+
+// writeTo menyusun objek PDF 1.4: katalog, daftar halaman, 2 font, info, lalu halaman + content stream
+func (d *pdfDoc) writeTo(w io.Writer, r *Report) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	const firstPage = 6 // objek 1-5 tetap, tiap halaman 2 objek (page + content)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (Syukur) /CreationDate (D:%s) >>",
+		pdfString(SheetName+" "+r.PeriodLabel()), r.GeneratedAt.UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(pageWidth), num(pageHeight), firstPage+2*i+1))
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		zw.Write(page.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := out.WriteTo(w)
+	return err
+}
+
+func titles(cols []pdfColumn) []string {
+	out := make([]string, len(cols))
+	for i, col := range cols {
+		out[i] = col.title
+	}
+	return out
+}
+
+// stripe: latar belang untuk baris genap
+func stripe(i int) *pdfColor {
+	if i%2 == 1 {
+		return &colorStripe
+	}
+	return nil
+}
+
+func typeLabel(t models.TransactionType) string {
+	if t == models.TypeIncome {
+		return "Pemasukan"
+	}
+	return "Pengeluaran"
+}
+
+func (c pdfColor) String() string {
+	return num(c[0]) + " " + num(c[1]) + " " + num(c[2])
+}
+
+// num: angka pendek tanpa nol berlebih (PDF tidak menerima notasi eksponen)
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// pdfString: teks jadi string literal PDF (WinAnsi): karakter di luar Latin-1 jadi "?", kurung & backslash di-escape
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x7f || (r >= 0xa0 && r <= 0xff):
+			b.WriteByte(byte(r))
+		case r == '€':
+			b.WriteByte(0x80)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths: lebar glyph Helvetica (per 1000 unit) untuk ASCII 32-126, dari metrik AFM standar.
+// Helvetica-Bold sedikit lebih lebar, cukup dipakai untuk perkiraan rata kanan & pemotongan.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+func textWidth(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += helveticaWidths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// fitText memotong teks yang lebih lebar dari kolom, diakhiri "..."
+func fitText(s string, width, size float64) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if textWidth(s, size) <= width {
+		return s
+	}
+	for s != "" && textWidth(s+"...", size) > width {
+		_, n := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-n]
+	}
+	return s + "..."
+}
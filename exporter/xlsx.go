@@ -0,0 +1,396 @@
+package exporter
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheet tambahan workbook XLSX. Sheet transaksi (SheetName) tetap pertama supaya file bisa diimport ulang.
+const (
+	sheetSummary  = "Ringkasan"
+	sheetCategory = "Per Kategori"
+	sheetDaily    = "Harian"
+)
+
+var frozenHeader = &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}
+
+// writeXLSX: sheet transaksi + ringkasan, per kategori & harian (dengan grafik).
+// Semua sheet ditulis pakai StreamWriter (baris besar disimpan ke file sementara, bukan memori).
+// Angka ringkasan berupa rumus Excel ke sheet transaksi, nilai hasilnya ikut disimpan
+// supaya tetap tampil di aplikasi yang tidak menghitung ulang rumus.
+func writeXLSX(w io.Writer, r *Report) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", SheetName)
+	for _, name := range []string{sheetSummary, sheetCategory, sheetDaily} {
+		if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+	}
+
+	styles := newXLSXStyles(f)
+	days, err := writeTransactionSheet(f, styles, r)
+	if err != nil {
+		return err
+	}
+	for _, write := range []func(*excelize.File, *xlsxStyles, *Report) error{writeSummarySheet, writeCategorySheet} {
+		if err := write(f, styles, r); err != nil {
+			return err
+		}
+	}
+	if err := writeDailySheet(f, styles, r, days); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+// writeTransactionSheet menulis baris transaksi sambil menjumlah per hari untuk sheet harian
+func writeTransactionSheet(f *excelize.File, s *xlsxStyles, r *Report) (*dailyTotals, error) {
+	rows, err := newSheetRows(f, SheetName, []float64{6, 12, 8, 12, 16, 30, 10, 18, 18, 20})
+	if err != nil {
+		return nil, err
+	}
+	rows.add(s.headerCells(columns(r))...)
+	days := &dailyTotals{totals: make(map[time.Time]*dayTotal)}
+	err = r.eachRow(func(i int, t Row) error {
+		days.add(t)
+		rows.add(
+			i+1,
+			excelize.Cell{StyleID: s.date, Value: dateOnly(t.Date)},
+			t.Date.Format("15:04"),
+			excelize.Cell{StyleID: s.typeStyle(t.Type), Value: strings.ToUpper(string(t.Type))},
+			t.Category,
+			t.Note,
+			t.Currency,
+			excelize.Cell{StyleID: s.amount(t.Currency, false), Value: money.Major(t.Amount, t.Currency)},
+			excelize.Cell{StyleID: s.amount(r.Currency, false), Value: money.Major(t.Base, r.Currency)},
+			t.TagList(),
+		)
+		return rows.err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := f.AutoFilter(SheetName, fmt.Sprintf("A1:J%d", rows.row), nil); err != nil {
+		return nil, err
+	}
+	return days, rows.flush()
+}
+
+// writeSummarySheet: kepala laporan, total (SUMIFS ke sheet transaksi) & pivot kategori x tipe
+func writeSummarySheet(f *excelize.File, s *xlsxStyles, r *Report) error {
+	rows, err := newSheetRows(f, sheetSummary, []float64{24, 20, 20, 20})
+	if err != nil {
+		return err
+	}
+	amount, total := s.amount(r.Currency, false), s.amount(r.Currency, true)
+	amounts, types, categories := trxColumn("I"), trxColumn("D"), trxColumn("E")
+
+	rows.add(excelize.Cell{StyleID: s.title, Value: SheetName})
+	rows.add()
+	rows.add(s.label("Periode"), r.PeriodLabel())
+	rows.add(s.label("Pemilik"), r.Owner)
+	rows.add(s.label("Mata uang"), r.Currency)
+	rows.add(s.label("Dibuat"), r.GeneratedAt.Format("02-01-2006 15:04")+" ("+r.Location.String()+")")
+	rows.add()
+	income := rows.add(s.label("Pemasukan"),
+		formula(total, money.Major(r.Income, r.Currency), `SUMIFS(%s,%s,"INCOME")`, amounts, types))
+	expense := rows.add(s.label("Pengeluaran"),
+		formula(total, money.Major(r.Expense, r.Currency), `SUMIFS(%s,%s,"EXPENSE")`, amounts, types))
+	rows.add(s.label("Saldo"),
+		formula(total, money.Major(r.Income-r.Expense, r.Currency), "B%d-B%d", income, expense))
+	// Bukan rumus COUNT: baris rincian split ikut bernomor, padahal satu transaksi
+	rows.add(s.label("Jumlah transaksi"), r.Count)
+	rows.add()
+
+	rows.add(s.headerCells([]string{"Kategori", "Pemasukan", "Pengeluaran", "Selisih"})...)
+	pivot := categoryPivot(r)
+	first := rows.row + 1
+	for _, p := range pivot {
+		n := rows.row + 1
+		rows.add(
+			p.category,
+			formula(amount, money.Major(p.income, r.Currency), `SUMIFS(%s,%s,$A%d,%s,"INCOME")`, amounts, categories, n, types),
+			formula(amount, money.Major(p.expense, r.Currency), `SUMIFS(%s,%s,$A%d,%s,"EXPENSE")`, amounts, categories, n, types),
+			formula(amount, money.Major(p.income-p.expense, r.Currency), "B%d-C%d", n, n),
+		)
+	}
+	if last := rows.row; len(pivot) > 0 {
+		rows.add(
+			s.label("Total"),
+			formula(total, money.Major(r.Income, r.Currency), "SUM(B%d:B%d)", first, last),
+			formula(total, money.Major(r.Expense, r.Currency), "SUM(C%d:C%d)", first, last),
+			formula(total, money.Major(r.Income-r.Expense, r.Currency), "SUM(D%d:D%d)", first, last),
+		)
+	}
+	return rows.flush()
+}
+
+// writeCategorySheet: satu baris per tipe + kategori (jumlah transaksi, total, rata-rata, persen dari total tipenya)
+func writeCategorySheet(f *excelize.File, s *xlsxStyles, r *Report) error {
+	rows, err := newSheetRows(f, sheetCategory, []float64{12, 20, 18, 20, 20, 12})
+	if err != nil {
+		return err
+	}
+	amount := s.amount(r.Currency, false)
+	amounts, types, categories := trxColumn("I"), trxColumn("D"), trxColumn("E")
+
+	rows.add(s.headerCells([]string{"Tipe", "Kategori", "Jumlah Transaksi", "Total", "Rata-rata", "Persentase"})...)
+	for _, c := range r.Categories {
+		n := rows.row + 1
+		typeTotal := r.Expense
+		if c.Type == models.TypeIncome {
+			typeTotal = r.Income
+		}
+		var average, share float64
+		if c.Count > 0 {
+			average = money.Major(c.Total, r.Currency) / float64(c.Count)
+		}
+		if typeTotal > 0 {
+			share = float64(c.Total) / float64(typeTotal)
+		}
+		rows.add(
+			excelize.Cell{StyleID: s.typeStyle(c.Type), Value: strings.ToUpper(string(c.Type))},
+			c.Category,
+			formula(0, c.Count, "COUNTIFS(%s,$A%d,%s,$B%d)", types, n, categories, n),
+			formula(amount, money.Major(c.Total, r.Currency), "SUMIFS(%s,%s,$A%d,%s,$B%d)", amounts, types, n, categories, n),
+			formula(amount, average, "IF(C%d=0,0,D%d/C%d)", n, n, n),
+			formula(s.percent, share, "IFERROR(D%d/SUMIFS(%s,%s,$A%d),0)", n, amounts, types, n),
+		)
+	}
+	if err := f.AutoFilter(sheetCategory, fmt.Sprintf("A1:F%d", rows.row), nil); err != nil {
+		return err
+	}
+	return rows.flush()
+}
+
+type dayTotal struct{ income, expense int64 }
+
+// dailyTotals: pemasukan & pengeluaran per tanggal (nilai cache rumus sheet harian)
+type dailyTotals struct {
+	first, last time.Time
+	totals      map[time.Time]*dayTotal
+}
+
+func (d *dailyTotals) add(t Row) {
+	day := dateOnly(t.Date)
+	if len(d.totals) == 0 || day.Before(d.first) {
+		d.first = day
+	}
+	if len(d.totals) == 0 || day.After(d.last) {
+		d.last = day
+	}
+	if d.totals[day] == nil {
+		d.totals[day] = &dayTotal{}
+	}
+	if t.Type == models.TypeIncome {
+		d.totals[day].income += t.Base
+	} else {
+		d.totals[day].expense += t.Base
+	}
+}
+
+// writeDailySheet: pemasukan & pengeluaran per hari (hari kosong tetap ada) + grafik pengeluaran harian
+func writeDailySheet(f *excelize.File, s *xlsxStyles, r *Report, days *dailyTotals) error {
+	rows, err := newSheetRows(f, sheetDaily, []float64{14, 20, 20})
+	if err != nil {
+		return err
+	}
+	rows.add(s.headerCells([]string{"Tanggal", "Pemasukan", "Pengeluaran"})...)
+	if len(days.totals) == 0 {
+		return rows.flush()
+	}
+
+	amount := s.amount(r.Currency, false)
+	amounts, dates, types := trxColumn("I"), trxColumn("B"), trxColumn("D")
+	for day := days.first; !day.After(days.last); day = day.AddDate(0, 0, 1) {
+		total := days.totals[day]
+		if total == nil {
+			total = &dayTotal{}
+		}
+		n := rows.row + 1
+		rows.add(
+			excelize.Cell{StyleID: s.date, Value: day},
+			formula(amount, money.Major(total.income, r.Currency), `SUMIFS(%s,%s,$A%d,%s,"INCOME")`, amounts, dates, n, types),
+			formula(amount, money.Major(total.expense, r.Currency), `SUMIFS(%s,%s,$A%d,%s,"EXPENSE")`, amounts, dates, n, types),
+		)
+	}
+
+	err = f.AddChart(sheetDaily, "E2", &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{{
+			Name:       fmt.Sprintf("'%s'!$C$1", sheetDaily),
+			Categories: fmt.Sprintf("'%s'!$A$2:$A$%d", sheetDaily, rows.row),
+			Values:     fmt.Sprintf("'%s'!$C$2:$C$%d", sheetDaily, rows.row),
+			Fill:       excelize.Fill{Type: "pattern", Color: []string{"#EF4444"}, Pattern: 1},
+		}},
+		Title:     []excelize.RichTextRun{{Text: "Pengeluaran Harian (" + r.Currency + ")"}},
+		Legend:    excelize.ChartLegend{Position: "none"},
+		Dimension: excelize.ChartDimension{Width: 720, Height: 360},
+	})
+	if err != nil {
+		return err
+	}
+	return rows.flush()
+}
+
+// sheetRows: StreamWriter yang menulis baris berurutan dari baris 1. Error pertama disimpan & dikembalikan flush.
+type sheetRows struct {
+	sw  *excelize.StreamWriter
+	row int
+	err error
+}
+
+// newSheetRows: stream writer dengan lebar kolom (dari kolom A) & judul kolom yang dibekukan
+func newSheetRows(f *excelize.File, sheet string, widths []float64) (*sheetRows, error) {
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+	for i, width := range widths {
+		if err := sw.SetColWidth(i+1, i+1, width); err != nil {
+			return nil, err
+		}
+	}
+	if sheet != sheetSummary {
+		if err := sw.SetPanes(frozenHeader); err != nil {
+			return nil, err
+		}
+	}
+	return &sheetRows{sw: sw}, nil
+}
+
+// add menulis baris berikutnya (tanpa values = baris kosong), return nomor barisnya
+func (s *sheetRows) add(values ...interface{}) int {
+	s.row++
+	if s.err == nil && len(values) > 0 {
+		cell, _ := excelize.CoordinatesToCellName(1, s.row)
+		s.err = s.sw.SetRow(cell, values)
+	}
+	return s.row
+}
+
+func (s *sheetRows) flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.sw.Flush()
+}
+
+// xlsxStyles: style dibuat sekali per workbook, format nominal per mata uang dibuat saat pertama dipakai
+type xlsxStyles struct {
+	f                                                   *excelize.File
+	header, title, bold, date, percent, income, expense int
+	amounts                                             map[string]int
+}
+
+func newXLSXStyles(f *excelize.File) *xlsxStyles {
+	s := &xlsxStyles{f: f, amounts: make(map[string]int)}
+	s.header, _ = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "#FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#4F46E5"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	s.title, _ = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 16, Color: "#4F46E5"}})
+	s.bold, _ = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	dateFormat := "dd-mm-yyyy"
+	s.date, _ = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	s.percent, _ = f.NewStyle(&excelize.Style{NumFmt: 10}) // 0.00%
+	s.income, _ = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "#10B981"}})
+	s.expense, _ = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "#EF4444"}})
+	return s
+}
+
+// amount: format nominal dengan simbol & pemisah ribuan, mis. "Rp "#,##0 atau "US$ "#,##0.00
+func (s *xlsxStyles) amount(code string, bold bool) int {
+	key := code
+	if bold {
+		key += "/bold"
+	}
+	if id, ok := s.amounts[key]; ok {
+		return id
+	}
+
+	symbol, format := code, "#,##0"
+	if c, ok := money.Lookup(code); ok {
+		symbol = c.Symbol
+		if c.Exponent > 0 {
+			format += "." + strings.Repeat("0", c.Exponent)
+		}
+	}
+	format = `"` + symbol + ` "` + format
+	style := &excelize.Style{CustomNumFmt: &format}
+	if bold {
+		style.Font = &excelize.Font{Bold: true}
+	}
+	id, _ := s.f.NewStyle(style)
+	s.amounts[key] = id
+	return id
+}
+
+func (s *xlsxStyles) typeStyle(t models.TransactionType) int {
+	if t == models.TypeIncome {
+		return s.income
+	}
+	return s.expense
+}
+
+func (s *xlsxStyles) label(text string) excelize.Cell {
+	return excelize.Cell{StyleID: s.bold, Value: text}
+}
+
+func (s *xlsxStyles) headerCells(titles []string) []interface{} {
+	cells := make([]interface{}, len(titles))
+	for i, title := range titles {
+		cells[i] = excelize.Cell{StyleID: s.header, Value: title}
+	}
+	return cells
+}
+
+// formula: sel rumus beserta nilai hasilnya (cache)
+func formula(style int, value interface{}, format string, args ...interface{}) excelize.Cell {
+	return excelize.Cell{StyleID: style, Formula: fmt.Sprintf(format, args...), Value: value}
+}
+
+// trxColumn: satu kolom penuh di sheet transaksi, mis. 'Laporan Keuangan'!$I:$I
+func trxColumn(col string) string {
+	return "'" + SheetName + "'!$" + col + ":$" + col
+}
+
+// dateOnly: tanggal tanpa jam dalam UTC (tanggal Excel tidak punya zona waktu)
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type pivotRow struct {
+	category        string
+	income, expense int64
+}
+
+// categoryPivot: total pemasukan & pengeluaran per kategori, urut nama kategori
+func categoryPivot(r *Report) []pivotRow {
+	index := make(map[string]int)
+	var pivot []pivotRow
+	for _, c := range r.Categories {
+		i, ok := index[c.Category]
+		if !ok {
+			i = len(pivot)
+			index[c.Category] = i
+			pivot = append(pivot, pivotRow{category: c.Category})
+		}
+		if c.Type == models.TypeIncome {
+			pivot[i].income += c.Total
+		} else {
+			pivot[i].expense += c.Total
+		}
+	}
+	sort.Slice(pivot, func(i, j int) bool { return pivot[i].category < pivot[j].category })
+	return pivot
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/utils"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/user/data-export: arsip ZIP semua data akun (profil, pengaturan, transaksi,
+// dompet, rekening koran, bukti pembayaran beserta gambarnya, audit log)
+func (h *Handler) ExportAccountData(c *gin.Context) {
+	userID := getUserID(c)
+
+	archive, err := h.accountService.Archive(userID, time.Now())
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+	h.recordAudit(c, AuditDataExport, "user", userID, nil, nil)
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", exporter.ArchiveFileName(archive.User.Username, archive.GeneratedAt)))
+	c.Header("Content-Transfer-Encoding", "binary")
+
+	if err := exporter.WriteArchive(c.Writer, archive); err != nil {
+		if c.Writer.Written() {
+			log.Printf("arsip data user %d gagal di tengah jalan: %v", userID, err)
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+	}
+}
+
+// POST /api/user/delete-request {"current_password": "..."}
+// Akun + semua datanya dihapus permanen setelah masa tunggu (ACCOUNT_DELETION_DAYS) oleh job purge.
+// Selama masa tunggu akun tetap bisa dipakai, permintaan ulang tidak memperpanjang waktunya.
+func (h *Handler) RequestAccountDeletion(c *gin.Context) {
+	user, err := h.users.FindByID(getUserID(c))
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+
+	var input struct {
+		CurrentPassword string `json:"current_password"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	if verr := utils.ValidateCurrentPassword(user.Password, input.CurrentPassword); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+
+	if user.DeleteAfter == nil {
+		deleteAfter := time.Now().AddDate(0, 0, utils.AccountDeletionDays())
+		if err := h.users.UpdateFields(user.ID, map[string]interface{}{"delete_after": deleteAfter}); err != nil {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
+		user.DeleteAfter = &deleteAfter
+		h.recordAudit(c, AuditDeleteRequest, "user", user.ID, nil, gin.H{"delete_after": deleteAfter})
+	}
+
+	when := user.DeleteAfter.In(utils.UserLocation(user.Timezone)).Format("02-01-2006 15:04 MST")
+	c.JSON(http.StatusOK, gin.H{
+		"message":      utils.T(utils.Lang(c), "msg.account_deletion_scheduled", when),
+		"delete_after": user.DeleteAfter,
+	})
+}
+
+// DELETE /api/user/delete-request: batalkan permintaan hapus akun selama masa tunggu
+func (h *Handler) CancelAccountDeletion(c *gin.Context) {
+	user, err := h.users.FindByID(getUserID(c))
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	if user.DeleteAfter == nil {
+		utils.RespondError(c, utils.ErrNoDeletionRequest)
+		return
+	}
+
+	if err := h.users.UpdateFields(user.ID, map[string]interface{}{"delete_after": nil}); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditDeleteCancel, "user", user.ID, gin.H{"delete_after": user.DeleteAfter}, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.account_deletion_cancelled")})
+}
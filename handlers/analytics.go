@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"backend-gin/services"
+	"backend-gin/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/analytics/trends?interval=week|month|year + filter periode bersama.
+// Tanpa periode: 12 minggu / 12 bulan / 5 tahun terakhir (termasuk yang sedang berjalan).
+func (h *Handler) GetTrends(c *gin.Context) {
+	userID := getUserID(c)
+
+	interval := strings.ToLower(strings.TrimSpace(c.DefaultQuery("interval", services.IntervalMonth)))
+	if !services.ValidInterval(interval) {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("interval", utils.CodeIntervalInvalid))
+		return
+	}
+
+	q, now, appErr := h.periodQuery(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var period utils.Period
+	if q.Empty() {
+		period = defaultTrendPeriod(interval, today, q.MonthStartDay)
+	} else if period, appErr = utils.ResolvePeriod(q, now); appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+	// "Sejak tanggal X" berarti sampai hari ini; tanpa tanggal awal tidak bisa dibagi per interval
+	if period.To.IsZero() {
+		period.To = today.AddDate(0, 0, 1)
+	}
+	if period.From.IsZero() {
+		utils.RespondError(c, utils.ErrPeriodTooLong)
+		return
+	}
+
+	trends, currency, err := h.trxService.Trends(userID, services.TrendQuery{
+		Interval: interval, From: period.From, To: period.To, MonthStartDay: q.MonthStartDay, Now: now,
+	})
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": trends, "currency": currency, "period": periodJSON(period)})
+}
+
+// defaultTrendPeriod: rentang default tren sesuai interval
+func defaultTrendPeriod(interval string, today time.Time, monthStartDay int) utils.Period {
+	switch interval {
+	case services.IntervalWeek:
+		start := utils.WeekStart(today)
+		return utils.Period{From: start.AddDate(0, 0, -7*11), To: start.AddDate(0, 0, 7)}
+	case services.IntervalYear:
+		start := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location())
+		return utils.Period{From: start.AddDate(-4, 0, 0), To: start.AddDate(1, 0, 0)}
+	}
+	start := utils.MonthStart(today, monthStartDay)
+	return utils.Period{From: start.AddDate(0, -11, 0), To: start.AddDate(0, 1, 0)}
+}
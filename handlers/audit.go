@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Daftar aksi yang dicatat di audit log
+const (
+	AuditLogin             = "auth.login"
+	AuditLoginFailed       = "auth.login_failed"
+	AuditRegister          = "auth.register"
+	AuditOwnerSetup        = "auth.owner_setup"
+	AuditPasswordForgot    = "auth.password_forgot"
+	AuditPasswordReset     = "auth.password_reset"
+	AuditProfileUpdate     = "user.profile_update"
+	AuditPasswordChange    = "user.password_change"
+	AuditSettingsUpdate    = "user.settings_update"
+	AuditDataExport        = "user.data_export"
+	AuditDeleteRequest     = "user.delete_request"
+	AuditDeleteCancel      = "user.delete_cancel"
+	AuditTransactionImport = "transaction.import"
+	AuditBulkCreate        = "transaction.bulk_create"
+	AuditBulkDelete        = "transaction.bulk_delete"
+	AuditRecategorize      = "transaction.recategorize"
+	AuditStatementImport   = "statement.import"
+	AuditStatementDelete   = "statement.delete"
+	AuditPaymentVerify     = "payment.verify"
+	AuditPaymentManual     = "payment.manual_upload"
+	AuditAdminUserList     = "admin.user.list"
+	AuditAdminUserView     = "admin.user.view"
+	AuditAdminUserCreate   = "admin.user.create"
+	AuditAdminUserUpdate   = "admin.user.update"
+	AuditAdminUserPassword = "admin.user.password_change"
+	AuditAdminUserStatus   = "admin.user.status"
+	AuditAdminUserDelete   = "admin.user.delete"
+	AuditAdminUserRestore  = "admin.user.restore"
+	AuditAdminPaymentList  = "admin.payment.list"
+	AuditAdminPaymentDel   = "admin.payment.delete"
+	AuditAdminPaymentClear = "admin.payment.delete_all"
+	AuditAdminAuditExport  = "admin.audit.export"
+	AuditAdminRateSave     = "admin.exchange_rate.save"
+	AuditAdminRateDelete   = "admin.exchange_rate.delete"
+)
+
+// Snapshot data user untuk audit (tanpa password / data rahasia)
+func auditUserSnapshot(u models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"username":        u.Username,
+		"role":            u.Role,
+		"status":          u.Status,
+		"telegram_id":     u.TelegramID,
+		"trial_ends_at":   u.TrialEndsAt,
+		"daily_limit":     u.DailyLimit,
+		"base_currency":   u.BaseCurrency,
+		"month_start_day": u.MonthStartDay,
+		"timezone":        u.Timezone,
+		"alert_message":   u.AlertMessage,
+		"language":        u.Language,
+		"monthly_report":  u.MonthlyReport,
+	}
+}
+
+// Helper: Catat aksi ke audit log. Actor diambil dari token (kalau ada).
+// Gagal mencatat tidak membatalkan aksi utama, cukup masuk log server.
+func (h *Handler) recordAudit(c *gin.Context, action, targetType string, targetID interface{}, before, after interface{}) {
+	var actor *models.User
+	if id, exists := c.Get("user_id"); exists {
+		if u, err := h.users.FindByID(id.(uint)); err == nil {
+			actor = u
+		} else {
+			actor = &models.User{ID: id.(uint)}
+		}
+	}
+	h.writeAudit(c, actor, action, targetType, targetID, before, after)
+}
+
+// Helper: recordAudit untuk aksi yang actor-nya sudah diketahui tapi belum ada token (login, reset password)
+func (h *Handler) recordAuditAs(c *gin.Context, actor models.User, action, targetType string, targetID interface{}, before, after interface{}) {
+	h.writeAudit(c, &actor, action, targetType, targetID, before, after)
+}
+
+func (h *Handler) writeAudit(c *gin.Context, actor *models.User, action, targetType string, targetID interface{}, before, after interface{}) {
+	entry := models.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		CreatedAt:  time.Now(),
+	}
+	if targetID != nil {
+		entry.TargetID = fmt.Sprint(targetID)
+	}
+	if actor != nil {
+		actorID := actor.ID
+		entry.ActorID = &actorID
+		entry.ActorUsername = actor.Username
+		entry.ActorRole = actor.Role
+	}
+
+	beforeMap := toAuditMap(before)
+	afterMap := toAuditMap(after)
+	entry.Before = marshalAudit(beforeMap)
+	entry.After = marshalAudit(afterMap)
+	entry.Changes = marshalAudit(diffAudit(beforeMap, afterMap))
+
+	if err := h.auditLogs.Create(&entry); err != nil {
+		log.Printf("[AUDIT] Gagal mencatat %s: %v", action, err)
+	}
+}
+
+// Ubah struct/map apa saja jadi map lewat JSON supaya bisa dibandingkan per field
+func toAuditMap(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func diffAudit(before, after map[string]interface{}) map[string]interface{} {
+	if before == nil || after == nil {
+		return nil
+	}
+	changes := make(map[string]interface{})
+	for key, newVal := range after {
+		if oldVal := before[key]; !reflect.DeepEqual(oldVal, newVal) {
+			changes[key] = gin.H{"from": oldVal, "to": newVal}
+		}
+	}
+	for key, oldVal := range before {
+		if _, exists := after[key]; !exists {
+			changes[key] = gin.H{"from": oldVal, "to": nil}
+		}
+	}
+	return changes
+}
+
+func marshalAudit(m map[string]interface{}) string {
+	if m == nil {
+		return ""
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+// Helper: Parse tanggal filter (RFC3339 atau YYYY-MM-DD di zona waktu admin)
+func parseAuditTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// GET /api/admin/audit?actor_id=&action=&target_type=&target_id=&from=&to=&page=&limit=&format=csv
+func (h *Handler) GetAuditLogs(c *gin.Context) {
+	if !isAdmin(c) {
+		utils.RespondError(c, utils.ErrForbidden)
+		return
+	}
+
+	var filter repository.AuditFilter
+	loc := utils.UserLocation("")
+	if admin, err := h.users.FindByID(getUserID(c)); err == nil {
+		loc = utils.UserLocation(admin.Timezone)
+	}
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := strconv.ParseUint(actorID, 10, 64)
+		if err != nil {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("actor_id", utils.CodeFieldInvalid))
+			return
+		}
+		actor := uint(id)
+		filter.ActorID = &actor
+	}
+	filter.Action = c.Query("action")
+	filter.TargetType = c.Query("target_type")
+	filter.TargetID = c.Query("target_id")
+	if from := c.Query("from"); from != "" {
+		t, err := parseAuditTime(from, loc)
+		if err != nil {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("from", utils.CodeFieldInvalid))
+			return
+		}
+		filter.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := parseAuditTime(to, loc)
+		if err != nil {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("to", utils.CodeFieldInvalid))
+			return
+		}
+		// Tanggal tanpa jam dianggap sampai akhir hari itu
+		if len(to) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1)
+		}
+		filter.To = &t
+	}
+
+	if c.Query("format") == "csv" {
+		h.exportAuditCSV(c, filter)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	logs, total, err := h.auditLogs.List(filter, page, limit)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": logs,
+		"meta": gin.H{
+			"current_page": page,
+			"limit":        limit,
+			"total_data":   total,
+			"total_pages":  math.Ceil(float64(total) / float64(limit)),
+		},
+	})
+}
+
+// Export audit log (sesuai filter) ke CSV, dibaca per batch supaya hemat memori
+func (h *Handler) exportAuditCSV(c *gin.Context, filter repository.AuditFilter) {
+	h.recordAudit(c, AuditAdminAuditExport, "audit_log", nil, nil, map[string]interface{}{"filters": c.Request.URL.RawQuery})
+
+	fileName := fmt.Sprintf("Audit_Log_%s.csv", time.Now().Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "created_at", "actor_id", "actor_username", "actor_role", "action", "target_type", "target_id", "changes", "before", "after", "ip", "user_agent"})
+
+	// Dibaca urut ID (kronologis)
+	err := h.auditLogs.Each(filter, 500, func(batch []models.AuditLog) error {
+		for _, entry := range batch {
+			actorID := ""
+			if entry.ActorID != nil {
+				actorID = strconv.FormatUint(uint64(*entry.ActorID), 10)
+			}
+			w.Write([]string{
+				strconv.FormatUint(uint64(entry.ID), 10),
+				entry.CreatedAt.Format(time.RFC3339),
+				actorID,
+				exporter.CSVCell(entry.ActorUsername),
+				entry.ActorRole,
+				entry.Action,
+				entry.TargetType,
+				exporter.CSVCell(entry.TargetID),
+				exporter.CSVCell(entry.Changes),
+				exporter.CSVCell(entry.Before),
+				exporter.CSVCell(entry.After),
+				exporter.CSVCell(entry.IP),
+				exporter.CSVCell(entry.UserAgent),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	})
+	if err != nil {
+		log.Printf("[AUDIT] Gagal export CSV: %v", err)
+	}
+	w.Flush()
+}
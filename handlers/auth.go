@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"backend-gin/database"
 	"backend-gin/models"
 	"backend-gin/utils"
 	"net/http"
@@ -20,35 +19,40 @@ type LoginInput struct {
 // Struct untuk Validasi Input Register
 type RegisterInput struct {
 	Username        string `json:"username" binding:"required"`
-	Password        string `json:"password" binding:"required,min=6"`
+	Password        string `json:"password" binding:"required"` // Aturan panjang/kekuatan dicek di utils.ValidatePassword
 	ConfirmPassword string `json:"confirm_password" binding:"required"`
 }
 
-func Login(c *gin.Context) {
+func (h *Handler) Login(c *gin.Context) {
 	var input LoginInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format data salah", "details": err.Error()})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
-	var user models.User
-	if err := database.DB.Where("username = ?", input.Username).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Username atau password salah"})
+	// Sama dengan saat register/ganti username: spasi di awal/akhir dibuang
+	input.Username = utils.NormalizeUsername(input.Username)
+	user, err := h.users.FindByUsername(input.Username)
+	if err != nil {
+		h.writeAudit(c, nil, AuditLoginFailed, "user", nil, nil, gin.H{"username": input.Username})
+		utils.RespondError(c, utils.ErrInvalidCredentials)
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Username atau password salah"})
+		h.writeAudit(c, nil, AuditLoginFailed, "user", user.ID, nil, gin.H{"username": input.Username})
+		utils.RespondError(c, utils.ErrInvalidCredentials)
 		return
 	}
 
 	// Buat Token JWT
-	token, err := utils.GenerateToken(user.ID, user.Role)
+	token, err := utils.GenerateToken(user.ID, user.Role, user.SessionVersion)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token"})
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
 		return
 	}
+	h.recordAuditAs(c, *user, AuditLogin, "user", user.ID, nil, nil)
 
 	// Kirim Response Lengkap (termasuk status trial)
 	c.JSON(http.StatusOK, gin.H{
@@ -59,26 +63,36 @@ func Login(c *gin.Context) {
 			"role":          user.Role,
 			"status":        user.Status,
 			"trial_ends_at": user.TrialEndsAt,
+			"language":      user.Language,
 		},
 	})
 }
 
-func Register(c *gin.Context) {
+func (h *Handler) Register(c *gin.Context) {
 	var input RegisterInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap", "details": err.Error()})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
-	// 1. Cek Konfirmasi Password
-	if input.Password != input.ConfirmPassword {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Konfirmasi password tidak cocok!"})
+	// 1. Validasi Username & Password (termasuk konfirmasi)
+	input.Username = utils.NormalizeUsername(input.Username)
+	if verr := utils.ValidateUsername(input.Username); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+	if verr := utils.ValidateNewPassword(input.Password, input.ConfirmPassword, input.Username); verr != nil {
+		utils.RespondError(c, verr)
 		return
 	}
 
 	// 2. Hash Password
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 
 	// 3. Siapkan Data User Baru (Mode Trial 1 Hari)
 	newUser := models.User{
@@ -88,16 +102,18 @@ func Register(c *gin.Context) {
 		Status:      "trial",
 		TrialEndsAt: time.Now().Add(24 * time.Hour), // Trial 24 Jam dari sekarang
 		TelegramID:  nil,                            // Belum bind telegram
+		Language:    utils.Lang(c),                  // Bahasa bot ikut bahasa browser saat daftar
 	}
 
 	// 4. Simpan ke Database
-	if err := database.DB.Create(&newUser).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Username sudah dipakai!"})
+	if err := h.users.Create(&newUser); err != nil {
+		utils.RespondError(c, utils.ErrUsernameTaken.Wrap(err))
 		return
 	}
+	h.recordAuditAs(c, newUser, AuditRegister, "user", newUser.ID, nil, auditUserSnapshot(newUser))
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Registrasi berhasil! Mode Trial aktif selama 24 jam.",
+		"message": utils.T(utils.Lang(c), "msg.register_success"),
 		"user": gin.H{
 			"username":      newUser.Username,
 			"status":        newUser.Status,
@@ -113,7 +129,7 @@ func Register(c *gin.Context) {
 }
 
 // FUNGSI KHUSUS: Buat Super Admin (Hanya bisa sekali pakai atau pakai Secret Key)
-func RegisterOwner(c *gin.Context) {
+func (h *Handler) RegisterOwner(c *gin.Context) {
 	var input struct {
 		Username string `json:"username" binding:"required"`
 		Password string `json:"password" binding:"required"`
@@ -121,19 +137,32 @@ func RegisterOwner(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak lengkap", "details": err.Error()})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
 	// 1. Cek Kunci Rahasia (Hardcode di sini biar simpel, atau ambil dari .env)
 	// Pastikan secret ini SAMA dengan yang kamu kirim di Postman nanti
 	if input.Secret != "syukur_owner_2025" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Kunci rahasia salah! Anda bukan owner."})
+		utils.RespondError(c, utils.ErrOwnerSecretInvalid)
 		return
 	}
 
-	// 2. Hash Password
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	// 2. Validasi & Hash Password (Owner ikut aturan yang sama)
+	input.Username = utils.NormalizeUsername(input.Username)
+	if verr := utils.ValidateUsername(input.Username); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+	if verr := utils.ValidatePassword(input.Password, input.Username); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 
 	// 3. Buat User dengan Level Tertinggi
 	superAdmin := models.User{
@@ -146,13 +175,14 @@ func RegisterOwner(c *gin.Context) {
 	}
 
 	// 4. Simpan ke Database
-	if err := database.DB.Create(&superAdmin).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Gagal buat admin. Username mungkin sudah ada."})
+	if err := h.users.Create(&superAdmin); err != nil {
+		utils.RespondError(c, utils.ErrUsernameTaken.Wrap(err))
 		return
 	}
+	h.recordAuditAs(c, superAdmin, AuditOwnerSetup, "user", superAdmin.ID, nil, auditUserSnapshot(superAdmin))
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "👑 Super Admin berhasil dibuat!",
+		"message": utils.T(utils.Lang(c), "msg.owner_created"),
 		"data": gin.H{
 			"username": superAdmin.Username,
 			"role":     superAdmin.Role,
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"bytes"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tombol pilihan periode /export
+const (
+	callbackExportThisMonth = "export_this_month"
+	callbackExportLastMonth = "export_last_month"
+)
+
+// MonthlyReportHour: laporan bulanan otomatis baru dikirim mulai jam ini (waktu user) di awal bulan
+const MonthlyReportHour = 7
+
+// handleExport: /export (tombol bulan ini / bulan lalu), /export 11 [2025], /export auto on|off
+func (h *Handler) handleExport(chatID int64, user *models.User, lang, args string) {
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 0:
+		keyboard := &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{
+				{{Text: utils.T(lang, "bot.button.export_this_month"), CallbackData: callbackExportThisMonth}, {Text: utils.T(lang, "bot.button.export_last_month"), CallbackData: callbackExportLastMonth}},
+			},
+		}
+		sendReply(chatID, utils.T(lang, "bot.export_pick"), keyboard)
+		return
+	case strings.EqualFold(fields[0], "auto"):
+		h.handleMonthlyReportToggle(chatID, user, lang, fields[1:])
+		return
+	case len(fields) > 2:
+		sendReply(chatID, utils.T(lang, "bot.export_usage"), nil)
+		return
+	}
+
+	// Tahun default = tahun ini di zona waktu user
+	q := utils.PeriodQuery{Month: fields[0], Year: strconv.Itoa(userNow(user).Year())}
+	if len(fields) == 2 {
+		q.Year = fields[1]
+	}
+	h.sendExport(chatID, user, lang, q)
+}
+
+// sendExport: kirim workbook Excel (sama dengan GET /api/export) untuk periode q ke chat
+func (h *Handler) sendExport(chatID int64, user *models.User, lang string, q utils.PeriodQuery) {
+	q.MonthStartDay = monthStartDay(user)
+	period, appErr := utils.ResolvePeriod(q, userNow(user))
+	if appErr != nil {
+		sendReply(chatID, utils.T(lang, "bot.export_usage"), nil)
+		return
+	}
+
+	report, err := h.exportService.Report(user.ID, period.From, period.To, utils.UserLocation(user.Timezone))
+	if err == nil {
+		err = sendReport(chatID, lang, report, "bot.export_caption")
+	}
+	if err != nil {
+		log.Printf("[BOT] Gagal kirim laporan user %d: %v", user.ID, err)
+		sendReply(chatID, botErrorText(lang, err), nil)
+	}
+}
+
+// handleMonthlyReportToggle: /export auto on|off
+func (h *Handler) handleMonthlyReportToggle(chatID int64, user *models.User, lang string, args []string) {
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		sendReply(chatID, utils.T(lang, "bot.export_usage"), nil)
+		return
+	}
+
+	enabled := args[0] == "on"
+	setMonthlyReport(user, enabled, time.Now())
+	fields := map[string]interface{}{"monthly_report": user.MonthlyReport, "last_monthly_report": user.LastMonthlyReport}
+	if err := h.users.UpdateFields(user.ID, fields); err != nil {
+		log.Printf("[BOT] Gagal ubah laporan bulanan user %d: %v", user.ID, err)
+		sendReply(chatID, utils.T(lang, "INTERNAL_ERROR"), nil)
+		return
+	}
+
+	if enabled {
+		sendReply(chatID, utils.T(lang, "bot.export_auto_on", MonthlyReportHour), nil)
+	} else {
+		sendReply(chatID, utils.T(lang, "bot.export_auto_off"), nil)
+	}
+}
+
+// sendReport menulis laporan sebagai XLSX lalu mengirimnya lewat sendDocument
+func sendReport(chatID int64, lang string, report *exporter.Report, captionKey string) error {
+	var file bytes.Buffer
+	if err := exporter.Write(&file, exporter.FormatXLSX, report); err != nil {
+		return err
+	}
+	caption := utils.T(lang, captionKey, report.PeriodLabel(), report.Count,
+		money.Format(report.Income, report.Currency), money.Format(report.Expense, report.Currency))
+	return sendDocument(chatID, exporter.FormatXLSX.FileName(report.GeneratedAt), file.Bytes(), caption)
+}
+
+// lastMonthPeriod: periode "bulan lalu" versi user (zona waktu & tanggal awal bulan)
+func lastMonthPeriod(user *models.User, now time.Time) utils.Period {
+	q := utils.PeriodQuery{Period: utils.PeriodLastMonth, MonthStartDay: monthStartDay(user)}
+	period, _ := utils.ResolvePeriod(q, now.In(utils.UserLocation(user.Timezone)))
+	return period
+}
+
+// monthlyReportKey: penanda periode laporan bulanan (tanggal awal periode)
+func monthlyReportKey(period utils.Period) string {
+	return period.From.Format("2006-01-02")
+}
+
+// setMonthlyReport menyalakan / mematikan laporan bulanan otomatis.
+// Saat baru dinyalakan, bulan lalu dianggap sudah terkirim: laporan pertama datang di awal bulan berikutnya.
+func setMonthlyReport(user *models.User, enabled bool, now time.Time) {
+	if enabled && !user.MonthlyReport {
+		user.LastMonthlyReport = monthlyReportKey(lastMonthPeriod(user, now))
+	}
+	user.MonthlyReport = enabled
+}
+
+// SendMonthlyReports mengirim laporan Excel bulan lalu ke chat Telegram user yang menyalakan
+// laporan bulanan. Dipanggil berkala (jobs.StartMonthlyReports); tiap periode hanya dikirim sekali,
+// mulai jam MonthlyReportHour di hari pertama bulan baru user. Bulan tanpa transaksi dilewati.
+// Gagal kirim ke satu user cukup dicatat, dicoba lagi di putaran berikutnya.
+func (h *Handler) SendMonthlyReports(now time.Time) error {
+	users, err := h.users.ListMonthlyReport()
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		user := &users[i]
+		period := lastMonthPeriod(user, now)
+		key := monthlyReportKey(period)
+		if user.LastMonthlyReport == key || now.Before(period.To.Add(MonthlyReportHour*time.Hour)) {
+			continue
+		}
+
+		report, err := h.exportService.Report(user.ID, period.From, period.To, utils.UserLocation(user.Timezone))
+		if err == nil && report.Count > 0 {
+			err = sendReport(*user.TelegramID, utils.UserLanguage(user.Language), report, "bot.monthly_caption")
+		}
+		if err != nil {
+			log.Printf("[REPORT] Gagal kirim laporan bulanan user %d: %v", user.ID, err)
+			continue
+		}
+
+		if err := h.users.UpdateFields(user.ID, map[string]interface{}{"last_monthly_report": key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/repository"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"errors"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bulkTransactionInput: satu item POST /api/transactions/bulk.
+// Field sama dengan CreateTransaction, ditambah tanggal opsional.
+type bulkTransactionInput struct {
+	Type     string       `json:"type"`
+	Amount   string       `json:"amount"`
+	Category string       `json:"category"`
+	Note     string       `json:"note"`
+	Currency string       `json:"currency"`
+	WalletID *uint        `json:"wallet_id"`
+	Date     string       `json:"date"` // Opsional: RFC3339 atau YYYY-MM-DD (zona waktu user), default sekarang
+	Splits   []splitInput `json:"splits"`
+}
+
+// POST /api/transactions/bulk {"transactions": [{...}, ...]}
+// Semua tersimpan atau tidak sama sekali. Kalau ada item yang tidak valid: 422 BULK_HAS_ERRORS
+// dengan error per item di "results".
+func (h *Handler) CreateTransactionsBulk(c *gin.Context) {
+	userID := getUserID(c)
+
+	var input struct {
+		Transactions []bulkTransactionInput `json:"transactions"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	if len(input.Transactions) == 0 {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("transactions", utils.CodeFieldRequired))
+		return
+	}
+	if len(input.Transactions) > services.BulkMaxItems {
+		utils.RespondError(c, utils.ErrBulkTooLarge.WithField("transactions", utils.CodeBulkTooLarge))
+		return
+	}
+
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	loc := utils.UserLocation(user.Timezone)
+
+	// Mata uang per kombinasi dompet + input cukup dicari sekali
+	type currencyKey struct {
+		walletID uint
+		currency string
+	}
+	currencies := make(map[currencyKey]string)
+
+	trx := make([]models.Transaction, len(input.Transactions))
+	invalid := make(map[int]error)
+	for i, item := range input.Transactions {
+		key := currencyKey{currency: item.Currency}
+		if item.WalletID != nil {
+			key.walletID = *item.WalletID
+		}
+		currency, found := currencies[key]
+		if !found {
+			if currency, err = h.trxService.ResolveCurrency(userID, item.WalletID, item.Currency); err != nil {
+				invalid[i] = serviceError(err)
+				continue
+			}
+			currencies[key] = currency
+		}
+
+		amount, err := money.Parse(item.Amount, currency)
+		if err != nil {
+			invalid[i] = utils.ErrInvalidAmount
+			continue
+		}
+		at, ok := bulkDate(item.Date, loc)
+		if !ok {
+			invalid[i] = utils.ErrInvalidInput.WithField("date", utils.CodeFieldInvalid)
+			continue
+		}
+		splits, appErr := parseSplits(item.Splits, currency)
+		if appErr != nil {
+			invalid[i] = appErr
+			continue
+		}
+		trx[i] = models.Transaction{
+			Amount:    amount,
+			Currency:  currency,
+			WalletID:  item.WalletID,
+			Type:      models.TransactionType(item.Type),
+			Category:  item.Category,
+			Note:      item.Note,
+			CreatedAt: at,
+			Splits:    splits,
+		}
+	}
+
+	invalid, alert, err := h.trxService.CreateMany(userID, trx, invalid)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	lang := utils.Lang(c)
+	results := make([]gin.H, len(trx))
+	if len(invalid) > 0 {
+		for i := range trx {
+			results[i] = gin.H{"index": i, "status": "valid"}
+			if err, found := invalid[i]; found {
+				results[i] = gin.H{"index": i, "status": "invalid", "error": bulkItemError(lang, err)}
+			}
+		}
+		utils.RespondErrorWith(c, utils.ErrBulkHasErrors.WithArgs(len(invalid)), gin.H{"results": results})
+		return
+	}
+
+	for i, t := range trx {
+		results[i] = gin.H{"index": i, "status": "created", "id": t.ID}
+	}
+	h.recordAudit(c, AuditBulkCreate, "transaction", nil, nil, gin.H{"created": len(trx)})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": utils.T(lang, "msg.transactions_bulk_saved", len(trx)),
+		"created": len(trx),
+		"results": results,
+		"data":    trx,
+		"alert":   alert,
+	})
+}
+
+// bulkDate: tanggal item opsional, RFC3339 atau YYYY-MM-DD (tengah malam di zona waktu user)
+func bulkDate(raw string, loc *time.Location) (time.Time, bool) {
+	if raw == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation("2006-01-02", raw, loc)
+	return t, err == nil
+}
+
+// bulkItemError: error satu item dalam format detail field
+func bulkItemError(lang string, err error) gin.H {
+	appErr := serviceError(err)
+	code := appErr.Code
+	if len(appErr.Details) > 0 {
+		code = appErr.Details[0].Code
+	}
+	return gin.H{"field": appErr.Field(), "code": code, "message": utils.T(lang, code, appErr.Args...)}
+}
+
+// POST /api/transactions/bulk-delete
+// Body {"ids": [1, 2, 3]} dan/atau filter query string yang sama dengan GET /api/transactions
+// (?category=Makan&period=last_month, ...). Semua yang cocok masuk tong sampah sekaligus.
+// Tanpa ID maupun filter ditolak, supaya tidak menghapus semua transaksi tanpa sengaja.
+func (h *Handler) DeleteTransactionsBulk(c *gin.Context) {
+	userID := getUserID(c)
+
+	var input struct {
+		IDs []uint `json:"ids"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	if len(input.IDs) > services.BulkMaxItems {
+		utils.RespondError(c, utils.ErrBulkTooLarge.WithField("ids", utils.CodeBulkTooLarge))
+		return
+	}
+
+	filter, _, appErr := h.listFilter(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+	if len(input.IDs) == 0 && bulkFilterEmpty(filter) {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("ids", utils.CodeFieldRequired))
+		return
+	}
+	filter.IDs = input.IDs
+
+	deleted, err := h.transactions.DeleteMatching(filter)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	// Dengan daftar ID: hasil per ID yang diminta (urutan request), selain itu per transaksi yang terhapus
+	var results []gin.H
+	if len(input.IDs) > 0 {
+		done := make(map[uint]bool, len(deleted))
+		for _, id := range deleted {
+			done[id] = true
+		}
+		seen := make(map[uint]bool, len(input.IDs))
+		for _, id := range input.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			status := "not_found"
+			if done[id] {
+				status = "deleted"
+			}
+			results = append(results, gin.H{"id": id, "status": status})
+		}
+	} else {
+		results = make([]gin.H, len(deleted))
+		for i, id := range deleted {
+			results[i] = gin.H{"id": id, "status": "deleted"}
+		}
+	}
+	if len(deleted) > 0 {
+		h.recordAudit(c, AuditBulkDelete, "transaction", nil, gin.H{"ids": deleted}, nil)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": utils.T(utils.Lang(c), "msg.transactions_bulk_deleted", len(deleted), utils.TrashRetentionDays()),
+		"deleted": len(deleted),
+		"results": results,
+	})
+}
+
+// bulkFilterEmpty: true kalau tidak ada satu pun filter query (periode, kategori, dst.)
+func bulkFilterEmpty(filter repository.TransactionFilter) bool {
+	return filter.Type == "" && filter.Search == "" && len(filter.Categories) == 0 && len(filter.Tags) == 0 &&
+		filter.Currency == "" && filter.From.IsZero() && filter.To.IsZero() && filter.MinAmount == 0 && filter.MaxAmount == 0
+}
+
+// POST /api/transactions/recategorize {"from": "makan", "to": "Makan", "type": "expense", "ids": [1, 2]}
+// Ganti kategori (pencocokan tidak peka huruf besar/kecil). type & ids opsional.
+func (h *Handler) RecategorizeTransactions(c *gin.Context) {
+	userID := getUserID(c)
+
+	var input struct {
+		From string                 `json:"from" binding:"required"`
+		To   string                 `json:"to" binding:"required"`
+		Type models.TransactionType `json:"type"`
+		IDs  []uint                 `json:"ids"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	if len(input.IDs) > services.BulkMaxItems {
+		utils.RespondError(c, utils.ErrBulkTooLarge.WithField("ids", utils.CodeBulkTooLarge))
+		return
+	}
+
+	changes, err := h.trxService.Recategorize(userID, input.From, input.To, input.Type, input.IDs)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	to := strings.TrimSpace(input.To)
+	results := make([]gin.H, len(changes))
+	ids := make([]uint, len(changes))
+	for i, change := range changes {
+		results[i] = gin.H{"id": change.ID, "status": "updated", "from": change.Category}
+		ids[i] = change.ID
+	}
+	if len(changes) > 0 {
+		h.recordAudit(c, AuditRecategorize, "transaction", nil,
+			gin.H{"category": input.From, "ids": ids}, gin.H{"category": to})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": utils.T(utils.Lang(c), "msg.transactions_recategorized", len(changes), to),
+		"updated": len(changes),
+		"results": results,
+	})
+}
+
+// handleRecategorize: perintah bot /recat <kategori lama> <kategori baru>
+func (h *Handler) handleRecategorize(chatID int64, user *models.User, lang string, args []string) {
+	if len(args) != 2 {
+		sendReply(chatID, utils.T(lang, "bot.recat_usage"), nil)
+		return
+	}
+	changes, err := h.trxService.Recategorize(user.ID, args[0], args[1], "", nil)
+	if err != nil {
+		sendReply(chatID, botErrorText(lang, err), nil)
+		return
+	}
+	if len(changes) == 0 {
+		sendReply(chatID, utils.T(lang, "bot.recat_none", html.EscapeString(args[0])), nil)
+		return
+	}
+	sendReply(chatID, utils.T(lang, "bot.recat_done", len(changes), html.EscapeString(args[0]), html.EscapeString(args[1])), nil)
+}
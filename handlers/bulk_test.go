@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"backend-gin/models"
+	"backend-gin/utils"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateTransactionsBulk(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	// Satu item salah: tidak ada yang disimpan, error per item
+	res := app.do(http.MethodPost, "/api/transactions/bulk", token, map[string]interface{}{
+		"transactions": []map[string]interface{}{
+			{"type": "expense", "amount": "25000", "category": "Makan"},
+			{"type": "expense", "amount": "abc", "category": "Makan"},
+			{"type": "income", "amount": "5000000", "category": "Gaji", "date": "31-02-2025"},
+		},
+	})
+	expectError(t, res, http.StatusUnprocessableEntity, utils.CodeBulkHasErrors)
+	results := res.Body["results"].([]interface{})
+	if len(results) != 3 || results[0].(map[string]interface{})["status"] != "valid" {
+		t.Fatalf("results = %v", results)
+	}
+	if item := results[1].(map[string]interface{}); item["error"].(map[string]interface{})["field"] != "amount" {
+		t.Errorf("item 2 = %v", item)
+	}
+	if item := results[2].(map[string]interface{}); item["error"].(map[string]interface{})["field"] != "date" {
+		t.Errorf("item 3 = %v", item)
+	}
+	var count int64
+	app.db.Model(&models.Transaction{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d transaksi tersimpan padahal ada item yang salah", count)
+	}
+
+	res = app.do(http.MethodPost, "/api/transactions/bulk", token, map[string]interface{}{
+		"transactions": []map[string]interface{}{
+			{"type": "expense", "amount": "25000", "category": "Makan", "date": "2025-03-01"},
+			{"type": "income", "amount": "5000000", "category": "Gaji"},
+		},
+	})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["created"] != float64(2) {
+		t.Fatalf("created = %v", res.Body["created"])
+	}
+	first := res.Body["results"].([]interface{})[0].(map[string]interface{})
+	trx, err := app.repos.Transactions.FindForUser(user.ID, uint(first["id"].(float64)))
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if want := time.Date(2025, time.March, 1, 0, 0, 0, 0, utils.UserLocation(utils.DefaultTimezone)); !trx.CreatedAt.Equal(want) {
+		t.Errorf("created_at = %v, mau %v", trx.CreatedAt, want)
+	}
+
+	res = app.do(http.MethodPost, "/api/transactions/bulk", token, map[string]interface{}{"transactions": []interface{}{}})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+}
+
+func TestDeleteTransactionsBulk(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("sari", "user", "trial")
+	token := app.token(user)
+
+	a := app.createTransaction(user, "expense", 25000, "Makan", time.Now())
+	b := app.createTransaction(user, "expense", 15000, "Kopi", time.Now())
+	app.createTransaction(user, "expense", 10000, "Parkir", time.Now())
+	app.createTransaction(user, "expense", 12000, "Parkir", time.Now())
+	foreign := app.createTransaction(other, "expense", 10000, "Parkir", time.Now())
+
+	// Tanpa ID maupun filter ditolak
+	res := app.do(http.MethodPost, "/api/transactions/bulk-delete", token, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+
+	res = app.do(http.MethodPost, "/api/transactions/bulk-delete", token, map[string]interface{}{"ids": []uint{a.ID, b.ID, foreign.ID}})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["deleted"] != float64(2) {
+		t.Fatalf("deleted = %v", res.Body["deleted"])
+	}
+	if last := res.Body["results"].([]interface{})[2].(map[string]interface{}); last["status"] != "not_found" {
+		t.Errorf("transaksi user lain = %v", last)
+	}
+
+	res = app.do(http.MethodPost, "/api/transactions/bulk-delete?category=parkir", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["deleted"] != float64(2) {
+		t.Fatalf("deleted by filter = %v", res.Body["deleted"])
+	}
+
+	var count int64
+	app.db.Model(&models.Transaction{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d transaksi tersisa", count)
+	}
+	if _, err := app.repos.Transactions.FindForUser(other.ID, foreign.ID); err != nil {
+		t.Fatalf("transaksi user lain ikut terhapus: %v", err)
+	}
+	// Masuk tong sampah, bukan terhapus permanen
+	res = app.do(http.MethodGet, "/api/transactions/trash", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if data := res.Body["data"].([]interface{}); len(data) != 4 {
+		t.Fatalf("tong sampah = %d transaksi", len(data))
+	}
+}
+
+func TestRecategorizeTransactions(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+	app.linkTelegram(user, 777)
+
+	app.createTransaction(user, "expense", 25000, "makan", time.Now())
+	app.createTransaction(user, "expense", 30000, "MAKAN", time.Now())
+	app.createTransaction(user, "expense", 35000, "Makan", time.Now())
+	app.createTransaction(user, "expense", 10000, "Kopi", time.Now())
+
+	res := app.do(http.MethodPost, "/api/transactions/recategorize", token, map[string]string{"from": "makan", "to": " "})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+
+	// Yang sudah "Makan" tidak dihitung
+	res = app.do(http.MethodPost, "/api/transactions/recategorize", token, map[string]string{"from": "makan", "to": "Makan"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["updated"] != float64(2) {
+		t.Fatalf("updated = %v", res.Body["updated"])
+	}
+	if first := res.Body["results"].([]interface{})[0].(map[string]interface{}); first["from"] != "makan" {
+		t.Errorf("kategori lama = %v", first["from"])
+	}
+	var count int64
+	app.db.Model(&models.Transaction{}).Where("category = ?", "Makan").Count(&count)
+	if count != 3 {
+		t.Fatalf("kategori Makan = %d transaksi", count)
+	}
+
+	app.botMessage(777, "/recat kopi Ngopi")
+	app.botMessage(777, "/recat bensin Transport")
+	app.botMessage(777, "/recat kopi")
+	messages, _ := telegram.reset()
+	if len(messages) != 3 {
+		t.Fatalf("messages = %+v", messages)
+	}
+	if !strings.Contains(messages[0].Text, "1 transaksi") || !strings.Contains(messages[1].Text, "Tidak ada transaksi") || !strings.Contains(messages[2].Text, "Format") {
+		t.Fatalf("balasan bot = %+v", messages)
+	}
+}
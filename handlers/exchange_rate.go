@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/admin/exchange-rates
+func (h *Handler) GetExchangeRates(c *gin.Context) {
+	if !isAdmin(c) {
+		utils.RespondError(c, utils.ErrForbidden)
+		return
+	}
+
+	rates, err := h.exchangeRates.List()
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rates})
+}
+
+// POST /api/admin/exchange-rates
+// Body: {"base_currency":"USD","quote_currency":"IDR","rate":"16250","effective_date":"2025-11-01"}
+// Pasangan + tanggal yang sama akan ditimpa.
+func (h *Handler) SaveExchangeRate(c *gin.Context) {
+	if !isAdmin(c) {
+		utils.RespondError(c, utils.ErrForbidden)
+		return
+	}
+
+	var input struct {
+		BaseCurrency  string `json:"base_currency" binding:"required"`
+		QuoteCurrency string `json:"quote_currency" binding:"required"`
+		Rate          string `json:"rate" binding:"required"` // String supaya presisi tidak hilang
+		EffectiveDate string `json:"effective_date"`          // YYYY-MM-DD, default hari ini
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+
+	base := money.Normalize(input.BaseCurrency)
+	if base == "" {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("base_currency", utils.CodeCurrencyInvalid))
+		return
+	}
+	quote := money.Normalize(input.QuoteCurrency)
+	if quote == "" || quote == base {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("quote_currency", utils.CodeCurrencyInvalid))
+		return
+	}
+	if _, err := money.ParseRate(input.Rate); err != nil {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("rate", utils.CodeRateInvalid))
+		return
+	}
+
+	// Tanggal efektif disimpan tengah malam UTC (kurs berlaku per hari)
+	now := time.Now()
+	effective := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if input.EffectiveDate != "" {
+		var err error
+		effective, err = time.Parse("2006-01-02", input.EffectiveDate)
+		if err != nil {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("effective_date", utils.CodeFieldInvalid))
+			return
+		}
+	}
+
+	entry := models.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          strings.TrimSpace(input.Rate),
+		EffectiveDate: effective,
+	}
+	if err := h.exchangeRates.Save(&entry); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminRateSave, "exchange_rate", entry.ID, nil, entry)
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.exchange_rate_saved"), "data": entry})
+}
+
+// DELETE /api/admin/exchange-rates/:id
+func (h *Handler) DeleteExchangeRate(c *gin.Context) {
+	if !isAdmin(c) {
+		utils.RespondError(c, utils.ErrForbidden)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrRateNotFound)
+		return
+	}
+	rate, err := h.exchangeRates.FindByID(uint(id))
+	if err != nil {
+		utils.RespondError(c, utils.ErrRateNotFound.Wrap(err))
+		return
+	}
+
+	if err := h.exchangeRates.Delete(rate); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditAdminRateDelete, "exchange_rate", rate.ID, rate, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.exchange_rate_deleted")})
+}
@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportFormats(t *testing.T) {
+	app := newTestApp(t)
+	source := app.createUser("budi", "user", "trial")
+	token := app.token(source)
+
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	app.createTransaction(source, "income", 7500000, "Gaji", time.Date(2025, time.April, 1, 9, 15, 0, 0, jakarta))
+	app.createTransaction(source, "expense", 32500, "Makan", time.Date(2025, time.April, 2, 23, 45, 0, 0, jakarta))
+	app.createTransaction(source, "expense", 10000, "Parkir", time.Date(2025, time.May, 1, 8, 0, 0, 0, jakarta))
+
+	// CSV hasil export bisa langsung diimport ulang
+	res := app.do(http.MethodGet, "/api/export?format=csv&month=4&year=2025", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if lines := strings.Split(strings.TrimSpace(string(res.Raw)), "\n"); len(lines) != 3 ||
+		lines[1] != "1,2025-04-02,23:45,EXPENSE,Makan,,IDR,32500,32500," {
+		t.Fatalf("csv = %q", res.Raw)
+	}
+	target := app.createUser("sari", "user", "trial")
+	res = app.upload("/api/import", app.token(target), "laporan.csv", res.Raw, map[string]string{"commit": "true"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["imported"] != float64(2) {
+		t.Fatalf("imported = %v\nbody: %s", res.Body["imported"], res.Raw)
+	}
+
+	// JSON lines: satu transaksi per baris, terbaru dulu
+	res = app.do(http.MethodGet, "/api/export?format=jsonl", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	lines := strings.Split(strings.TrimSpace(string(res.Raw)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("jsonl = %q", res.Raw)
+	}
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first["category"] != "Parkir" || first["amount"] != float64(10000) || first["base_currency"] != "IDR" ||
+		first["date"] != "2025-05-01T08:00:00+07:00" {
+		t.Errorf("baris pertama jsonl = %v", first)
+	}
+
+	// ODS: zip dengan mimetype di depan
+	res = app.do(http.MethodGet, "/api/export?format=ods", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	zr, err := zip.NewReader(bytes.NewReader(res.Raw), int64(len(res.Raw)))
+	if err != nil || zr.File[0].Name != "mimetype" {
+		t.Fatalf("ods tidak valid: %v", err)
+	}
+
+	res = app.do(http.MethodGet, "/api/export?format=pdf&period=all", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if !bytes.HasPrefix(res.Raw, []byte("%PDF-1.4")) || !bytes.HasSuffix(res.Raw, []byte("%%EOF\n")) {
+		t.Fatalf("pdf tidak valid: %q", res.Raw[:min(len(res.Raw), 64)])
+	}
+
+	expectError(t, app.do(http.MethodGet, "/api/export?format=docx", token, nil), http.StatusBadRequest, utils.CodeExportFormatInvalid)
+}
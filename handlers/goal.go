@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/goals: semua target tabungan beserta progresnya
+func (h *Handler) GetGoals(c *gin.Context) {
+	userID := getUserID(c)
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+
+	goals, err := h.goalService.List(userID, time.Now(), utils.UserLocation(user.Timezone))
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": goals})
+}
+
+// POST /api/goals {"name", "target_amount", "deadline": "2026-12-31", "wallet_id" | "category", "currency"}
+// Tertaut ke dompet (isi = saldo dompet) atau kategori (isi = pengeluaran kategori itu sejak target dibuat).
+func (h *Handler) CreateGoal(c *gin.Context) {
+	userID := getUserID(c)
+
+	var input struct {
+		Name         string `json:"name" binding:"required,max=100"`
+		TargetAmount string `json:"target_amount" binding:"required"`
+		Deadline     string `json:"deadline"` // Opsional, YYYY-MM-DD (hari terakhir, inklusif)
+		WalletID     *uint  `json:"wallet_id"`
+		Category     string `json:"category"`
+		Currency     string `json:"currency"` // Opsional untuk target kategori, default mata uang dasar user
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	currency, err := h.goalService.ResolveCurrency(userID, input.WalletID, input.Category, input.Currency)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+	target, err := money.Parse(input.TargetAmount, currency)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInvalidInput.WithField("target_amount", utils.CodeInvalidAmount))
+		return
+	}
+
+	goalInput := services.GoalInput{
+		Name:         input.Name,
+		TargetAmount: target,
+		Currency:     currency,
+		WalletID:     input.WalletID,
+		Category:     input.Category,
+	}
+	if input.Deadline != "" {
+		deadline, err := time.ParseInLocation("2006-01-02", input.Deadline, utils.UserLocation(user.Timezone))
+		if err != nil {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("deadline", utils.CodeDateInvalid))
+			return
+		}
+		goalInput.Deadline = &deadline
+	}
+
+	goal, err := h.goalService.Create(userID, goalInput, time.Now())
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+	progress, err := h.goalService.Progress(goal, time.Now(), utils.UserLocation(user.Timezone))
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.goal_created"), "data": progress})
+}
+
+// GET /api/goals/:id: progres satu target
+func (h *Handler) GetGoal(c *gin.Context) {
+	goal, user, ok := h.goalFromParam(c)
+	if !ok {
+		return
+	}
+	progress, err := h.goalService.Progress(goal, time.Now(), utils.UserLocation(user.Timezone))
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": progress})
+}
+
+// POST /api/goals/:id/contributions {"amount": "500000", "note": "gaji Oktober"}
+// Setoran dicatat sebagai transaksi (lihat services.GoalService.Contribute).
+func (h *Handler) ContributeGoal(c *gin.Context) {
+	goal, user, ok := h.goalFromParam(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Amount string `json:"amount" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	amount, err := money.Parse(input.Amount, goal.Currency)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInvalidAmount)
+		return
+	}
+
+	trx, progress, alert, err := h.goalService.Contribute(goal, amount, input.Note, time.Now(), utils.UserLocation(user.Timezone))
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	lang := utils.Lang(c)
+	message := utils.T(lang, "msg.goal_contributed")
+	if progress.JustReached {
+		message = utils.T(lang, "msg.goal_reached", goal.Name)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "data": trx, "goal": progress, "alert": alert})
+}
+
+// DELETE /api/goals/:id (transaksi setorannya tidak ikut terhapus)
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	goal, _, ok := h.goalFromParam(c)
+	if !ok {
+		return
+	}
+	if err := h.goalService.Delete(goal); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.goal_deleted")})
+}
+
+// goalFromParam: target milik user dari :id (response error sudah dikirim kalau ok=false)
+func (h *Handler) goalFromParam(c *gin.Context) (*models.SavingsGoal, *models.User, bool) {
+	userID := getUserID(c)
+	id, ok := uintParam(c, "id")
+	if !ok {
+		utils.RespondError(c, utils.ErrGoalNotFound)
+		return nil, nil, false
+	}
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return nil, nil, false
+	}
+	goal, err := h.goalService.Find(userID, id)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return nil, nil, false
+	}
+	return goal, user, true
+}
+
+// handleTarget: perintah bot /target (lihat progres) dan /target <ID> <nominal> [catatan] (setor)
+func (h *Handler) handleTarget(chatID int64, user *models.User, lang string, args []string) {
+	loc := utils.UserLocation(user.Timezone)
+	if len(args) == 0 {
+		goals, err := h.goalService.List(user.ID, time.Now(), loc)
+		if err != nil {
+			log.Printf("[BOT] Gagal hitung target tabungan user %d: %v", user.ID, err)
+			sendReply(chatID, botErrorText(lang, err), nil)
+			return
+		}
+		if len(goals) == 0 {
+			sendReply(chatID, utils.T(lang, "bot.goal_empty"), nil)
+			return
+		}
+		lines := []string{utils.T(lang, "bot.goal_header")}
+		var congrats []string
+		for _, goal := range goals {
+			lines = append(lines, goalText(lang, goal, loc))
+			if goal.JustReached {
+				congrats = append(congrats, goalCongrats(lang, goal))
+			}
+		}
+		sendReply(chatID, strings.Join(append(lines, congrats...), "\n\n"), nil)
+		return
+	}
+
+	if len(args) < 2 {
+		sendReply(chatID, utils.T(lang, "bot.goal_usage"), nil)
+		return
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		sendReply(chatID, utils.T(lang, "bot.goal_usage"), nil)
+		return
+	}
+	goal, err := h.goalService.Find(user.ID, uint(id))
+	if err != nil {
+		sendReply(chatID, botErrorText(lang, err), nil)
+		return
+	}
+	amount, err := money.Parse(args[1], goal.Currency)
+	if err != nil {
+		sendReply(chatID, utils.T(lang, "bot.invalid_number"), nil)
+		return
+	}
+
+	trx, progress, alert, err := h.goalService.Contribute(goal, amount, strings.Join(args[2:], " "), time.Now(), loc)
+	if err != nil {
+		log.Printf("[BOT] Gagal simpan setoran target %d user %d: %v", goal.ID, user.ID, err)
+		sendReply(chatID, botErrorText(lang, err), nil)
+		return
+	}
+	text := utils.T(lang, "bot.goal_saved", money.Format(trx.Amount, trx.Currency), html.EscapeString(goal.Name), trx.ID) +
+		"\n\n" + goalText(lang, *progress, loc)
+	if progress.JustReached {
+		text += "\n\n" + goalCongrats(lang, *progress)
+	}
+	if alert != "" {
+		text += "\n\n🚨 " + alert
+	}
+	sendReply(chatID, text, nil)
+}
+
+// goalText: satu target di balasan bot, dengan progress bar
+func goalText(lang string, goal services.GoalProgress, loc *time.Location) string {
+	text := utils.T(lang, "bot.goal_line", html.EscapeString(goal.Name), goal.ID, progressBar(goal.Percent), goal.Percent,
+		money.Format(goal.Saved, goal.Currency), money.Format(goal.TargetAmount, goal.Currency))
+	switch {
+	case goal.Reached:
+		text += utils.T(lang, "bot.goal_done")
+	case goal.DeadlinePassed:
+		text += utils.T(lang, "bot.goal_overdue", goal.Deadline.In(loc).Format("02-01-2006"), money.Format(goal.Remaining, goal.Currency))
+	case goal.MonthlyNeeded != nil:
+		text += utils.T(lang, "bot.goal_monthly", money.Format(*goal.MonthlyNeeded, goal.Currency), goal.Deadline.In(loc).Format("02-01-2006"))
+	}
+	return text
+}
+
+func goalCongrats(lang string, goal services.GoalProgress) string {
+	return utils.T(lang, "bot.goal_congrats", html.EscapeString(goal.Name), money.Format(goal.Saved, goal.Currency))
+}
+
+// progressBar: 10 kotak, mis. ▓▓▓▓░░░░░░ untuk 40%
+func progressBar(percent float64) string {
+	filled := int(percent / 10)
+	if filled > 10 {
+		filled = 10
+	}
+	return strings.Repeat("▓", filled) + strings.Repeat("░", 10-filled)
+}
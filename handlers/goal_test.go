@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"backend-gin/models"
+	"backend-gin/utils"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSavingsGoalCategory(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("sari", "user", "trial")
+	token := app.token(user)
+
+	// Tanggal 1, empat bulan lagi: selalu tepat 4 bulan dari hari ini
+	now := time.Now().In(utils.UserLocation(utils.DefaultTimezone))
+	deadline := time.Date(now.Year(), now.Month()+4, 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+
+	res := app.do(http.MethodPost, "/api/goals", token, map[string]interface{}{
+		"name": "Dana Darurat", "target_amount": "3000000", "category": "Tabungan", "wallet_id": 1,
+	})
+	expectError(t, res, http.StatusBadRequest, utils.CodeGoalLinkInvalid)
+	res = app.do(http.MethodPost, "/api/goals", token, map[string]interface{}{
+		"name": "Dana Darurat", "target_amount": "3000000", "category": "Tabungan", "deadline": "2020-01-01",
+	})
+	expectError(t, res, http.StatusBadRequest, utils.CodeGoalDeadlineInvalid)
+
+	res = app.do(http.MethodPost, "/api/goals", token, map[string]interface{}{
+		"name": "Dana Darurat", "target_amount": "3.000.000", "category": "Tabungan", "deadline": deadline,
+	})
+	expectStatus(t, res, http.StatusOK)
+	goal := res.Body["data"].(map[string]interface{})
+	if goal["currency"] != "IDR" || goal["target_amount"] != float64(3000000) || goal["saved"] != float64(0) {
+		t.Fatalf("goal = %v", goal)
+	}
+	path := fmt.Sprintf("/api/goals/%d", int(goal["id"].(float64)))
+
+	// Transaksi sebelum target dibuat tidak dihitung
+	app.createTransaction(user, "expense", 750000, "Tabungan", time.Now().Add(-time.Hour))
+	res = app.do(http.MethodPost, path+"/contributions", token, map[string]string{"amount": "1000000"})
+	expectStatus(t, res, http.StatusOK)
+	trx := res.Body["data"].(map[string]interface{})
+	if trx["type"] != "expense" || trx["category"] != "Tabungan" || trx["note"] != "Dana Darurat" {
+		t.Fatalf("transaksi setoran = %v", trx)
+	}
+	app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "income", "amount": "200000", "category": "tabungan"})
+
+	res = app.do(http.MethodGet, path, token, nil)
+	expectStatus(t, res, http.StatusOK)
+	goal = res.Body["data"].(map[string]interface{})
+	if goal["saved"] != float64(800000) || goal["remaining"] != float64(2200000) || goal["percent"] != 26.7 {
+		t.Fatalf("progres = %v", goal)
+	}
+	if goal["months_left"] != float64(4) || goal["monthly_needed"] != float64(550000) || goal["reached"] != false {
+		t.Fatalf("setoran per bulan = %v", goal)
+	}
+
+	// Lewat deadline: sisanya harus disetor sekaligus
+	app.db.Model(&models.SavingsGoal{}).Where("id = ?", goal["id"]).Update("deadline", now.AddDate(0, 0, -2))
+	res = app.do(http.MethodGet, "/api/goals", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	list := res.Body["data"].([]interface{})
+	if len(list) != 1 {
+		t.Fatalf("goals = %v", list)
+	}
+	if g := list[0].(map[string]interface{}); g["deadline_passed"] != true || g["months_left"] != float64(0) || g["monthly_needed"] != float64(2200000) {
+		t.Fatalf("lewat deadline = %v", g)
+	}
+
+	res = app.do(http.MethodGet, path, app.token(other), nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeGoalNotFound)
+
+	// Hapus target, transaksi setoran tetap ada
+	res = app.do(http.MethodDelete, path, token, nil)
+	expectStatus(t, res, http.StatusOK)
+	var count int64
+	app.db.Model(&models.Transaction{}).Where("user_id = ? AND category = ?", user.ID, "Tabungan").Count(&count)
+	if count != 2 {
+		t.Fatalf("transaksi Tabungan = %d", count)
+	}
+}
+
+func TestSavingsGoalWalletAndBot(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+	app.linkTelegram(user, 777)
+
+	res := app.do(http.MethodPost, "/api/wallets", token, map[string]string{"name": "Tabungan Liburan"})
+	expectStatus(t, res, http.StatusOK)
+	walletID := res.Body["data"].(map[string]interface{})["id"]
+	app.do(http.MethodPost, "/api/transactions", token, map[string]interface{}{
+		"type": "income", "amount": "700000", "category": "Gaji", "wallet_id": walletID,
+	})
+
+	res = app.do(http.MethodPost, "/api/goals", token, map[string]interface{}{
+		"name": "Liburan Bali", "target_amount": "1000000", "wallet_id": walletID,
+	})
+	expectStatus(t, res, http.StatusOK)
+	goal := res.Body["data"].(map[string]interface{})
+	if goal["saved"] != float64(700000) || goal["percent"] != float64(70) || goal["monthly_needed"] != nil {
+		t.Fatalf("goal = %v", goal)
+	}
+	id := int(goal["id"].(float64))
+
+	// Dompet yang tertaut target tidak bisa dihapus walau belum ada transaksinya
+	res = app.do(http.MethodPost, "/api/wallets", token, map[string]string{"name": "Dana Pendidikan"})
+	emptyWallet := res.Body["data"].(map[string]interface{})["id"]
+	res = app.do(http.MethodPost, "/api/goals", token, map[string]interface{}{
+		"name": "Kuliah", "target_amount": "50000000", "wallet_id": emptyWallet,
+	})
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodDelete, fmt.Sprintf("/api/wallets/%v", emptyWallet), token, nil)
+	expectError(t, res, http.StatusConflict, utils.CodeWalletHasGoals)
+
+	app.botMessage(777, "/target")
+	app.botMessage(777, "/target 99 1000")
+	app.botMessage(777, "/target "+fmt.Sprint(id))
+	app.botMessage(777, fmt.Sprintf("/target %d 300000 bonus", id))
+	app.botMessage(777, "/target")
+	messages, _ := telegram.reset()
+	if len(messages) != 5 {
+		t.Fatalf("messages = %+v", messages)
+	}
+	if !strings.Contains(messages[0].Text, "Liburan Bali") || !strings.Contains(messages[0].Text, "▓▓▓▓▓▓▓░░░ 70.0%") {
+		t.Errorf("progres = %q", messages[0].Text)
+	}
+	if !strings.Contains(messages[1].Text, "tidak ditemukan") || !strings.Contains(messages[2].Text, "Format") {
+		t.Errorf("balasan error = %q / %q", messages[1].Text, messages[2].Text)
+	}
+	if !strings.Contains(messages[3].Text, "Selamat") || !strings.Contains(messages[3].Text, "▓▓▓▓▓▓▓▓▓▓ 100.0%") {
+		t.Errorf("setoran = %q", messages[3].Text)
+	}
+	// Ucapan selamat cukup sekali
+	if strings.Contains(messages[4].Text, "Selamat") || !strings.Contains(messages[4].Text, "Tercapai") {
+		t.Errorf("progres setelah tercapai = %q", messages[4].Text)
+	}
+
+	var trx models.Transaction
+	app.db.Where("user_id = ? AND category = ?", user.ID, models.GoalCategory).First(&trx)
+	if trx.Type != models.TypeIncome || trx.WalletID == nil || trx.Amount != 300000 || trx.Note != "bonus" {
+		t.Fatalf("transaksi setoran = %+v", trx)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"backend-gin/money"
+	"backend-gin/repository"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"errors"
+)
+
+// Handler menampung semua dependency HTTP handler.
+// Dibuat sekali di main (atau di test) lalu dipasang ke router, tidak ada akses database global.
+type Handler struct {
+	users          repository.UserRepository
+	transactions   repository.TransactionRepository
+	paymentLogs    repository.PaymentLogRepository
+	passwordResets repository.PasswordResetRepository
+	auditLogs      repository.AuditLogRepository
+	wallets        repository.WalletRepository
+	exchangeRates  repository.ExchangeRateRepository
+	statements     repository.StatementRepository
+	goals          repository.GoalRepository
+
+	trxService       *services.TransactionService
+	userService      *services.UserService
+	statementService *services.StatementService
+	exportService    *services.ExportService
+	accountService   *services.AccountService
+	goalService      *services.GoalService
+}
+
+func New(repos *repository.Repositories, svc *services.Services) *Handler {
+	return &Handler{
+		users:            repos.Users,
+		transactions:     repos.Transactions,
+		paymentLogs:      repos.PaymentLogs,
+		passwordResets:   repos.PasswordResets,
+		auditLogs:        repos.AuditLogs,
+		wallets:          repos.Wallets,
+		exchangeRates:    repos.ExchangeRates,
+		statements:       repos.Statements,
+		goals:            repos.Goals,
+		trxService:       svc.Transactions,
+		userService:      svc.Users,
+		statementService: svc.Statements,
+		exportService:    svc.Exports,
+		accountService:   svc.Accounts,
+		goalService:      svc.Goals,
+	}
+}
+
+// serviceError menerjemahkan error dari services ke AppError (error asing dianggap INTERNAL_ERROR)
+func serviceError(err error) *utils.AppError {
+	var missing *services.MissingRateError
+	switch {
+	case errors.As(err, &missing):
+		return utils.ErrRateMissing.WithArgs(missing.From, missing.To).Wrap(err)
+	case errors.Is(err, money.ErrAmountTooLarge):
+		return utils.ErrAmountTooLarge.Wrap(err)
+	case errors.Is(err, services.ErrWalletNotFound):
+		return utils.ErrWalletNotFound
+	case errors.Is(err, services.ErrCurrencyInvalid):
+		return utils.ErrCurrencyInvalid
+	case errors.Is(err, services.ErrCurrencyMismatch):
+		return utils.ErrCurrencyMismatch
+	case errors.Is(err, services.ErrTransactionNotFound):
+		return utils.ErrTransactionNotFound
+	case errors.Is(err, services.ErrStatementNotFound):
+		return utils.ErrStatementNotFound
+	case errors.Is(err, services.ErrStatementEntryNotFound):
+		return utils.ErrEntryNotFound
+	case errors.Is(err, services.ErrStatementEntryLinked):
+		return utils.ErrEntryLinked
+	case errors.Is(err, services.ErrGoalNotFound):
+		return utils.ErrGoalNotFound
+	}
+	return utils.AsAppError(err)
+}
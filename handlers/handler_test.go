@@ -0,0 +1,215 @@
+package handlers_test
+
+import (
+	"backend-gin/database"
+	"backend-gin/handlers"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/router"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testPassword = "RahasiaSekali123"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testApp: aplikasi lengkap (router + handler + repository) di atas SQLite in-memory
+type testApp struct {
+	t       *testing.T
+	db      *gorm.DB
+	repos   *repository.Repositories
+	handler *handlers.Handler
+	router  *gin.Engine
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("TRASH_RETENTION_DAYS", "30")
+
+	db, err := database.Open(database.DriverSQLite, ":memory:", &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	// Satu koneksi saja: tiap koneksi baru ke ":memory:" adalah database kosong yang berbeda
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := database.MigrateUp(db, false); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return newTestAppWith(t, db, repository.New(db))
+}
+
+// newTestAppWith memakai repository yang sudah disiapkan (misalnya dengan fake)
+func newTestAppWith(t *testing.T, db *gorm.DB, repos *repository.Repositories) *testApp {
+	t.Helper()
+	h := handlers.New(repos, services.New(repos))
+	return &testApp{t: t, db: db, repos: repos, handler: h, router: router.New(h, repos.Users, "")}
+}
+
+func (a *testApp) createUser(username, role, status string) *models.User {
+	a.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		a.t.Fatalf("hash password: %v", err)
+	}
+	user := &models.User{
+		Username:    username,
+		Password:    string(hash),
+		Role:        role,
+		Status:      status,
+		TrialEndsAt: time.Now().Add(24 * time.Hour),
+	}
+	if err := a.repos.Users.Create(user); err != nil {
+		a.t.Fatalf("create user %s: %v", username, err)
+	}
+	return user
+}
+
+func (a *testApp) token(user *models.User) string {
+	a.t.Helper()
+	token, err := utils.GenerateToken(user.ID, user.Role, user.SessionVersion)
+	if err != nil {
+		a.t.Fatalf("generate token: %v", err)
+	}
+	return token
+}
+
+func (a *testApp) createTransaction(user *models.User, tipe string, amount int64, category string, at time.Time) *models.Transaction {
+	a.t.Helper()
+	trx := &models.Transaction{UserID: user.ID, Type: models.TransactionType(tipe), Amount: amount, Category: category, CreatedAt: at}
+	if err := a.repos.Transactions.Create(trx); err != nil {
+		a.t.Fatalf("create transaction: %v", err)
+	}
+	return trx
+}
+
+type testResponse struct {
+	Status int
+	Body   map[string]interface{}
+	Raw    []byte
+}
+
+// Code error dari body response (lihat utils.RespondError)
+func (r testResponse) Code() string {
+	code, _ := r.Body["code"].(string)
+	return code
+}
+
+func (a *testApp) do(method, path, token string, body interface{}) testResponse {
+	a.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			a.t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	return a.send(req, token)
+}
+
+// upload: POST multipart dengan satu file ("file") + field form biasa
+func (a *testApp) upload(path, token, filename string, content []byte, fields map[string]string) testResponse {
+	a.t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			a.t.Fatal(err)
+		}
+	}
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		a.t.Fatal(err)
+	}
+	if err := form.Close(); err != nil {
+		a.t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return a.send(req, token)
+}
+
+func (a *testApp) send(req *http.Request, token string) testResponse {
+	a.t.Helper()
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, req)
+
+	res := testResponse{Status: rec.Code, Raw: rec.Body.Bytes()}
+	// Hanya response JSON yang di-decode (export JSON lines juga diawali "{")
+	if strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") && len(res.Raw) > 0 && res.Raw[0] == '{' {
+		if err := json.Unmarshal(res.Raw, &res.Body); err != nil {
+			a.t.Fatalf("decode response %s %s: %v\n%s", req.Method, req.URL.Path, err, res.Raw)
+		}
+	}
+	return res
+}
+
+func expectStatus(t *testing.T, res testResponse, want int) {
+	t.Helper()
+	if res.Status != want {
+		t.Fatalf("status = %d, mau %d\nbody: %s", res.Status, want, res.Raw)
+	}
+}
+
+func expectError(t *testing.T, res testResponse, status int, code string) {
+	t.Helper()
+	expectStatus(t, res, status)
+	if res.Code() != code {
+		t.Fatalf("code = %q, mau %q\nbody: %s", res.Code(), code, res.Raw)
+	}
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	app := newTestApp(t)
+
+	res := app.do(http.MethodGet, "/api/transactions", "", nil)
+	expectError(t, res, http.StatusUnauthorized, utils.CodeTokenRequired)
+}
+
+func TestTokenRejectedAfterSessionVersionChanges(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	if err := app.repos.Users.UpdateFields(user.ID, map[string]interface{}{"session_version": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	res := app.do(http.MethodGet, "/api/transactions", token, nil)
+	expectError(t, res, http.StatusUnauthorized, utils.CodeSessionExpired)
+}
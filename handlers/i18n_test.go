@@ -0,0 +1,107 @@
+package handlers_test
+
+import (
+	"backend-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// postWithLanguage: POST JSON dengan header Accept-Language
+func (a *testApp) postWithLanguage(path, language, body string) testResponse {
+	a.t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if language != "" {
+		req.Header.Set("Accept-Language", language)
+	}
+	return a.send(req, "")
+}
+
+func TestResponseLanguageFromAcceptLanguage(t *testing.T) {
+	app := newTestApp(t)
+	app.createUser("budi", "user", "trial")
+
+	const wrongLogin = `{"username":"budi","password":"SalahSekali123"}`
+	for language, want := range map[string]string{
+		"en":                    "Wrong username or password",
+		"en-US,en;q=0.9":        "Wrong username or password",
+		"id;q=0.3, en;q=0.8":    "Wrong username or password",
+		"id":                    "Username atau password salah",
+		"fr-FR":                 "Username atau password salah", // tidak didukung: bahasa default
+		"":                      "Username atau password salah",
+		"en;q=0, fr":            "Username atau password salah",
+		"fr;q=0.9, en-GB;q=0.1": "Wrong username or password",
+	} {
+		res := app.postWithLanguage("/login", language, wrongLogin)
+		expectError(t, res, http.StatusUnauthorized, utils.CodeInvalidCredentials)
+		if res.Body["error"] != want {
+			t.Errorf("Accept-Language %q: error = %q, mau %q", language, res.Body["error"], want)
+		}
+	}
+
+	// Detail per field & pesan sukses ikut diterjemahkan
+	res := app.postWithLanguage("/register", "en", `{"username":"1x","password":"RahasiaSekali123","confirm_password":"RahasiaSekali123"}`)
+	expectError(t, res, http.StatusBadRequest, utils.CodeUsernameInvalid)
+	details, _ := res.Body["details"].([]interface{})
+	if len(details) != 1 || details[0].(map[string]interface{})["message"] != utils.T("en", utils.CodeUsernameInvalid) {
+		t.Errorf("details = %v", res.Body["details"])
+	}
+	res = app.postWithLanguage("/password/forgot", "en", `{"username":"budi"}`)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["message"] != "If the account is linked to Telegram, a reset code has been sent through the bot." {
+		t.Errorf("message = %q", res.Body["message"])
+	}
+}
+
+func TestBotUsesStoredLanguage(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+
+	reply := func(text string) string {
+		t.Helper()
+		app.botMessage(777, text)
+		messages, _ := telegram.reset()
+		if len(messages) != 1 {
+			t.Fatalf("balasan %q = %+v, mau 1 pesan", text, messages)
+		}
+		return messages[0].Text
+	}
+
+	// Belum pilih bahasa: default Indonesia
+	if got := reply("/perintahaneh"); got != utils.T("id", "bot.unknown_command") {
+		t.Errorf("balasan default = %q", got)
+	}
+	if got := reply("/lang en"); got != "✅ Bot language switched to English." {
+		t.Errorf("ganti bahasa = %q", got)
+	}
+	saved, err := app.repos.Users.FindByID(user.ID)
+	if err != nil || saved.Language != "en" {
+		t.Fatalf("bahasa tersimpan = %q, %v", saved.Language, err)
+	}
+	if got := reply("/perintahaneh"); got != "⚠️ Unknown command. Type /help" {
+		t.Errorf("balasan setelah /lang en = %q", got)
+	}
+
+	// Pesan bot dari request HTTP ikut bahasa user, bukan Accept-Language browser
+	res := app.postWithLanguage("/password/forgot", "id", `{"username":"budi"}`)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["message"] != utils.T("id", "msg.password_forgot") {
+		t.Errorf("message HTTP = %q", res.Body["message"])
+	}
+	messages, _ := telegram.reset()
+	if len(messages) != 1 || !strings.Contains(messages[0].Text, "Password Reset") ||
+		!strings.Contains(messages[0].Text, "Ignore this message") {
+		t.Errorf("pesan kode reset = %+v", messages)
+	}
+
+	if got := reply("/lang xx"); got != "⚠️ Usage: /lang id or /lang en" {
+		t.Errorf("bahasa tidak didukung = %q", got)
+	}
+	if got := reply("/lang id"); got != utils.T("id", "bot.lang_changed") {
+		t.Errorf("kembali ke id = %q", got)
+	}
+}
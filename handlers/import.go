@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"backend-gin/importer"
+	"backend-gin/money"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize: batas ukuran file import (5 MB)
+const maxImportSize = 5 << 20
+
+// POST /api/import (multipart): file + format, mapping, currency, date_format, default_category,
+// commit, skip_invalid, include_duplicates (semua opsional).
+// Tanpa commit=true hanya dry-run: laporan baris, pemetaan kolom & duplikat, tidak ada yang disimpan.
+func (h *Handler) ImportTransactions(c *gin.Context) {
+	userID := getUserID(c)
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, utils.ErrFileRequired.Wrap(err))
+		return
+	}
+	defer file.Close()
+	if header.Size > maxImportSize {
+		utils.RespondError(c, utils.ErrImportTooLarge)
+		return
+	}
+
+	format, err := importer.DetectFormat(c.PostForm("format"), header.Filename)
+	if err != nil {
+		utils.RespondError(c, utils.ErrImportFormatInvalid)
+		return
+	}
+	opts := importer.Options{
+		Format:          format,
+		Location:        utils.UserLocation(user.Timezone),
+		DefaultCategory: strings.TrimSpace(c.PostForm("default_category")),
+	}
+
+	var appErr *utils.AppError
+	if opts.Currency, appErr = h.importCurrency(c, userID); appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+
+	if raw := c.PostForm("date_format"); raw != "" {
+		layout, ok := importer.DateLayout(raw)
+		if !ok {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("date_format", utils.CodeDateFormatInvalid))
+			return
+		}
+		opts.DateLayout = layout
+	}
+	// mapping: JSON {"date": "Tgl Transaksi", "amount": "Nominal", ...}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Mapping); err != nil {
+			utils.RespondError(c, utils.ErrImportMappingInvalid.Wrap(err))
+			return
+		}
+	}
+
+	result, err := importer.Parse(file, opts)
+	if err != nil {
+		utils.RespondError(c, importError(err))
+		return
+	}
+
+	report, err := h.trxService.Import(userID, result.Rows, services.ImportOptions{
+		Location:          opts.Location,
+		Commit:            formBool(c, "commit"),
+		SkipInvalid:       formBool(c, "skip_invalid"),
+		IncludeDuplicates: formBool(c, "include_duplicates"),
+	})
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+	if report.Committed {
+		h.recordAudit(c, AuditTransactionImport, "transaction", nil, nil, gin.H{
+			"file": header.Filename, "format": format, "imported": report.Imported,
+		})
+	}
+
+	lang := utils.Lang(c)
+	rows := make([]gin.H, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		item := gin.H{
+			"line":      row.Line,
+			"date":      nil,
+			"type":      row.Type,
+			"amount":    row.Amount,
+			"currency":  row.Currency,
+			"category":  row.Category,
+			"note":      row.Note,
+			"duplicate": row.Duplicate,
+			"import":    row.Import,
+			"error":     nil,
+		}
+		if !row.Date.IsZero() {
+			item["date"] = row.Date
+		}
+		if !row.Valid() {
+			item["error"] = gin.H{"field": row.Field, "code": row.Code, "message": utils.T(lang, row.Code)}
+		}
+		rows = append(rows, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"format":   result.Format,
+		"columns":  result.Columns,
+		"mapping":  result.Mapping,
+		"currency": opts.Currency,
+		"summary": gin.H{
+			"total":      len(report.Rows),
+			"valid":      report.Valid,
+			"invalid":    report.Invalid,
+			"duplicates": report.Duplicates,
+			"importable": report.Importable,
+		},
+		"committed": report.Committed,
+		"imported":  report.Imported,
+		"rows":      rows,
+	})
+}
+
+// importCurrency: mata uang untuk file yang tidak menyebutkannya (form "currency"), default mata uang dasar user
+func (h *Handler) importCurrency(c *gin.Context, userID uint) (string, *utils.AppError) {
+	if raw := c.PostForm("currency"); raw != "" {
+		currency := money.Normalize(raw)
+		if currency == "" {
+			return "", utils.ErrCurrencyInvalid
+		}
+		return currency, nil
+	}
+	currency, err := h.trxService.BaseCurrency(userID)
+	if err != nil {
+		return "", serviceError(err)
+	}
+	return currency, nil
+}
+
+// importError menerjemahkan error importer ke AppError
+func importError(err error) *utils.AppError {
+	switch {
+	case errors.Is(err, importer.ErrFormatUnknown):
+		return utils.ErrImportFormatInvalid
+	case errors.Is(err, importer.ErrTooManyRows):
+		return utils.ErrImportTooLarge
+	case errors.Is(err, importer.ErrMappingInvalid):
+		return utils.ErrImportMappingInvalid.Wrap(err)
+	}
+	return utils.ErrImportFileInvalid.Wrap(err)
+}
+
+// formBool: field form "true"/"1" (nilai lain dianggap false)
+func formBool(c *gin.Context, name string) bool {
+	value, _ := strconv.ParseBool(c.PostForm(name))
+	return value
+}
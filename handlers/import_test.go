@@ -0,0 +1,158 @@
+package handlers_test
+
+import (
+	"backend-gin/utils"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestImportCSVDryRunAndCommit(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	app.createTransaction(user, "expense", 25000, "Makan", time.Date(2025, time.March, 1, 12, 0, 0, 0, jakarta))
+
+	csv := []byte("Tanggal;Keterangan;Kategori;Jumlah\n" +
+		"01/03/2025;Nasi padang;Makan;-25.000\n" +
+		"01/03/2025;Kopi;Makan;-25.000\n" +
+		"02/03/2025;Gaji Maret;Gaji;5.000.000\n" +
+		"31/02/2025;Tanggal salah;Makan;-1.000\n")
+
+	res := app.upload("/api/import", token, "mutasi.csv", csv, nil)
+	expectStatus(t, res, http.StatusOK)
+	mapping := res.Body["mapping"].(map[string]interface{})
+	if mapping["date"] != "Tanggal" || mapping["amount"] != "Jumlah" || mapping["note"] != "Keterangan" {
+		t.Fatalf("mapping tebakan = %v", mapping)
+	}
+	summary := res.Body["summary"].(map[string]interface{})
+	if summary["valid"] != float64(3) || summary["invalid"] != float64(1) || summary["duplicates"] != float64(1) || summary["importable"] != float64(2) {
+		t.Fatalf("summary dry-run = %v", summary)
+	}
+	rows := res.Body["rows"].([]interface{})
+	if first := rows[0].(map[string]interface{}); first["duplicate"] != true || first["type"] != "expense" {
+		t.Errorf("baris 1 = %v, mau duplikat pengeluaran", first)
+	}
+	if second := rows[1].(map[string]interface{}); second["duplicate"] != false {
+		t.Errorf("baris 2 = %v, kopi kedua bukan duplikat (di database hanya satu)", second)
+	}
+	bad := rows[3].(map[string]interface{})
+	if bad["line"] != float64(5) || bad["error"].(map[string]interface{})["code"] != utils.CodeDateInvalid {
+		t.Errorf("baris error = %v", bad)
+	}
+
+	// Dry-run tidak menyimpan apa pun
+	res = app.do(http.MethodGet, "/api/summary", token, nil)
+	if res.Body["total_income"] != float64(0) {
+		t.Fatalf("dry-run ikut menyimpan: %v", res.Body)
+	}
+
+	// Commit ditolak selama masih ada baris error, kecuali skip_invalid
+	res = app.upload("/api/import", token, "mutasi.csv", csv, map[string]string{"commit": "true"})
+	expectError(t, res, http.StatusUnprocessableEntity, utils.CodeImportHasErrors)
+
+	res = app.upload("/api/import", token, "mutasi.csv", csv, map[string]string{"commit": "true", "skip_invalid": "true"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["committed"] != true || res.Body["imported"] != float64(2) {
+		t.Fatalf("commit = %v / %v, mau 2 baris", res.Body["committed"], res.Body["imported"])
+	}
+	res = app.do(http.MethodGet, "/api/summary", token, nil)
+	if res.Body["total_income"] != float64(5000000) || res.Body["total_expense"] != float64(50000) {
+		t.Fatalf("summary setelah import = %v", res.Body)
+	}
+	// Waktu input terakhir ikut dicatat untuk admin
+	saved, err := app.repos.Users.FindByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.LastTransactionAt == nil || time.Since(*saved.LastTransactionAt) > time.Minute {
+		t.Fatalf("last_transaction_at = %v, mau waktu import", saved.LastTransactionAt)
+	}
+
+	// Import ulang file yang sama: semua baris valid sudah ada
+	res = app.upload("/api/import", token, "mutasi.csv", csv, nil)
+	summary = res.Body["summary"].(map[string]interface{})
+	if summary["duplicates"] != float64(3) || summary["importable"] != float64(0) {
+		t.Fatalf("summary import ulang = %v", summary)
+	}
+}
+
+func TestImportColumnMappingAndFormat(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	csv := []byte("Posted,Value,Memo\n2025-03-01,-12.50,Lunch\n")
+	res := app.upload("/api/import", token, "bank.csv", csv, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeImportMappingInvalid)
+
+	res = app.upload("/api/import", token, "bank.csv", csv, map[string]string{
+		"mapping": `{"date":"Posted","amount":"Value","note":"Memo"}`, "currency": "usd",
+	})
+	expectStatus(t, res, http.StatusOK)
+	row := res.Body["rows"].([]interface{})[0].(map[string]interface{})
+	if row["amount"] != float64(1250) || row["currency"] != "USD" || row["type"] != "expense" || row["category"] != "Import" {
+		t.Fatalf("baris = %v", row)
+	}
+
+	res = app.upload("/api/import", token, "laporan.pdf", csv, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeImportFormatInvalid)
+	res = app.upload("/api/import", token, "bank.csv", csv, map[string]string{"date_format": "tanggal"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+}
+
+func TestImportExportedExcel(t *testing.T) {
+	app := newTestApp(t)
+	source := app.createUser("budi", "user", "trial")
+	target := app.createUser("sari", "user", "trial")
+
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	app.createTransaction(source, "income", 7500000, "Gaji", time.Date(2025, time.April, 1, 9, 15, 0, 0, jakarta))
+	app.createTransaction(source, "expense", 32500, "Makan", time.Date(2025, time.April, 2, 23, 45, 0, 0, jakarta))
+
+	res := app.do(http.MethodGet, "/api/export", app.token(source), nil)
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.upload("/api/import", app.token(target), "laporan.xlsx", res.Raw, map[string]string{"commit": "true"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["imported"] != float64(2) {
+		t.Fatalf("imported = %v\nbody: %s", res.Body["imported"], res.Raw)
+	}
+
+	// Tanggal, jam, tipe & kategori kembali utuh (zona waktu user sama)
+	trx, err := app.repos.Transactions.FindInPeriod(target.ID, time.Time{}, time.Time{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trx) != 2 {
+		t.Fatalf("jumlah transaksi = %d, mau 2", len(trx))
+	}
+	got := trx[1]
+	if got.Type != "expense" || got.Amount != 32500 || got.Category != "Makan" ||
+		!got.CreatedAt.Equal(time.Date(2025, time.April, 2, 23, 45, 0, 0, jakarta)) {
+		t.Fatalf("transaksi hasil import = %+v", got)
+	}
+}
+
+func TestImportNoteHashtags(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	today := time.Now().In(utils.UserLocation(utils.DefaultTimezone)).Format("02/01/2006")
+	csv := []byte("Tanggal;Keterangan;Kategori;Jumlah\n" +
+		today + ";Makan siang #kantor;Makan;-45.000\n" +
+		today + ";Taksi #Kantor #liburan-bali;Transport;-80.000\n")
+	res := app.upload("/api/import", token, "mutasi.csv", csv, map[string]string{"commit": "true"})
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodGet, "/api/tags", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	data := res.Body["data"].([]interface{})
+	if len(data) != 2 {
+		t.Fatalf("tags = %v", data)
+	}
+	if first := data[0].(map[string]interface{}); first["tag"] != "kantor" || first["count"] != float64(2) || first["expense"] != float64(125000) {
+		t.Fatalf("tag kantor = %v", first)
+	}
+}
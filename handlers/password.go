@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	resetCodeLength   = 6
+	resetCodeTTL      = 15 * time.Minute
+	resetCodeCooldown = time.Minute // Jeda minimal antar permintaan kode
+	resetMaxAttempts  = 5
+	resetDailyLimit   = 5  // Kode per akun dalam 24 jam
+	resetIPLimit      = 10 // Kode per IP per jam, dihitung dari semua akun
+)
+
+// Struct untuk Validasi Input Lupa Password
+type ForgotPasswordInput struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// Struct untuk Validasi Input Reset Password
+type ResetPasswordInput struct {
+	Username        string `json:"username" binding:"required"`
+	Code            string `json:"code" binding:"required"`
+	Password        string `json:"password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+// ENDPOINT: POST /password/forgot
+// Kirim kode reset sekali pakai ke Telegram yang terhubung dengan akun
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var input ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	input.Username = utils.NormalizeUsername(input.Username)
+
+	now := time.Now()
+	// Satu IP tidak boleh menghujani banyak akun dengan kode. Hanya kode yang benar-benar dikirim
+	// yang dihitung, jadi jawaban 429 tidak membuka apakah username terdaftar.
+	fromIP, err := h.passwordResets.CountFromIPSince(c.ClientIP(), now.Add(-time.Hour))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	if fromIP >= resetIPLimit {
+		utils.RespondError(c, utils.ErrTooManyRequests)
+		return
+	}
+
+	// Pesan sama untuk semua kasus supaya tidak bisa dipakai menebak username yang terdaftar
+	forgotPasswordMessage := utils.T(utils.Lang(c), "msg.password_forgot")
+
+	user, err := h.users.FindByUsername(input.Username)
+	if err != nil || user.TelegramID == nil {
+		c.JSON(http.StatusOK, gin.H{"message": forgotPasswordMessage})
+		return
+	}
+
+	// Cegah spam: kalau baru saja minta kode atau jatah harian habis, jangan kirim lagi
+	recent, err := h.passwordResets.CountCreatedSince(user.ID, now.Add(-resetCodeCooldown))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	today, err := h.passwordResets.CountCreatedSince(user.ID, now.Add(-24*time.Hour))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	if recent > 0 || today >= resetDailyLimit {
+		c.JSON(http.StatusOK, gin.H{"message": forgotPasswordMessage})
+		return
+	}
+
+	code, err := utils.GenerateNumericCode(resetCodeLength)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	// Kode lama yang belum dipakai langsung hangus
+	err = h.passwordResets.Issue(&models.PasswordReset{
+		UserID:    user.ID,
+		CodeHash:  utils.HashCode(code),
+		ExpiresAt: now.Add(resetCodeTTL),
+		CreatedAt: now,
+		IP:        c.ClientIP(),
+	})
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	// Pesan bot pakai bahasa pilihan user, bukan bahasa browser
+	lang := utils.UserLanguage(user.Language)
+	pesan := utils.T(lang, "bot.reset_code", user.Username, code, int(resetCodeTTL.Minutes()))
+
+	// Opsional: sertakan link langsung ke halaman reset di frontend
+	if frontendURL := os.Getenv("FRONTEND_URL"); frontendURL != "" {
+		link := fmt.Sprintf("%s/reset-password?username=%s&code=%s", frontendURL, url.QueryEscape(user.Username), code)
+		pesan += utils.T(lang, "bot.reset_link", link)
+	}
+	pesan += utils.T(lang, "bot.reset_ignore")
+
+	sendReply(*user.TelegramID, pesan, nil)
+	// Actor kosong: yang minta reset belum tentu pemilik akun
+	h.writeAudit(c, nil, AuditPasswordForgot, "user", user.ID, nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": forgotPasswordMessage})
+}
+
+// ENDPOINT: POST /password/reset
+// Pakai kode dari bot untuk set password baru, semua sesi lama ikut logout
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var input ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+	input.Username = utils.NormalizeUsername(input.Username)
+
+	if verr := utils.ValidateNewPassword(input.Password, input.ConfirmPassword, input.Username); verr != nil {
+		utils.RespondError(c, verr)
+		return
+	}
+
+	user, err := h.users.FindByUsername(input.Username)
+	if err != nil {
+		utils.RespondError(c, utils.ErrResetCodeInvalid)
+		return
+	}
+
+	// Ambil kode terbaru yang masih aktif
+	reset, err := h.passwordResets.FindActive(user.ID, time.Now())
+	if err != nil {
+		utils.RespondError(c, utils.ErrResetCodeInvalid)
+		return
+	}
+
+	if !utils.CompareCode(reset.CodeHash, input.Code) {
+		// Terlalu banyak salah: kode langsung hangus, harus minta ulang
+		err := h.passwordResets.RecordFailedAttempt(reset, resetMaxAttempts)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
+		utils.RespondError(c, utils.ErrResetCodeInvalid)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	// Kode ditandai terpakai (single use) dan versi sesi naik supaya semua token lama tidak berlaku lagi
+	err = h.passwordResets.Consume(reset, string(hashedPassword))
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.RespondError(c, utils.ErrResetCodeInvalid)
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	h.recordAuditAs(c, *user, AuditPasswordReset, "user", user.ID, nil, nil)
+
+	if user.TelegramID != nil {
+		sendReply(*user.TelegramID, utils.T(utils.UserLanguage(user.Language), "bot.password_changed"), nil)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.password_reset")})
+}
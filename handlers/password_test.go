@@ -0,0 +1,256 @@
+package handlers_test
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// racyResets: fake repository yang menahan request sampai semuanya selesai membaca kode aktif,
+// supaya tebakan yang bersamaan pasti melihat hitungan percobaan yang sama
+type racyResets struct {
+	repository.PasswordResetRepository
+	pending atomic.Int32
+	reads   sync.WaitGroup
+}
+
+func (r *racyResets) FindActive(userID uint, now time.Time) (*models.PasswordReset, error) {
+	reset, err := r.PasswordResetRepository.FindActive(userID, now)
+	if r.pending.Add(-1) >= 0 {
+		r.reads.Done()
+		r.reads.Wait()
+	}
+	return reset, err
+}
+
+func TestResetPasswordConcurrentWrongCodesBurnCode(t *testing.T) {
+	const guesses = 6 // Lebih dari batas 5 percobaan
+
+	base := newTestApp(t)
+	repos := *base.repos
+	resets := &racyResets{PasswordResetRepository: repos.PasswordResets}
+	resets.pending.Store(guesses)
+	resets.reads.Add(guesses)
+	repos.PasswordResets = resets
+	app := newTestAppWith(t, base.db, &repos)
+	user := app.createUser("budi", "user", "trial")
+
+	now := time.Now()
+	reset := &models.PasswordReset{
+		UserID:    user.ID,
+		CodeHash:  utils.HashCode("123456"),
+		ExpiresAt: now.Add(15 * time.Minute),
+		CreatedAt: now,
+	}
+	if err := app.repos.PasswordResets.Issue(reset); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.do(http.MethodPost, "/password/reset", "", resetInput("000000"))
+		}()
+	}
+	wg.Wait()
+
+	// Hitungan tidak boleh saling menimpa: kode hangus tepat di percobaan ke-5
+	var stored models.PasswordReset
+	app.db.First(&stored, reset.ID)
+	if stored.UsedAt == nil || stored.Attempts != 5 {
+		t.Fatalf("kode setelah %d tebakan salah = attempts %d, used_at %v", guesses, stored.Attempts, stored.UsedAt)
+	}
+
+	// Kode yang benar pun sudah tidak berlaku
+	res := app.do(http.MethodPost, "/password/reset", "", resetInput("123456"))
+	expectError(t, res, http.StatusBadRequest, utils.CodeResetCodeInvalid)
+}
+
+func TestUpdateProfileUsernameNotInCurrentPassword(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	// testPassword = "RahasiaSekali123": username baru "Rahasia" ada di dalamnya
+	res := app.do(http.MethodPut, "/api/user/profile", token, map[string]string{"username": "Rahasia"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeCurrentPasswordRequired)
+	res = app.do(http.MethodPut, "/api/user/profile", token, map[string]string{"username": "Rahasia", "current_password": testPassword})
+	expectError(t, res, http.StatusBadRequest, utils.CodePasswordContainsUser)
+
+	res = app.do(http.MethodPut, "/api/user/profile", token, map[string]string{"username": "budiman", "current_password": testPassword})
+	expectStatus(t, res, http.StatusOK)
+	saved, err := app.repos.Users.FindByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Username != "budiman" {
+		t.Fatalf("username = %q, mau budiman", saved.Username)
+	}
+}
+
+var resetCodePattern = regexp.MustCompile(`<code>(\d{6})</code>`)
+
+// requestResetCode: POST /password/forgot lalu ambil kode dari pesan bot
+func requestResetCode(t *testing.T, app *testApp, telegram *fakeTelegram, username string) string {
+	t.Helper()
+	res := app.do(http.MethodPost, "/password/forgot", "", map[string]string{"username": username})
+	expectStatus(t, res, http.StatusOK)
+	messages, _ := telegram.reset()
+	if len(messages) != 1 {
+		t.Fatalf("pesan bot = %+v, mau 1 kode reset", messages)
+	}
+	match := resetCodePattern.FindStringSubmatch(messages[0].Text)
+	if match == nil {
+		t.Fatalf("kode tidak ada di pesan %q", messages[0].Text)
+	}
+	return match[1]
+}
+
+func resetInput(code string) map[string]string {
+	return map[string]string{
+		"username": "budi", "code": code, "password": "PasswordBaru987", "confirm_password": "PasswordBaru987",
+	}
+}
+
+func TestPasswordForgotAndReset(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+	oldToken := app.token(user)
+	expectStatus(t, app.do(http.MethodGet, "/api/user/settings", oldToken, nil), http.StatusOK)
+
+	code := requestResetCode(t, app, telegram, "budi")
+	expectStatus(t, app.do(http.MethodPost, "/password/reset", "", resetInput(code)), http.StatusOK)
+	if messages, _ := telegram.reset(); len(messages) != 1 || messages[0].ChatID != 777 {
+		t.Errorf("notifikasi password diganti = %+v", messages)
+	}
+
+	// Semua sesi lama logout, password baru langsung berlaku
+	expectError(t, app.do(http.MethodGet, "/api/user/settings", oldToken, nil), http.StatusUnauthorized, utils.CodeSessionExpired)
+	expectError(t, app.do(http.MethodPost, "/login", "", map[string]string{"username": "budi", "password": testPassword}),
+		http.StatusUnauthorized, utils.CodeInvalidCredentials)
+	expectStatus(t, app.do(http.MethodPost, "/login", "", map[string]string{"username": "budi", "password": "PasswordBaru987"}),
+		http.StatusOK)
+
+	// Kode sekali pakai
+	expectError(t, app.do(http.MethodPost, "/password/reset", "", resetInput(code)), http.StatusBadRequest, utils.CodeResetCodeInvalid)
+}
+
+func TestAuthTrimsUsername(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+
+	// Spasi di awal/akhir dibuang seperti saat register
+	expectStatus(t, app.do(http.MethodPost, "/login", "", map[string]string{"username": "  budi ", "password": testPassword}), http.StatusOK)
+	code := requestResetCode(t, app, telegram, " budi\t")
+
+	// Username yang sudah dirapikan juga dipakai untuk cek password mengandung username
+	input := resetInput(code)
+	input["username"], input["password"], input["confirm_password"] = "budi ", "RahasiaBudi987", "RahasiaBudi987"
+	expectError(t, app.do(http.MethodPost, "/password/reset", "", input), http.StatusBadRequest, utils.CodePasswordContainsUser)
+
+	input = resetInput(code)
+	input["username"] = "budi "
+	expectStatus(t, app.do(http.MethodPost, "/password/reset", "", input), http.StatusOK)
+}
+
+func TestPasswordResetCodeExpires(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+	code := requestResetCode(t, app, telegram, "budi")
+
+	var reset models.PasswordReset
+	if err := app.db.Where("user_id = ?", user.ID).First(&reset).Error; err != nil {
+		t.Fatal(err)
+	}
+	if ttl := reset.ExpiresAt.Sub(reset.CreatedAt); ttl != 15*time.Minute {
+		t.Fatalf("masa berlaku kode = %v, mau 15m", ttl)
+	}
+
+	// Mundurkan waktu kode seolah 15 menit sudah lewat
+	shift := 15*time.Minute + time.Second
+	err := app.db.Model(&reset).Updates(map[string]interface{}{
+		"created_at": reset.CreatedAt.Add(-shift), "expires_at": reset.ExpiresAt.Add(-shift),
+	}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectError(t, app.do(http.MethodPost, "/password/reset", "", resetInput(code)), http.StatusBadRequest, utils.CodeResetCodeInvalid)
+	expectStatus(t, app.do(http.MethodPost, "/login", "", map[string]string{"username": "budi", "password": testPassword}),
+		http.StatusOK)
+}
+
+func TestPasswordForgotLimits(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+
+	// issued: kode yang diminta ago yang lalu dari ip
+	issued := func(userID uint, ago time.Duration, ip string) *models.PasswordReset {
+		t.Helper()
+		at := time.Now().Add(-ago)
+		reset := &models.PasswordReset{UserID: userID, CodeHash: utils.HashCode("123456"), ExpiresAt: at.Add(15 * time.Minute), CreatedAt: at, IP: ip}
+		if err := app.repos.PasswordResets.Issue(reset); err != nil {
+			t.Fatal(err)
+		}
+		return reset
+	}
+	forgot := func(username, ip string) testResponse {
+		req := httptest.NewRequest(http.MethodPost, "/password/forgot", strings.NewReader(`{"username":"`+username+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":40000"
+		return app.send(req, "")
+	}
+
+	// Jatah 5 kode sehari habis: jawaban tetap sama, tapi tidak ada kode terkirim
+	var oldest *models.PasswordReset
+	for hours := 1; hours <= 5; hours++ {
+		oldest = issued(user.ID, time.Duration(hours)*time.Hour, "198.51.100.7")
+	}
+	expectStatus(t, forgot("budi", "192.0.2.1"), http.StatusOK)
+	if messages, _ := telegram.reset(); len(messages) != 0 {
+		t.Fatalf("kode ke-6 dalam sehari terkirim: %+v", messages)
+	}
+
+	// Kode tertua sudah lewat 24 jam: boleh minta lagi
+	if err := app.db.Model(oldest).Update("created_at", time.Now().Add(-25*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	expectStatus(t, forgot("budi", "192.0.2.1"), http.StatusOK)
+	if messages, _ := telegram.reset(); len(messages) != 1 {
+		t.Fatalf("pesan bot = %+v, mau 1 kode", messages)
+	}
+
+	// 10 kode sejam dari satu IP (akun mana pun): IP itu ditolak, termasuk untuk username tak terdaftar
+	other := app.createUser("sari", "user", "trial")
+	app.linkTelegram(other, 888)
+	for minutes := 2; minutes <= 10; minutes++ {
+		issued(other.ID, time.Duration(minutes)*time.Minute, "192.0.2.1")
+	}
+	expectError(t, forgot("sari", "192.0.2.1"), http.StatusTooManyRequests, utils.CodeTooManyRequests)
+	expectError(t, forgot("tidakada", "192.0.2.1"), http.StatusTooManyRequests, utils.CodeTooManyRequests)
+
+	// IP lain tidak ikut kena
+	andi := app.createUser("andi", "user", "trial")
+	app.linkTelegram(andi, 999)
+	expectStatus(t, forgot("andi", "203.0.113.9"), http.StatusOK)
+	if messages, _ := telegram.reset(); len(messages) != 1 || messages[0].ChatID != 999 {
+		t.Fatalf("pesan bot = %+v, mau 1 kode untuk andi", messages)
+	}
+}
@@ -12,8 +12,8 @@ import (
 	"strings"
 	"time"
 
-	"backend-gin/database"
 	"backend-gin/models"
+	"backend-gin/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,7 +31,7 @@ type AIResponse struct {
 	IsValid bool   `json:"is_valid"`
 	Amount  int64  `json:"amount"`
 	Bank    string `json:"bank"`
-	Reason  string `json:"reason"`
+	Reason  string `json:"reason"` // Key katalog pesan (payment.reason.*), diterjemahkan saat dikirim
 }
 
 // Helper untuk memastikan folder uploads ada
@@ -46,16 +46,16 @@ func ensureUploadDir() string {
 // ---------------------------------------------------------
 // 1. VERIFIKASI OTOMATIS (AUTO - OCR)
 // ---------------------------------------------------------
-func VerifyPayment(c *gin.Context) {
+func (h *Handler) VerifyPayment(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.RespondError(c, utils.ErrUnauthorized)
 		return
 	}
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File diperlukan"})
+		utils.RespondError(c, utils.ErrFileRequired.Wrap(err))
 		return
 	}
 	defer file.Close()
@@ -68,7 +68,7 @@ func VerifyPayment(c *gin.Context) {
 	savePath := filepath.Join(uploadDir, filename)
 
 	if err := c.SaveUploadedFile(header, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan file"})
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -76,8 +76,11 @@ func VerifyPayment(c *gin.Context) {
 	apiKey := os.Getenv("OCR_API_KEY")
 	// Jika API Key kosong, anggap manual checks (fallback aman)
 	if apiKey == "" {
-		savePaymentLog(userID.(uint), filename, "MANUAL_CHECK", 0, "API Key Missing", c)
-		c.JSON(http.StatusAccepted, gin.H{"message": "OCR Off, masuk verifikasi manual", "manual_check": true})
+		if err := h.savePaymentLog(userID.(uint), filename, "MANUAL_CHECK", 0, "API Key Missing", c); err != nil {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": utils.T(utils.Lang(c), "msg.payment_ocr_off"), "manual_check": true})
 		return
 	}
 
@@ -86,7 +89,11 @@ func VerifyPayment(c *gin.Context) {
 	// var errOCR error
 	
 	// Buka file lokal untuk dikirim
-	savedFile, _ := os.Open(savePath)
+	savedFile, err := os.Open(savePath)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 	var buf bytes.Buffer
 	w := multipart.NewWriter(&buf)
 	fw, _ := w.CreateFormFile("file", filename)
@@ -105,8 +112,11 @@ func VerifyPayment(c *gin.Context) {
 	
 	if err != nil {
 		// Jika OCR Error/Timeout -> Lempar ke Manual
-		savePaymentLog(userID.(uint), filename, "MANUAL_CHECK", 0, "OCR Timeout", c)
-		c.JSON(http.StatusAccepted, gin.H{"message": "Gagal baca otomatis, masuk antrian admin.", "manual_check": true})
+		if err := h.savePaymentLog(userID.(uint), filename, "MANUAL_CHECK", 0, "OCR Timeout", c); err != nil {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": utils.T(utils.Lang(c), "msg.payment_ocr_timeout"), "manual_check": true})
 		return
 	}
 	defer resp.Body.Close()
@@ -116,8 +126,11 @@ func VerifyPayment(c *gin.Context) {
 
 	if ocr.OCRExitCode != 1 || len(ocr.ParsedResults) == 0 {
 		// Jika OCR Gagal Baca -> Lempar ke Manual
-		savePaymentLog(userID.(uint), filename, "MANUAL_CHECK", 0, "OCR Failed Read", c)
-		c.JSON(http.StatusAccepted, gin.H{"message": "Struk tidak terbaca, masuk antrian admin.", "manual_check": true})
+		if err := h.savePaymentLog(userID.(uint), filename, "MANUAL_CHECK", 0, "OCR Failed Read", c); err != nil {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"message": utils.T(utils.Lang(c), "msg.payment_ocr_unreadable"), "manual_check": true})
 		return
 	}
 
@@ -126,36 +139,40 @@ func VerifyPayment(c *gin.Context) {
 
 	// SIMPAN LOG
 	// KUNCI: Jika Valid, simpan bank asli. Jika tidak, simpan deteksinya.
-	savePaymentLog(userID.(uint), filename, result.Bank, result.Amount, text, c)
+	if err := h.savePaymentLog(userID.(uint), filename, result.Bank, result.Amount, text, c); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	h.recordAudit(c, AuditPaymentVerify, "user", userID, nil, result)
 
 	if !result.IsValid {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Bukti ditolak: " + result.Reason})
+		utils.RespondError(c, utils.ErrPaymentRejected.WithArgs(utils.T(utils.Lang(c), result.Reason)))
 		return
 	}
 
 	// Aktifkan User
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err == nil {
-		user.Status = "active"
-		database.DB.Save(&user)
+	if err := h.users.UpdateFields(userID.(uint), map[string]interface{}{"status": "active"}); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Pembayaran Valid! Akun Aktif.", "data": result})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.payment_valid"), "data": result})
 }
 
 // ---------------------------------------------------------
 // 2. VERIFIKASI MANUAL (MANUAL UPLOAD)
 // ---------------------------------------------------------
-func ManualPaymentUpload(c *gin.Context) {
+func (h *Handler) ManualPaymentUpload(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		utils.RespondError(c, utils.ErrUnauthorized)
 		return
 	}
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File wajib ada"})
+		utils.RespondError(c, utils.ErrFileRequired.Wrap(err))
 		return
 	}
 	defer file.Close()
@@ -168,15 +185,20 @@ func ManualPaymentUpload(c *gin.Context) {
 	savePath := filepath.Join(uploadDir, filename)
 
 	if err := c.SaveUploadedFile(header, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal simpan file"})
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
 		return
 	}
 
 	// Update Status User -> Pending
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err == nil {
-		user.Status = "pending"
-		database.DB.Save(&user)
+	user, err := h.users.FindByID(userID.(uint))
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	user.Status = "pending"
+	if err := h.users.Save(user); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
 	}
 
 	// SIMPAN LOG DENGAN CAP KHUSUS: "MANUAL_CHECK"
@@ -190,15 +212,21 @@ func ManualPaymentUpload(c *gin.Context) {
 		RawOCRResponse: "User upload manual (Bypass AI)",
 		CreatedAt:      time.Now(),
 	}
-	database.DB.Create(&log)
+	if err := h.paymentLogs.Create(&log); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditPaymentManual, "payment_log", log.ID, nil, log)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Terkirim ke Admin", "status": "pending"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.payment_manual_sent"), "status": "pending"})
 }
 
 // HELPER SIMPAN LOG
-func savePaymentLog(userID uint, filename, bank string, amount int64, rawText string, c *gin.Context) {
-	var user models.User
-	database.DB.First(&user, userID)
+func (h *Handler) savePaymentLog(userID uint, filename, bank string, amount int64, rawText string, c *gin.Context) error {
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
 
 	paymentLog := models.PaymentLog{
 		UserID:         user.ID,
@@ -209,7 +237,7 @@ func savePaymentLog(userID uint, filename, bank string, amount int64, rawText st
 		RawOCRResponse: rawText,
 		CreatedAt:      time.Now(),
 	}
-	database.DB.Create(&paymentLog)
+	return h.paymentLogs.Create(&paymentLog)
 }
 
 // LOGIC EKSTRAKSI TEKS (Sama seperti sebelumnya)
@@ -218,7 +246,7 @@ func extractPaymentInfo(text string) AIResponse {
 	// Logic cek BERHASIL/SUCCESS
 	if !strings.Contains(text, "BERHASIL") && !strings.Contains(text, "SUCCESS") && !strings.Contains(text, "SUKSES") {
 		r.IsValid = false
-		r.Reason = "Tidak ada kata BERHASIL/SUKSES"
+		r.Reason = "payment.reason.no_success_keyword"
 		return r
 	}
 	// Logic cek Bank
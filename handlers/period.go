@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// monthStartDay: tanggal awal bulan laporan user (data lama tanpa isian dianggap tanggal 1)
+func monthStartDay(user *models.User) int {
+	if user.MonthStartDay < 1 || user.MonthStartDay > utils.MaxMonthStartDay {
+		return 1
+	}
+	return user.MonthStartDay
+}
+
+// userNow: waktu sekarang di zona waktu user (dasar batas "hari ini", "minggu ini", dst.)
+func userNow(user *models.User) time.Time {
+	return time.Now().In(utils.UserLocation(user.Timezone))
+}
+
+// periodQuery membaca filter periode bersama dari query string:
+// ?period=this_month | ?from=2025-11-01&to=2025-11-30 | ?month=11&year=2025 (+ month_start_day opsional).
+// Awal bulan default diambil dari pengaturan user. now = waktu sekarang di zona waktu user.
+func (h *Handler) periodQuery(c *gin.Context, userID uint) (q utils.PeriodQuery, now time.Time, appErr *utils.AppError) {
+	q = utils.PeriodQuery{
+		Period: c.Query("period"),
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+		Month:  c.Query("month"),
+		Year:   c.Query("year"),
+	}
+
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		return q, now, utils.ErrUserNotFound.Wrap(err)
+	}
+	now = userNow(user)
+	q.MonthStartDay = monthStartDay(user)
+
+	if raw := c.Query("month_start_day"); raw != "" {
+		day, err := strconv.Atoi(raw)
+		if err != nil || day < 1 || day > utils.MaxMonthStartDay {
+			return q, now, utils.ErrInvalidInput.WithField("month_start_day", utils.CodeMonthStartDayInvalid)
+		}
+		q.MonthStartDay = day
+	}
+	return q, now, nil
+}
+
+// period: periodQuery + ResolvePeriod relatif ke waktu sekarang di zona waktu user
+func (h *Handler) period(c *gin.Context, userID uint) (utils.Period, *time.Location, *utils.AppError) {
+	q, now, appErr := h.periodQuery(c, userID)
+	if appErr != nil {
+		return utils.Period{}, nil, appErr
+	}
+	period, appErr := utils.ResolvePeriod(q, now)
+	return period, now.Location(), appErr
+}
+
+// periodJSON: rentang periode untuk response, "to" inklusif (null = tanpa batas)
+func periodJSON(p utils.Period) gin.H {
+	out := gin.H{"from": nil, "to": nil}
+	if !p.From.IsZero() {
+		out["from"] = p.From.Format("2006-01-02")
+	}
+	if !p.To.IsZero() {
+		out["to"] = p.To.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"html"
+	"log"
+	"strings"
+)
+
+// splitInput: satu baris rincian transaksi split dari web ({"category", "amount", "note"})
+type splitInput struct {
+	Category string `json:"category"`
+	Amount   string `json:"amount"` // Format sama dengan amount transaksi, dalam mata uang transaksi
+	Note     string `json:"note"`
+}
+
+// parseSplits: nominal tiap baris dibaca dalam mata uang transaksinya (validasi lain di services)
+func parseSplits(input []splitInput, currency string) ([]models.TransactionSplit, *utils.AppError) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+	splits := make([]models.TransactionSplit, len(input))
+	for i, line := range input {
+		amount, err := money.Parse(line.Amount, currency)
+		if err != nil {
+			return nil, utils.ErrInvalidInput.WithField("splits", utils.CodeInvalidAmount)
+		}
+		splits[i] = models.TransactionSplit{Category: line.Category, Note: line.Note, Amount: amount}
+	}
+	return splits, nil
+}
+
+// parseBotSplits: "Belanja:100000 Jajan:50000 struk indomaret" jadi baris split + catatan (kata setelah baris terakhir).
+// Satu baris boleh tanpa nominal ("Jajan:"), isinya sisa dari total. ok=false kalau formatnya salah.
+func parseBotSplits(tokens []string, total int64, currency string) (splits []models.TransactionSplit, note string, ok bool) {
+	remainder := -1
+	rest := total
+	for i, token := range tokens {
+		sep := strings.LastIndex(token, ":")
+		if sep < 0 {
+			note = strings.Join(tokens[i:], " ")
+			break
+		}
+		line := models.TransactionSplit{Category: token[:sep]}
+		if raw := token[sep+1:]; raw == "" {
+			if remainder >= 0 {
+				return nil, "", false
+			}
+			remainder = len(splits)
+		} else {
+			amount, err := money.Parse(raw, currency)
+			if err != nil {
+				return nil, "", false
+			}
+			line.Amount = amount
+			rest -= amount
+		}
+		splits = append(splits, line)
+	}
+	if remainder >= 0 {
+		splits[remainder].Amount = rest
+	}
+	return splits, note, true
+}
+
+// saveBotSplit: simpan transaksi split dari chat, mis. "-150000 Belanja:100000 Jajan:50000"
+func (h *Handler) saveBotSplit(chatID int64, user *models.User, lang string, tipe models.TransactionType, amount int64, currency string, tokens []string) {
+	splits, note, ok := parseBotSplits(tokens, amount, currency)
+	if !ok {
+		sendReply(chatID, utils.T(lang, "bot.split_usage"), nil)
+		return
+	}
+	trx := models.Transaction{
+		UserID:   user.ID,
+		Amount:   amount,
+		Currency: currency,
+		Type:     tipe,
+		Note:     note,
+		Splits:   splits,
+	}
+	alert, err := h.trxService.Create(&trx)
+	if err != nil {
+		log.Printf("[BOT] Gagal simpan transaksi split user %d: %v", user.ID, err)
+		sendReply(chatID, botErrorText(lang, err), nil)
+		return
+	}
+
+	icon, alertMsg := "Dn", ""
+	if tipe == models.TypeIncome {
+		icon = "UP"
+	} else if alert != "" {
+		alertMsg = "\n\n🚨 " + alert
+	}
+	lines := make([]string, len(trx.Splits))
+	for i, line := range trx.Splits {
+		lines[i] = html.EscapeString(line.Category) + " " + money.Format(line.Amount, currency)
+	}
+	sendReply(chatID, utils.T(lang, "bot.saved", trx.ID, icon, money.Format(trx.Amount, currency), strings.Join(lines, ", "), botTime(user, trx.CreatedAt), alertMsg), nil)
+}
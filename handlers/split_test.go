@@ -0,0 +1,164 @@
+package handlers_test
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/jobs"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSplitTransaction(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+	app.createTransaction(user, "expense", 20000, "Jajan", time.Now().Add(-time.Hour))
+
+	split := func(amount string, lines ...[2]string) map[string]interface{} {
+		splits := make([]map[string]string, len(lines))
+		for i, line := range lines {
+			splits[i] = map[string]string{"category": line[0], "amount": line[1]}
+		}
+		return map[string]interface{}{"type": "expense", "amount": amount, "note": "struk indomaret", "splits": splits}
+	}
+
+	res := app.do(http.MethodPost, "/api/transactions", token, split("150000", [2]string{"Belanja", "100000"}, [2]string{"Jajan", "40000"}))
+	expectError(t, res, http.StatusBadRequest, utils.CodeSplitMismatch)
+	res = app.do(http.MethodPost, "/api/transactions", token, split("150000", [2]string{"Belanja", "150000"}))
+	expectError(t, res, http.StatusBadRequest, utils.CodeSplitInvalid)
+
+	res = app.do(http.MethodPost, "/api/transactions", token, split("150000", [2]string{"Jajan", "50000"}, [2]string{"Belanja", "100.000"}))
+	expectStatus(t, res, http.StatusOK)
+	data := res.Body["data"].(map[string]interface{})
+	if data["category"] != "Belanja" || len(data["splits"].([]interface{})) != 2 {
+		t.Fatalf("data = %v, mau kategori induk Belanja (baris terbesar)", data)
+	}
+
+	// Ringkasan kategori menghitung baris rincian, bukan induknya
+	res = app.do(http.MethodGet, "/api/categories", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	totals := make(map[string]float64)
+	for _, row := range res.Body["data"].([]interface{}) {
+		row := row.(map[string]interface{})
+		totals[row["category"].(string)] = row["total"].(float64)
+	}
+	if len(totals) != 2 || totals["Belanja"] != 100000 || totals["Jajan"] != 70000 {
+		t.Fatalf("kategori = %v", totals)
+	}
+
+	// Filter kategori ikut mencocokkan baris rincian
+	res = app.do(http.MethodGet, "/api/transactions?category=jajan", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if list := res.Body["data"].([]interface{}); len(list) != 2 {
+		t.Fatalf("filter jajan = %d transaksi", len(list))
+	}
+
+	// Export: satu baris per rincian
+	res = app.do(http.MethodGet, "/api/export?format=jsonl", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	lines := bytes.Split(bytes.TrimSpace(res.Raw), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("jsonl = %s", res.Raw)
+	}
+	var first map[string]interface{}
+	json.Unmarshal(lines[0], &first)
+	if first["split"] != true || first["category"] != "Jajan" || first["note"] != "struk indomaret" {
+		t.Errorf("baris pertama = %v", first)
+	}
+
+	// Ganti kategori ikut mengubah baris rincian
+	res = app.do(http.MethodPost, "/api/transactions/recategorize", token, map[string]string{"from": "jajan", "to": "Camilan"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["updated"] != float64(2) {
+		t.Fatalf("updated = %v", res.Body["updated"])
+	}
+	var lineCount int64
+	app.db.Model(&models.TransactionSplit{}).Where("category = ?", "Camilan").Count(&lineCount)
+	if lineCount != 1 {
+		t.Fatalf("baris rincian Camilan = %d", lineCount)
+	}
+
+	// Dihapus permanen dari tong sampah beserta rinciannya
+	id := uint(data["id"].(float64))
+	if _, err := app.repos.Transactions.Delete(user.ID, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := jobs.PurgeTrash(app.repos, time.Now().AddDate(0, 0, 31)); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	app.db.Model(&models.TransactionSplit{}).Count(&lineCount)
+	if lineCount != 0 {
+		t.Fatalf("%d baris rincian tersisa", lineCount)
+	}
+}
+
+func TestBotSplitTransaction(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+
+	app.botMessage(777, "-150000 Belanja:100000 Jajan: struk indomaret")
+	app.botMessage(777, "-150000 Belanja:100000 Jajan:40000")
+	app.botMessage(777, "-150000 Belanja: Jajan:")
+	messages, _ := telegram.reset()
+	if len(messages) != 3 {
+		t.Fatalf("messages = %+v", messages)
+	}
+	if !strings.Contains(messages[0].Text, "Jajan Rp 50000") {
+		t.Errorf("balasan simpan = %q", messages[0].Text)
+	}
+	if !strings.Contains(messages[1].Text, "Total rincian") || !strings.Contains(messages[2].Text, "Format split") {
+		t.Errorf("balasan error = %q / %q", messages[1].Text, messages[2].Text)
+	}
+
+	trx, _, err := app.repos.Transactions.List(repository.TransactionFilter{UserID: user.ID, Page: 1, Limit: 10})
+	if err != nil || len(trx) != 1 {
+		t.Fatalf("transaksi = %d (%v)", len(trx), err)
+	}
+	if trx[0].Note != "struk indomaret" || len(trx[0].Splits) != 2 || trx[0].Splits[1].Amount != 50000 {
+		t.Fatalf("transaksi = %+v", trx[0])
+	}
+}
+
+func TestExportCountsSplitTransactionOnce(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+	app.linkTelegram(user, 777)
+
+	res := app.do(http.MethodPost, "/api/transactions", token, map[string]interface{}{
+		"type": "expense", "amount": "150000", "note": "struk indomaret",
+		"splits": []map[string]string{{"category": "Belanja", "amount": "100000"}, {"category": "Jajan", "amount": "50000"}},
+	})
+	expectStatus(t, res, http.StatusOK)
+
+	// Dua baris rincian di sheet transaksi, tapi tetap satu transaksi
+	now := time.Now().In(utils.UserLocation(utils.DefaultTimezone))
+	app.botMessage(777, fmt.Sprintf("/export %d %d", now.Month(), now.Year()))
+	_, documents := telegram.reset()
+	if len(documents) != 1 || !strings.Contains(documents[0].Caption, "\n1 transaksi\n") {
+		t.Fatalf("documents = %+v", documents)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(documents[0].Content))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	defer f.Close()
+	if rows, _ := f.GetRows(exporter.SheetName); len(rows) != 3 {
+		t.Fatalf("sheet transaksi = %d baris, mau header + 2 rincian", len(rows))
+	}
+	if got, _ := f.GetCellValue("Ringkasan", "B11"); got != "1" {
+		t.Fatalf("jumlah transaksi = %q, mau 1", got)
+	}
+}
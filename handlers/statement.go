@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"backend-gin/importer"
+	"backend-gin/models"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/statements (multipart): file + bank (bca / mandiri), format (csv / pdf, default dari ekstensi)
+// & currency (opsional). Mutasi disimpan lalu langsung dicocokkan dengan transaksi (jendela 3 hari).
+func (h *Handler) UploadStatement(c *gin.Context) {
+	userID := getUserID(c)
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+
+	bank := strings.ToLower(strings.TrimSpace(c.PostForm("bank")))
+	if !importer.ValidBank(bank) {
+		utils.RespondError(c, utils.ErrBankInvalid)
+		return
+	}
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, utils.ErrFileRequired.Wrap(err))
+		return
+	}
+	defer file.Close()
+	if header.Size > maxImportSize {
+		utils.RespondError(c, utils.ErrImportTooLarge)
+		return
+	}
+
+	format, err := importer.DetectFormat(c.PostForm("format"), header.Filename)
+	if err != nil || (format != importer.FormatCSV && format != importer.FormatPDF) {
+		utils.RespondError(c, utils.ErrStatementFormatInvalid)
+		return
+	}
+	opts := importer.Options{Format: format, Location: utils.UserLocation(user.Timezone)}
+	var appErr *utils.AppError
+	if opts.Currency, appErr = h.importCurrency(c, userID); appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+
+	parsed, err := importer.ParseStatement(file, bank, opts)
+	if err != nil {
+		utils.RespondError(c, statementError(err))
+		return
+	}
+	statement, err := h.statementService.Save(userID, header.Filename, parsed)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditStatementImport, "statement", statement.ID, nil, gin.H{
+		"file": header.Filename, "bank": bank, "entries": len(parsed.Entries),
+	})
+
+	h.respondReconciliation(c, userID, statement.ID, services.DefaultMatchWindow, opts.Location)
+}
+
+// GET /api/statements: rekening koran yang pernah diupload, terbaru dulu
+func (h *Handler) GetStatements(c *gin.Context) {
+	statements, err := h.statements.ListForUser(getUserID(c))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": statements})
+}
+
+// GET /api/statements/:id?window=3: hasil rekonsiliasi (cocok, belum dicatat, tidak ada di bank)
+func (h *Handler) GetReconciliation(c *gin.Context) {
+	userID := getUserID(c)
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	id, ok := uintParam(c, "id")
+	if !ok {
+		utils.RespondError(c, utils.ErrStatementNotFound)
+		return
+	}
+
+	window := services.DefaultMatchWindow
+	if raw := c.Query("window"); raw != "" {
+		window, err = strconv.Atoi(raw)
+		if err != nil || window < 0 || window > services.MaxMatchWindow {
+			utils.RespondError(c, utils.ErrMatchWindowInvalid)
+			return
+		}
+	}
+
+	h.respondReconciliation(c, userID, id, window, utils.UserLocation(user.Timezone))
+}
+
+// DELETE /api/statements/:id (transaksi yang tertaut / dibuat dari mutasi tetap ada)
+func (h *Handler) DeleteStatement(c *gin.Context) {
+	userID := getUserID(c)
+	id, ok := uintParam(c, "id")
+	if !ok {
+		utils.RespondError(c, utils.ErrStatementNotFound)
+		return
+	}
+	statement, err := h.statements.FindForUser(userID, id)
+	if err != nil {
+		utils.RespondError(c, utils.ErrStatementNotFound.Wrap(err))
+		return
+	}
+	if err := h.statements.Delete(statement); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditStatementDelete, "statement", statement.ID, gin.H{
+		"file": statement.FileName, "bank": statement.Bank,
+	}, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.statement_deleted")})
+}
+
+// POST /api/statements/:id/entries/:entry_id/transaction: catat mutasi yang belum ada di aplikasi.
+// Body opsional {"category": "...", "note": "..."}, default kategori Import & catatan = keterangan mutasi.
+func (h *Handler) CreateFromStatementEntry(c *gin.Context) {
+	userID := getUserID(c)
+	id, entryID, ok := entryParams(c)
+	if !ok {
+		utils.RespondError(c, utils.ErrEntryNotFound)
+		return
+	}
+
+	var input struct {
+		Category string `json:"category" binding:"max=50"`
+		Note     string `json:"note"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			utils.RespondError(c, utils.BindError(err))
+			return
+		}
+	}
+
+	trx, alert, err := h.statementService.CreateFromEntry(userID, id, entryID, input.Category, input.Note)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": utils.T(utils.Lang(c), "msg.transaction_saved"),
+		"data":    trx,
+		"alert":   alert,
+	})
+}
+
+// PUT /api/statements/:id/entries/:entry_id/link {"transaction_id": 12}: tautkan manual ke transaksi yang sudah ada
+func (h *Handler) LinkStatementEntry(c *gin.Context) {
+	userID := getUserID(c)
+	id, entryID, ok := entryParams(c)
+	if !ok {
+		utils.RespondError(c, utils.ErrEntryNotFound)
+		return
+	}
+
+	var input struct {
+		TransactionID uint `json:"transaction_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+
+	if err := h.statementService.Link(userID, id, entryID, input.TransactionID); err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.statement_linked")})
+}
+
+// DELETE /api/statements/:id/entries/:entry_id/link
+func (h *Handler) UnlinkStatementEntry(c *gin.Context) {
+	userID := getUserID(c)
+	id, entryID, ok := entryParams(c)
+	if !ok {
+		utils.RespondError(c, utils.ErrEntryNotFound)
+		return
+	}
+
+	if err := h.statementService.Unlink(userID, id, entryID); err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.statement_unlinked")})
+}
+
+func (h *Handler) respondReconciliation(c *gin.Context, userID, statementID uint, window int, loc *time.Location) {
+	rec, err := h.statementService.Reconcile(userID, statementID, window, loc)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rec,
+		"summary": gin.H{
+			"entries":        len(rec.Matched) + len(rec.MissingInApp),
+			"matched":        len(rec.Matched),
+			"missing_in_app": len(rec.MissingInApp),
+			"extra_in_app":   len(rec.ExtraInApp),
+			"missing_amount": entryTotals(rec.MissingInApp),
+		},
+	})
+}
+
+// entryTotals: total mutasi yang belum dicatat per tipe (income / expense)
+func entryTotals(entries []models.StatementEntry) gin.H {
+	var income, expense int64
+	for _, e := range entries {
+		if e.Type == models.TypeIncome {
+			income += e.Amount
+		} else {
+			expense += e.Amount
+		}
+	}
+	return gin.H{"income": income, "expense": expense}
+}
+
+// statementError menerjemahkan error ParseStatement ke AppError
+func statementError(err error) *utils.AppError {
+	switch {
+	case errors.Is(err, importer.ErrStatementEmpty):
+		return utils.ErrStatementEmpty
+	case errors.Is(err, importer.ErrStatementEncrypted):
+		return utils.ErrStatementEncrypted
+	case errors.Is(err, importer.ErrBankUnknown):
+		return utils.ErrBankInvalid
+	}
+	return importError(err)
+}
+
+func uintParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	return uint(id), err == nil
+}
+
+func entryParams(c *gin.Context) (uint, uint, bool) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	entryID, ok := uintParam(c, "entry_id")
+	return id, entryID, ok
+}
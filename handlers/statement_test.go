@@ -0,0 +1,280 @@
+package handlers_test
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const bcaStatement = "No. rekening : 1234567890\n" +
+	"Periode : 01/03/2025 - 31/03/2025\n" +
+	"Tanggal Transaksi,Keterangan,Cabang,Jumlah,,Saldo\n" +
+	"'01/03,SALDO AWAL,'0000,,,\"1,000,000.00\"\n" +
+	"'03/03,QRIS WARUNG KOPI,'0000,\"25,000.00\",DB,\"975,000.00\"\n" +
+	"'10/03,TRSF E-BANKING CR HONOR,'0000,\"500,000.00\",CR,\"1,475,000.00\"\n" +
+	"'20/03,PLN LISTRIK,'0000,\"75,000.00\",DB,\"1,400,000.00\"\n"
+
+func reconciliation(t *testing.T, res testResponse) (summary map[string]interface{}, entries []map[string]interface{}) {
+	t.Helper()
+	expectStatus(t, res, http.StatusOK)
+	summary = res.Body["summary"].(map[string]interface{})
+	for _, item := range res.Body["data"].(map[string]interface{})["missing_in_app"].([]interface{}) {
+		entries = append(entries, item.(map[string]interface{}))
+	}
+	return summary, entries
+}
+
+func TestStatementReconciliation(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	coffee := app.createTransaction(user, "expense", 25000, "Makan", time.Date(2025, time.March, 4, 8, 0, 0, 0, jakarta))
+	app.createTransaction(user, "income", 500000, "Honor", time.Date(2025, time.March, 10, 15, 0, 0, 0, jakarta))
+	cash := app.createTransaction(user, "expense", 15000, "Parkir", time.Date(2025, time.March, 15, 9, 0, 0, 0, jakarta))
+	app.createTransaction(user, "expense", 99000, "Bulan depan", time.Date(2025, time.April, 5, 9, 0, 0, 0, jakarta))
+
+	res := app.upload("/api/statements", token, "mutasi-bca.csv", []byte(bcaStatement), map[string]string{"bank": "bca"})
+	summary, missing := reconciliation(t, res)
+	if summary["matched"] != float64(2) || summary["missing_in_app"] != float64(1) || summary["extra_in_app"] != float64(1) {
+		t.Fatalf("summary = %v\nbody: %s", summary, res.Raw)
+	}
+	data := res.Body["data"].(map[string]interface{})
+	statementID := int(data["statement"].(map[string]interface{})["id"].(float64))
+	if data["statement"].(map[string]interface{})["account_number"] != "1234567890" {
+		t.Errorf("statement = %v", data["statement"])
+	}
+	// Kopi dibukukan bank sehari sebelum dicatat: tetap cocok dalam jendela 3 hari
+	first := data["matched"].([]interface{})[0].(map[string]interface{})
+	if first["day_diff"] != float64(1) || first["linked"] != false ||
+		first["transaction"].(map[string]interface{})["id"] != float64(coffee.ID) {
+		t.Errorf("pasangan pertama = %v", first)
+	}
+	if extra := data["extra_in_app"].([]interface{}); extra[0].(map[string]interface{})["id"] != float64(cash.ID) {
+		t.Errorf("extra_in_app = %v, mau hanya parkir (transaksi April di luar periode)", extra)
+	}
+	base := fmt.Sprintf("/api/statements/%d", statementID)
+
+	// Jendela 0 hari: kopi tidak lagi cocok
+	summary, _ = reconciliation(t, app.do(http.MethodGet, base+"?window=0", token, nil))
+	if summary["matched"] != float64(1) || summary["missing_in_app"] != float64(2) || summary["extra_in_app"] != float64(2) {
+		t.Fatalf("summary window=0 = %v", summary)
+	}
+	expectError(t, app.do(http.MethodGet, base+"?window=30", token, nil), http.StatusBadRequest, utils.CodeMatchWindowInvalid)
+
+	// Satu klik: mutasi listrik jadi transaksi baru yang langsung tertaut
+	entryPath := fmt.Sprintf("%s/entries/%d", base, int(missing[0]["id"].(float64)))
+	res = app.do(http.MethodPost, entryPath+"/transaction", token, map[string]string{"category": "Tagihan"})
+	expectStatus(t, res, http.StatusOK)
+	created := res.Body["data"].(map[string]interface{})
+	if created["amount"] != float64(75000) || created["category"] != "Tagihan" || created["note"] != "PLN LISTRIK" {
+		t.Fatalf("transaksi dari mutasi = %v", created)
+	}
+	expectError(t, app.do(http.MethodPost, entryPath+"/transaction", token, nil), http.StatusConflict, utils.CodeEntryLinked)
+
+	summary, _ = reconciliation(t, app.do(http.MethodGet, base, token, nil))
+	if summary["matched"] != float64(3) || summary["missing_in_app"] != float64(0) {
+		t.Fatalf("summary setelah create = %v", summary)
+	}
+
+	// Tautan manual: transaksi yang sudah tertaut tidak bisa dipakai mutasi lain
+	res = app.do(http.MethodGet, base, token, nil)
+	var coffeeEntry int
+	for _, item := range res.Body["data"].(map[string]interface{})["matched"].([]interface{}) {
+		match := item.(map[string]interface{})
+		if match["entry"].(map[string]interface{})["description"] == "QRIS WARUNG KOPI" {
+			coffeeEntry = int(match["entry"].(map[string]interface{})["id"].(float64))
+		}
+	}
+	coffeePath := fmt.Sprintf("%s/entries/%d/link", base, coffeeEntry)
+	expectError(t, app.do(http.MethodPut, coffeePath, token, map[string]uint{"transaction_id": uint(created["id"].(float64))}),
+		http.StatusConflict, utils.CodeEntryLinked)
+	expectError(t, app.do(http.MethodPut, coffeePath, token, map[string]uint{"transaction_id": 9999}),
+		http.StatusNotFound, utils.CodeTransactionNotFound)
+	expectStatus(t, app.do(http.MethodPut, coffeePath, token, map[string]uint{"transaction_id": cash.ID}), http.StatusOK)
+
+	res = app.do(http.MethodGet, base, token, nil)
+	summary, _ = reconciliation(t, res)
+	if summary["extra_in_app"] != float64(1) {
+		t.Fatalf("summary setelah link = %v", summary)
+	}
+	if extra := res.Body["data"].(map[string]interface{})["extra_in_app"].([]interface{}); extra[0].(map[string]interface{})["id"] != float64(coffee.ID) {
+		t.Errorf("extra_in_app setelah link = %v, mau transaksi kopi", extra)
+	}
+	expectStatus(t, app.do(http.MethodDelete, coffeePath, token, nil), http.StatusOK)
+	summary, _ = reconciliation(t, app.do(http.MethodGet, base, token, nil))
+	if summary["matched"] != float64(3) || summary["extra_in_app"] != float64(1) {
+		t.Fatalf("summary setelah unlink = %v", summary)
+	}
+
+	// Rekening koran milik user lain tidak terlihat
+	other := app.token(app.createUser("sari", "user", "trial"))
+	expectError(t, app.do(http.MethodGet, base, other, nil), http.StatusNotFound, utils.CodeStatementNotFound)
+
+	res = app.do(http.MethodGet, "/api/statements", token, nil)
+	if list := res.Body["data"].([]interface{}); len(list) != 1 {
+		t.Fatalf("daftar rekening koran = %v", list)
+	}
+	expectStatus(t, app.do(http.MethodDelete, base, token, nil), http.StatusOK)
+	expectError(t, app.do(http.MethodGet, base, token, nil), http.StatusNotFound, utils.CodeStatementNotFound)
+}
+
+func TestStatementUploadValidation(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	res := app.upload("/api/statements", token, "mutasi.csv", []byte(bcaStatement), map[string]string{"bank": "bni"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeBankInvalid)
+	res = app.upload("/api/statements", token, "mutasi.xlsx", []byte(bcaStatement), map[string]string{"bank": "bca"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeStatementFormatInvalid)
+	res = app.upload("/api/statements", token, "mutasi.csv", []byte("Tanggal,Keterangan\n"), map[string]string{"bank": "mandiri"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeStatementEmpty)
+	res = app.upload("/api/statements", token, "estatement.pdf", []byte("%PDF-1.4\ntrailer << /Encrypt 3 0 R >>\n"), map[string]string{"bank": "bca"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeStatementEncrypted)
+}
+
+// racyStatements: fake repository yang menahan request sampai semuanya selesai membaca mutasi,
+// seperti double klik yang lolos cek "belum tertaut" bersamaan
+type racyStatements struct {
+	repository.StatementRepository
+	pending atomic.Int32
+	reads   sync.WaitGroup
+}
+
+func (r *racyStatements) FindEntry(statementID, entryID uint) (*models.StatementEntry, error) {
+	entry, err := r.StatementRepository.FindEntry(statementID, entryID)
+	if r.pending.Add(-1) >= 0 {
+		r.reads.Done()
+		r.reads.Wait()
+	}
+	return entry, err
+}
+
+func TestStatementCreateFromEntryDoubleClick(t *testing.T) {
+	const clicks = 2
+
+	base := newTestApp(t)
+	repos := *base.repos
+	statements := &racyStatements{StatementRepository: repos.Statements}
+	repos.Statements = statements
+	app := newTestAppWith(t, base.db, &repos)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	res := app.upload("/api/statements", token, "mutasi-bca.csv", []byte(bcaStatement), map[string]string{"bank": "bca"})
+	_, missing := reconciliation(t, res)
+	statementID := int(res.Body["data"].(map[string]interface{})["statement"].(map[string]interface{})["id"].(float64))
+	var path string
+	for _, entry := range missing {
+		if entry["description"] == "PLN LISTRIK" {
+			path = fmt.Sprintf("/api/statements/%d/entries/%d/transaction", statementID, int(entry["id"].(float64)))
+		}
+	}
+
+	statements.pending.Store(clicks)
+	statements.reads.Add(clicks)
+	results := make([]testResponse, clicks)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = app.do(http.MethodPost, path, token, nil)
+		}()
+	}
+	wg.Wait()
+
+	codes := map[int]int{}
+	for _, res := range results {
+		codes[res.Status]++
+		if res.Status == http.StatusConflict && res.Code() != utils.CodeEntryLinked {
+			t.Errorf("error = %s, mau %s", res.Code(), utils.CodeEntryLinked)
+		}
+	}
+	if codes[http.StatusOK] != 1 || codes[http.StatusConflict] != 1 {
+		t.Fatalf("status = %v, mau satu berhasil & satu ditolak", codes)
+	}
+	var count int64
+	app.db.Model(&models.Transaction{}).Where("user_id = ? AND note = ?", user.ID, "PLN LISTRIK").Count(&count)
+	if count != 1 {
+		t.Fatalf("transaksi PLN LISTRIK = %d, mau 1", count)
+	}
+}
+
+func TestStatementLinkConcurrent(t *testing.T) {
+	const requests = 2
+
+	base := newTestApp(t)
+	repos := *base.repos
+	statements := &racyStatements{StatementRepository: repos.Statements}
+	repos.Statements = statements
+	app := newTestAppWith(t, base.db, &repos)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	res := app.upload("/api/statements", token, "mutasi-bca.csv", []byte(bcaStatement), map[string]string{"bank": "bca"})
+	_, missing := reconciliation(t, res)
+	statementID := int(res.Body["data"].(map[string]interface{})["statement"].(map[string]interface{})["id"].(float64))
+	entries := map[string]uint{}
+	for _, entry := range missing {
+		entries[entry["description"].(string)] = uint(entry["id"].(float64))
+	}
+	linkPath := func(description string) string {
+		return fmt.Sprintf("/api/statements/%d/entries/%d/link", statementID, entries[description])
+	}
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	cash := app.createTransaction(user, "expense", 15000, "Parkir", time.Date(2025, time.March, 15, 9, 0, 0, 0, jakarta))
+	other := app.createTransaction(user, "income", 500000, "Honor", time.Date(2025, time.March, 11, 9, 0, 0, 0, jakarta))
+
+	// race: kirim semua request bersamaan, semuanya lolos baca mutasi sebelum ada yang menulis
+	race := func(paths []string, transactionIDs []uint) {
+		t.Helper()
+		statements.pending.Store(requests)
+		statements.reads.Add(requests)
+		results := make([]testResponse, requests)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = app.do(http.MethodPut, paths[i], token, map[string]uint{"transaction_id": transactionIDs[i]})
+			}()
+		}
+		wg.Wait()
+
+		codes := map[int]int{}
+		for _, res := range results {
+			codes[res.Status]++
+			if res.Status == http.StatusConflict && res.Code() != utils.CodeEntryLinked {
+				t.Errorf("error = %s, mau %s", res.Code(), utils.CodeEntryLinked)
+			}
+		}
+		if codes[http.StatusOK] != 1 || codes[http.StatusConflict] != 1 {
+			t.Fatalf("status = %v, mau satu berhasil & satu ditolak", codes)
+		}
+	}
+
+	// Satu transaksi ke dua mutasi berbeda: hanya satu mutasi yang tertaut
+	race([]string{linkPath("QRIS WARUNG KOPI"), linkPath("PLN LISTRIK")}, []uint{cash.ID, cash.ID})
+	var count int64
+	app.db.Model(&models.StatementEntry{}).Where("transaction_id = ?", cash.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("mutasi tertaut ke transaksi parkir = %d, mau 1", count)
+	}
+
+	// Satu mutasi ke dua transaksi berbeda: tautan yang menang tidak ditimpa yang kalah
+	honor := linkPath("TRSF E-BANKING CR HONOR")
+	third := app.createTransaction(user, "income", 500000, "Honor", time.Date(2025, time.March, 10, 15, 0, 0, 0, jakarta))
+	race([]string{honor, honor}, []uint{other.ID, third.ID})
+	app.db.Model(&models.StatementEntry{}).Where("transaction_id IN ?", []uint{other.ID, third.ID}).Count(&count)
+	if count != 1 {
+		t.Fatalf("mutasi tertaut ke transaksi honor = %d, mau 1", count)
+	}
+}
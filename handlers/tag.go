@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"backend-gin/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/tags?period=this_month
+// Total pemasukan/pengeluaran per tag (#hashtag di catatan) dalam periode, urut pengeluaran terbesar.
+func (h *Handler) GetTagSummary(c *gin.Context) {
+	userID := getUserID(c)
+	period, _, appErr := h.period(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+
+	results, currency, err := h.trxService.TagSummary(userID, period.From, period.To)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results, "currency": currency, "period": periodJSON(period)})
+}
+
+// tagsQuery: ?tag=kantor&tag=liburan atau ?tag=kantor,#liburan (tanpa '#' juga boleh)
+func tagsQuery(c *gin.Context) []string {
+	var tags []string
+	for _, raw := range c.QueryArray("tag") {
+		for _, tag := range strings.Split(raw, ",") {
+			if tag = utils.NormalizeTag(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
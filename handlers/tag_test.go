@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"backend-gin/jobs"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTransactionTags(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("sari", "user", "trial")
+	token := app.token(user)
+
+	res := app.do(http.MethodPost, "/api/transactions", token, map[string]string{
+		"type": "expense", "amount": "150000", "category": "Hotel", "note": "Menginap #Liburan-Bali #kantor #kantor",
+	})
+	expectStatus(t, res, http.StatusOK)
+	tags := res.Body["data"].(map[string]interface{})["tags"].([]interface{})
+	if len(tags) != 2 || tags[0].(map[string]interface{})["name"] != "liburan-bali" {
+		t.Fatalf("tags = %v", tags)
+	}
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{
+		"type": "expense", "amount": "20000", "category": "Makan", "note": "makan siang #kantor",
+	})
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{
+		"type": "income", "amount": "50000", "category": "Reimburse", "note": "#KANTOR",
+	})
+	expectStatus(t, res, http.StatusOK)
+	app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "5000", "category": "Parkir"})
+	app.do(http.MethodPost, "/api/transactions", app.token(other), map[string]string{
+		"type": "expense", "amount": "99000", "category": "Makan", "note": "#kantor",
+	})
+
+	// Satu baris tag per user per nama
+	var count int64
+	app.db.Model(&models.Tag{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 2 {
+		t.Fatalf("tag user = %d", count)
+	}
+
+	res = app.do(http.MethodGet, "/api/transactions?tag=%23Kantor", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if list := res.Body["data"].([]interface{}); len(list) != 3 {
+		t.Fatalf("filter #kantor = %d transaksi", len(list))
+	}
+	res = app.do(http.MethodGet, "/api/transactions?tag=kantor,liburan-bali&type=expense", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if list := res.Body["data"].([]interface{}); len(list) != 2 {
+		t.Fatalf("filter 2 tag = %d transaksi", len(list))
+	}
+
+	res = app.do(http.MethodGet, "/api/tags", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	data := res.Body["data"].([]interface{})
+	if len(data) != 2 {
+		t.Fatalf("tags = %v", data)
+	}
+	first := data[0].(map[string]interface{})
+	if first["tag"] != "kantor" || first["count"] != float64(3) || first["expense"] != float64(170000) ||
+		first["income"] != float64(50000) || first["net"] != float64(-120000) {
+		t.Errorf("kantor = %v", first)
+	}
+
+	// Export: kolom tag ikut di tiap baris
+	res = app.do(http.MethodGet, "/api/export?format=jsonl", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	exported := make(map[string][]interface{})
+	for _, line := range bytes.Split(bytes.TrimSpace(res.Raw), []byte("\n")) {
+		var row map[string]interface{}
+		json.Unmarshal(line, &row)
+		tags, _ := row["tags"].([]interface{})
+		exported[row["category"].(string)] = tags
+	}
+	if tags := exported["Hotel"]; len(tags) != 2 || tags[1] != "kantor" || exported["Parkir"] != nil {
+		t.Errorf("tag export = %v", exported)
+	}
+
+	// Dihapus permanen dari tong sampah beserta relasi tagnya
+	trx, _, err := app.repos.Transactions.List(repository.TransactionFilter{UserID: user.ID, Tags: []string{"liburan-bali"}, Page: 1, Limit: 10})
+	if err != nil || len(trx) != 1 {
+		t.Fatalf("transaksi liburan = %d (%v)", len(trx), err)
+	}
+	if _, err := app.repos.Transactions.Delete(user.ID, trx[0].ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := jobs.PurgeTrash(app.repos, time.Now().AddDate(0, 0, 31)); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	app.db.Model(&models.TransactionTag{}).Where("transaction_id = ?", trx[0].ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d relasi tag tersisa", count)
+	}
+}
+
+func TestBotTransactionTags(t *testing.T) {
+	newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+
+	app.botMessage(777, "-20000 Makan nasi padang #kantor")
+	trx, _, err := app.repos.Transactions.List(repository.TransactionFilter{UserID: user.ID, Tags: []string{"kantor"}, Page: 1, Limit: 10})
+	if err != nil || len(trx) != 1 {
+		t.Fatalf("transaksi #kantor = %d (%v)", len(trx), err)
+	}
+	if len(trx[0].Tags) != 1 || trx[0].Tags[0].Name != "kantor" {
+		t.Fatalf("tags = %+v", trx[0].Tags)
+	}
+}
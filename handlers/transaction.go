@@ -1,8 +1,13 @@
 package handlers
 
 import (
-	"backend-gin/database"
 	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/repository"
+	"backend-gin/services"
+	"backend-gin/utils"
+	"encoding/base64"
+	"encoding/json"
 	"math"
 	"net/http"
 	"strconv"
@@ -21,42 +26,11 @@ func getUserID(c *gin.Context) uint {
 	return id.(uint)
 }
 
-// Helper: Cek Limit Harian (Dipakai oleh Web & Bot)
-func CheckDailyLimit(userID uint, currentAmount int) string {
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		return ""
-	}
-
-	if user.DailyLimit <= 0 {
-		return ""
-	}
-
-	var totalToday int
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	database.DB.Model(&models.Transaction{}).
-		Where("user_id = ? AND type = 'expense' AND created_at >= ?", userID, startOfDay).
-		Select("COALESCE(SUM(amount), 0)").Row().Scan(&totalToday)
-
-	// Tambahkan transaksi yang baru saja akan diinput untuk pengecekan prediksi
-	// (Opsional, atau cek total existing + new)
-	if (totalToday + currentAmount) >= user.DailyLimit {
-		pesan := user.AlertMessage
-		if pesan == "" {
-			pesan = "⚠️ <b>WARNING:</b> Kamu sudah melebihi budget harian!"
-		}
-		return pesan
-	}
-
-	return ""
-}
-
-// 1. GET ALL TRANSACTIONS (PAGINATION) - Tetap seperti sebelumnya
-func GetTransactions(c *gin.Context) {
+// 1. GET ALL TRANSACTIONS (PAGINATION)
+// Offset (?page=) tetap didukung, tapi lebih baik pakai ?cursor= dari meta.next_cursor:
+// halaman tidak bergeser walau ada transaksi baru dan tetap cepat di riwayat panjang.
+func (h *Handler) GetTransactions(c *gin.Context) {
 	userID := getUserID(c)
-	var trx []models.Transaction
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -65,23 +39,48 @@ func GetTransactions(c *gin.Context) {
 	if limit < 1 { limit = 10 }
 	if limit > 100 { limit = 100 }
 
-	offset := (page - 1) * limit
-
-	query := database.DB.Model(&models.Transaction{}).Where("user_id = ?", userID)
-
-	if tipe := c.Query("type"); tipe != "" {
-		query = query.Where("type = ?", tipe)
+	filter, period, appErr := h.listFilter(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+	filter.Page = page
+	filter.Limit = limit
+	if appErr := listSort(c, &filter); appErr != nil {
+		utils.RespondError(c, appErr)
+		return
 	}
 
-	if search := c.Query("search"); search != "" {
-		search = "%" + search + "%"
-		query = query.Where("category LIKE ? OR note LIKE ?", search, search)
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, ok := decodeListCursor(raw)
+		if !ok || cursor.Sort != filter.Sort || cursor.Asc != filter.Asc {
+			utils.RespondError(c, utils.ErrCursorInvalid)
+			return
+		}
+		filter.After = &cursor.TransactionCursor
+		page = cursor.Page
 	}
 
-	var total int64
-	query.Count(&total)
+	trx, total, err := h.transactions.List(filter)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 
-	query.Order("created_at desc").Limit(limit).Offset(offset).Find(&trx)
+	// Repository mengambil satu baris ekstra untuk tahu masih ada halaman berikutnya
+	var nextCursor interface{}
+	if len(trx) > limit {
+		trx = trx[:limit]
+		last := trx[limit-1]
+		nextCursor = encodeListCursor(listCursor{
+			Sort: filter.Sort,
+			Asc:  filter.Asc,
+			Page: page + 1,
+			TransactionCursor: repository.TransactionCursor{
+				CreatedAt: last.CreatedAt, Amount: last.Amount, Category: last.Category, ID: last.ID,
+			},
+		})
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": trx,
@@ -90,222 +89,324 @@ func GetTransactions(c *gin.Context) {
 			"limit":        limit,
 			"total_data":   total,
 			"total_pages":  math.Ceil(float64(total) / float64(limit)),
+			"period":       periodJSON(period),
+			"next_cursor":  nextCursor,
 		},
 	})
 }
 
+// listFilter: filter daftar transaksi dari query string (?type, ?search, ?category, ?tag, periode,
+// ?currency & batas nominal). Dipakai GET /api/transactions dan operasi massal.
+func (h *Handler) listFilter(c *gin.Context, userID uint) (repository.TransactionFilter, utils.Period, *utils.AppError) {
+	filterType := models.TransactionType(c.Query("type"))
+	if filterType != "" && !filterType.Valid() {
+		return repository.TransactionFilter{}, utils.Period{}, utils.ErrInvalidInput.WithField("type", utils.CodeTransactionTypeInvalid)
+	}
+
+	period, _, appErr := h.period(c, userID)
+	if appErr != nil {
+		return repository.TransactionFilter{}, utils.Period{}, appErr
+	}
+
+	filter := repository.TransactionFilter{
+		UserID:     userID,
+		Type:       filterType,
+		Search:     c.Query("search"),
+		Categories: categoriesQuery(c),
+		Tags:       tagsQuery(c),
+		From:       period.From,
+		To:         period.To,
+	}
+	if appErr := h.amountFilter(c, userID, &filter); appErr != nil {
+		return repository.TransactionFilter{}, utils.Period{}, appErr
+	}
+	return filter, period, nil
+}
+
+// categoriesQuery: ?category=Makan&category=Transport atau ?category=Makan,Transport
+func categoriesQuery(c *gin.Context) []string {
+	var categories []string
+	for _, raw := range c.QueryArray("category") {
+		for _, category := range strings.Split(raw, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
+
+// listSort: ?sort=date|amount|category&order=asc|desc (default tanggal terbaru dulu, kategori A-Z)
+func listSort(c *gin.Context, filter *repository.TransactionFilter) *utils.AppError {
+	filter.Sort = strings.ToLower(c.DefaultQuery("sort", repository.SortDate))
+	switch filter.Sort {
+	case repository.SortDate, repository.SortAmount, repository.SortCategory:
+	default:
+		return utils.ErrInvalidInput.WithField("sort", utils.CodeFieldInvalid)
+	}
+
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+		filter.Asc = filter.Sort == repository.SortCategory
+	case "asc":
+		filter.Asc = true
+	case "desc":
+		filter.Asc = false
+	default:
+		return utils.ErrInvalidInput.WithField("order", utils.CodeFieldInvalid)
+	}
+	return nil
+}
+
+// listCursor: isi token ?cursor= (base64 JSON, dianggap opak oleh frontend)
+type listCursor struct {
+	Sort string `json:"s"`
+	Asc  bool   `json:"a"`
+	Page int    `json:"p"` // nomor halaman, supaya meta.current_page tetap terisi
+	repository.TransactionCursor
+}
+
+func encodeListCursor(cursor listCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeListCursor(token string) (listCursor, bool) {
+	var cursor listCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(raw, &cursor) != nil || cursor.ID == 0 || cursor.Page < 2 {
+		return listCursor{}, false
+	}
+	return cursor, true
+}
+
+// amountFilter: ?currency=USD&min_amount=10&max_amount=12.50.
+// Nominal ditulis dalam satuan utama mata uang filter (default mata uang dasar user), jadi
+// filter nominal otomatis membatasi daftar ke mata uang itu.
+func (h *Handler) amountFilter(c *gin.Context, userID uint, filter *repository.TransactionFilter) *utils.AppError {
+	if raw := c.Query("currency"); raw != "" {
+		if filter.Currency = money.Normalize(raw); filter.Currency == "" {
+			return utils.ErrCurrencyInvalid
+		}
+	}
+
+	minRaw, maxRaw := c.Query("min_amount"), c.Query("max_amount")
+	if minRaw == "" && maxRaw == "" {
+		return nil
+	}
+	if filter.Currency == "" {
+		base, err := h.trxService.BaseCurrency(userID)
+		if err != nil {
+			return utils.ErrInternal.Wrap(err)
+		}
+		filter.Currency = base
+	}
+
+	var err error
+	if minRaw != "" {
+		if filter.MinAmount, err = money.Parse(minRaw, filter.Currency); err != nil {
+			return utils.ErrInvalidInput.WithField("min_amount", utils.CodeInvalidAmount)
+		}
+	}
+	if maxRaw != "" {
+		if filter.MaxAmount, err = money.Parse(maxRaw, filter.Currency); err != nil {
+			return utils.ErrInvalidInput.WithField("max_amount", utils.CodeInvalidAmount)
+		}
+	}
+	if filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount {
+		return utils.ErrInvalidInput.WithField("max_amount", utils.CodeFieldInvalid)
+	}
+	return nil
+}
+
 // 2. CREATE TRANSACTION (WEB INPUT) - FITUR BARU
-func CreateTransaction(c *gin.Context) {
+func (h *Handler) CreateTransaction(c *gin.Context) {
 	userID := getUserID(c)
 
 	// Gunakan struct khusus untuk menerima input string (biar bisa handle "100.000")
 	var input struct {
-		Type     string `json:"type" binding:"required"`     // income / expense
-		Amount   string `json:"amount" binding:"required"`   // String: "100.000" atau "50000"
-		Category string `json:"category" binding:"required"` // Gaji, Makan, dll
-		Note     string `json:"note"`                        // Opsional
+		Type     string       `json:"type" binding:"required"`                    // income / expense
+		Amount   string       `json:"amount" binding:"required"`                  // String: "100.000" atau "12.50"
+		Category string       `json:"category" binding:"required_without=Splits"` // Gaji, Makan, dll (split: diisi otomatis)
+		Note     string       `json:"note"`                                       // Opsional
+		Currency string       `json:"currency"`                                   // Opsional, default mata uang dompet / dasar user
+		WalletID *uint        `json:"wallet_id"`                                  // Opsional
+		Splits   []splitInput `json:"splits"`                                     // Opsional: rincian per kategori, total = amount
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
-	now := time.Now()
-    database.DB.Model(&models.User{}).Where("id = ?", userID).Update("last_transaction_at", now)
+	// Mata uang harus diketahui dulu: jumlah desimal tiap mata uang berbeda
+	currency, err := h.trxService.ResolveCurrency(userID, input.WalletID, input.Currency)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
 
-	// Bersihkan format Rupiah (Hapus titik dan koma)
-	cleanAmount := strings.ReplaceAll(input.Amount, ".", "")
-	cleanAmount = strings.ReplaceAll(cleanAmount, ",", "")
-	
-	amountInt, err := strconv.Atoi(cleanAmount)
+	// Bersihkan format angka (pemisah ribuan / desimal) jadi minor unit
+	amount, err := money.Parse(input.Amount, currency)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format jumlah uang salah. Harusnya angka."})
+		utils.RespondError(c, utils.ErrInvalidAmount)
+		return
+	}
+	splits, appErr := parseSplits(input.Splits, currency)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
 		return
 	}
 
 	// Simpan
 	trx := models.Transaction{
-		UserID:   userID,
-		Amount:   amountInt,
-		Type:     input.Type,
-		Category: input.Category,
-		Note:     input.Note,
+		UserID:    userID,
+		Amount:    amount,
+		Currency:  currency,
+		WalletID:  input.WalletID,
+		Type:      models.TransactionType(input.Type),
+		Category:  input.Category,
+		Note:      input.Note,
 		CreatedAt: time.Now(),
+		Splits:    splits,
 	}
 
-	if err := database.DB.Create(&trx).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data"})
+	// Simpan + Cek Alert Limit (Hanya return pesan warning, tidak error)
+	alertMsg, err := h.trxService.Create(&trx)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
 		return
 	}
 
-	// Cek Alert Limit (Hanya return pesan warning, tidak error)
-	alertMsg := ""
-	if input.Type == "expense" {
-		// Kita cek limit berdasarkan total HARI INI (termasuk yg baru diinput)
-		// Note: helper CheckDailyLimit menghitung total di DB. 
-		// Karena data baru SUDAH tersimpan barusan, maka CheckDailyLimit akan menghitungnya.
-		// Namun helper saya tadi menghitung total yg ada di DB. 
-		// Karena kita panggil create dulu baru cek, maka totalnya valid.
-		// Tapi helper `CheckDailyLimit` saya modif sedikit logicnya di atas.
-		// Mari kita panggil helpernya:
-		alertMsg = CheckDailyLimit(userID, 0) // Param kedua 0 karena data sudah masuk DB
-	}
-
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Berhasil disimpan!",
+		"message": utils.T(utils.Lang(c), "msg.transaction_saved"),
 		"data":    trx,
 		"alert":   alertMsg,
 	})
 }
 
 // 3. GET TODAY TRANSACTIONS (UNTUK TABEL BAWAH) - FITUR BARU
-func GetTodayTransactions(c *gin.Context) {
+func (h *Handler) GetTodayTransactions(c *gin.Context) {
 	userID := getUserID(c)
-	var trx []models.Transaction
 
-	now := time.Now()
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	// "Hari ini" menurut zona waktu user
+	now := userNow(user)
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	// Ambil semua transaksi hari ini, urutkan dari yg terbaru
-	database.DB.Where("user_id = ? AND created_at >= ?", userID, startOfDay).
-		Order("created_at desc").
-		Find(&trx)
+	trx, err := h.transactions.FindInPeriod(userID, startOfDay, time.Time{}, true)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"data": trx})
 }
 
 // 4. DELETE TRANSACTION (WEB DELETE) - FITUR BARU
-func DeleteTransaction(c *gin.Context) {
+func (h *Handler) DeleteTransaction(c *gin.Context) {
 	userID := getUserID(c)
-	id := c.Param("id")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrTransactionNotFound)
+		return
+	}
 
 	// Pastikan user menghapus data miliknya sendiri
-	result := database.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Transaction{})
-
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus"})
+	// Soft delete: data masuk tong sampah dan masih bisa di-restore sampai masa simpan habis
+	deleted, err := h.transactions.Delete(userID, uint(id))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Data tidak ditemukan atau bukan milikmu"})
+	if !deleted {
+		utils.RespondError(c, utils.ErrTransactionNotFound)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Transaksi berhasil dihapus"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.transaction_deleted", utils.TrashRetentionDays())})
 }
 
 // 5. GET SUMMARY
-func GetSummary(c *gin.Context) {
+func (h *Handler) GetSummary(c *gin.Context) {
 	userID := getUserID(c)
-	var trx []models.Transaction
-	database.DB.Where("user_id = ?", userID).Find(&trx)
-
-	var income, expense int
-	for _, t := range trx {
-		if t.Type == "income" {
-			income += t.Amount
-		} else if t.Type == "expense" {
-			expense += t.Amount
-		}
+	period, _, appErr := h.period(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+
+	income, expense, currency, err := h.trxService.Summary(userID, period.From, period.To)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"total_income":  income,
 		"total_expense": expense,
 		"balance":       income - expense,
+		"currency":      currency,
+		"period":        periodJSON(period),
 	})
 }
 
 // 6. GET DAILY CHART
-// 3. GET CHART DATA (Support Filter Bulan)
-func GetDailyChart(c *gin.Context) {
+// 3. GET CHART DATA (Support Filter Periode, default 30 hari terakhir)
+func (h *Handler) GetDailyChart(c *gin.Context) {
 	userID := getUserID(c)
-	var trx []models.Transaction
-	
-	// Default: Ambil 30 hari terakhir
-	query := database.DB.Where("user_id = ?", userID)
 
-	monthStr := c.Query("month")
-	yearStr := c.Query("year")
-
-	// Jika ada filter bulan & tahun
-	if monthStr != "" && yearStr != "" {
-		month, _ := strconv.Atoi(monthStr)
-		year, _ := strconv.Atoi(yearStr)
-		
-		// Tanggal 1 bulan tersebut
-		startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
-		// Tanggal 1 bulan berikutnya (batas atas)
-		endDate := startDate.AddDate(0, 1, 0)
-
-		query = query.Where("created_at >= ? AND created_at < ?", startDate, endDate)
-	} else {
-		// Fallback: 30 Hari Terakhir
-		last30Days := time.Now().AddDate(0, 0, -30)
-		query = query.Where("created_at >= ?", last30Days)
+	q, now, appErr := h.periodQuery(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
 	}
 
-	query.Order("created_at asc").Find(&trx)
-
-	type DailyStats struct {
-		Date    string `json:"date"`
-		Income  int    `json:"income"`
-		Expense int    `json:"expense"`
+	var period utils.Period
+	if q.Empty() {
+		// Fallback: 30 Hari Terakhir + hari ini
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		period = utils.Period{From: today.AddDate(0, 0, -30), To: today.AddDate(0, 0, 1)}
+	} else if period, appErr = utils.ResolvePeriod(q, now); appErr != nil {
+		utils.RespondError(c, appErr)
+		return
 	}
-	
-	statsMap := make(map[string]*DailyStats)
-	for _, t := range trx {
-		// Format tanggal YYYY-MM-DD
-		dateStr := t.CreatedAt.Format("2006-01-02")
-		if _, exists := statsMap[dateStr]; !exists {
-			statsMap[dateStr] = &DailyStats{Date: dateStr}
-		}
-		if t.Type == "income" {
-			statsMap[dateStr].Income += t.Amount
-		} else {
-			statsMap[dateStr].Expense += t.Amount
-		}
+
+	// Grafik butuh rentang berbatas (satu titik per hari)
+	if !period.Bounded() || period.To.After(period.From.AddDate(0, 0, services.MaxChartDays)) {
+		utils.RespondError(c, utils.ErrPeriodTooLong)
+		return
 	}
 
-	var result []DailyStats
-	for _, v := range statsMap {
-		result = append(result, *v)
+	result, currency, err := h.trxService.DailyChart(userID, period.From, period.To)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": result})
+	c.JSON(http.StatusOK, gin.H{"data": result, "currency": currency, "period": periodJSON(period)})
 }
 
 // 7. GET CATEGORIES
-func GetCategorySummary(c *gin.Context) {
+func (h *Handler) GetCategorySummary(c *gin.Context) {
 	userID := getUserID(c)
-	var trx []models.Transaction
-	database.DB.Where("user_id = ?", userID).Find(&trx)
-
-	type CatStats struct {
-		Category string `json:"category"`
-		Total    int    `json:"total"`
-		Type     string `json:"type"`
-	}
-
-	tempMap := make(map[string]int)
-	for _, t := range trx {
-		key := t.Type + "-" + t.Category
-		tempMap[key] += t.Amount
-	}
-
-	var results []CatStats
-	for key, total := range tempMap {
-		parts := strings.Split(key, "-")
-		if len(parts) >= 2 {
-			results = append(results, CatStats{
-				Type:     parts[0],
-				Category: parts[1],
-				Total:    total,
-			})
-		}
+	period, _, appErr := h.period(c, userID)
+	if appErr != nil {
+		utils.RespondError(c, appErr)
+		return
+	}
+
+	results, currency, err := h.trxService.CategorySummary(userID, period.From, period.To)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": results})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{"data": results, "currency": currency, "period": periodJSON(period)})
+}
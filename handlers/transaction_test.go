@@ -0,0 +1,722 @@
+package handlers_test
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCreateTransaction(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	res := app.do(http.MethodPost, "/api/transactions", token, map[string]string{
+		"type": "expense", "amount": "25.000", "category": "Makan", "note": "nasi padang",
+	})
+	expectStatus(t, res, http.StatusOK)
+
+	data := res.Body["data"].(map[string]interface{})
+	if data["amount"] != float64(25000) {
+		t.Fatalf("amount = %v, mau 25000 (format Rupiah dibersihkan)", data["amount"])
+	}
+	if res.Body["alert"] != "" {
+		t.Fatalf("alert = %q, mau kosong (tanpa limit harian)", res.Body["alert"])
+	}
+
+	// Waktu input terakhir dicatat untuk admin
+	saved, err := app.repos.Users.FindByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.LastTransactionAt == nil {
+		t.Fatal("last_transaction_at tidak diisi")
+	}
+}
+
+func TestCreateTransactionDailyLimitAlert(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	if err := app.repos.Users.UpdateFields(user.ID, map[string]interface{}{"daily_limit": 50000, "alert_message": "Boros!"}); err != nil {
+		t.Fatal(err)
+	}
+	token := app.token(user)
+
+	res := app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "30000", "category": "Makan"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["alert"] != "" {
+		t.Fatalf("alert = %q, mau kosong (masih di bawah limit)", res.Body["alert"])
+	}
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "20000", "category": "Makan"})
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["alert"] != "Boros!" {
+		t.Fatalf("alert = %q, mau pesan alert user", res.Body["alert"])
+	}
+}
+
+func TestCreateTransactionValidation(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	res := app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "abc", "category": "Makan"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidAmount)
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "1000"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "transfer", "amount": "1000", "category": "Makan"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeTransactionTypeInvalid)
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "0", "category": "Makan"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeAmountNotPositive)
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "-500", "category": "Makan"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidAmount)
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{"type": "expense", "amount": "1000", "category": "   "})
+	expectError(t, res, http.StatusBadRequest, utils.CodeCategoryInvalid)
+
+	res = app.do(http.MethodGet, "/api/transactions?type=transfer", token, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+}
+
+func TestGetTransactionsFilterSearchAndPagination(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("siti", "user", "trial")
+	token := app.token(user)
+
+	now := time.Now()
+	for i := 0; i < 12; i++ {
+		app.createTransaction(user, "expense", int64(1000+i), "Makan", now.Add(-time.Duration(i)*time.Minute))
+	}
+	app.createTransaction(user, "income", 500000, "Gaji", now)
+	app.createTransaction(other, "expense", 9999, "Makan", now)
+
+	res := app.do(http.MethodGet, "/api/transactions?page=2&limit=5", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	meta := res.Body["meta"].(map[string]interface{})
+	if meta["total_data"] != float64(13) || meta["total_pages"] != float64(3) {
+		t.Fatalf("meta = %v, mau 13 data / 3 halaman (transaksi user lain tidak ikut)", meta)
+	}
+	if got := len(res.Body["data"].([]interface{})); got != 5 {
+		t.Fatalf("jumlah data halaman 2 = %d, mau 5", got)
+	}
+
+	res = app.do(http.MethodGet, "/api/transactions?type=income", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["total_data"] != float64(1) {
+		t.Fatalf("filter type: total_data = %v, mau 1", meta["total_data"])
+	}
+
+	// Pencarian tidak peka huruf besar/kecil
+	res = app.do(http.MethodGet, "/api/transactions?search=GAJI", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["total_data"] != float64(1) {
+		t.Fatalf("search: total_data = %v, mau 1", meta["total_data"])
+	}
+}
+
+func TestGetTransactionsCursorPagination(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		// Nominal kembar supaya urutan per ID ikut diuji
+		app.createTransaction(user, "expense", int64(1000*(i/2+1)), "Makan", base.Add(time.Duration(i)*time.Minute))
+	}
+
+	seen := map[float64]bool{}
+	collect := func(res testResponse) string {
+		expectStatus(t, res, http.StatusOK)
+		for _, row := range res.Body["data"].([]interface{}) {
+			id := row.(map[string]interface{})["id"].(float64)
+			if seen[id] {
+				t.Fatalf("transaksi %v muncul dua kali", id)
+			}
+			seen[id] = true
+		}
+		next, _ := res.Body["meta"].(map[string]interface{})["next_cursor"].(string)
+		return next
+	}
+
+	next := collect(app.do(http.MethodGet, "/api/transactions?limit=3", token, nil))
+	// Transaksi baru di tengah pagination tidak menggeser halaman berikutnya
+	app.createTransaction(user, "expense", 500, "Makan", time.Now())
+	res := app.do(http.MethodGet, "/api/transactions?limit=3&cursor="+next, token, nil)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["current_page"] != float64(2) {
+		t.Fatalf("current_page = %v, mau 2", meta["current_page"])
+	}
+	next = collect(res)
+	next = collect(app.do(http.MethodGet, "/api/transactions?limit=3&cursor="+next, token, nil))
+	if next != "" || len(seen) != 7 {
+		t.Fatalf("dapat %d transaksi (next_cursor %q), mau 7 transaksi lama lalu selesai", len(seen), next)
+	}
+
+	// Urut nominal terkecil dulu, lewat cursor juga
+	var amounts []float64
+	next = ""
+	for {
+		res := app.do(http.MethodGet, "/api/transactions?sort=amount&order=asc&limit=3&cursor="+next, token, nil)
+		expectStatus(t, res, http.StatusOK)
+		for _, row := range res.Body["data"].([]interface{}) {
+			amounts = append(amounts, row.(map[string]interface{})["amount"].(float64))
+		}
+		if next, _ = res.Body["meta"].(map[string]interface{})["next_cursor"].(string); next == "" {
+			break
+		}
+	}
+	if len(amounts) != 8 {
+		t.Fatalf("jumlah data urut nominal = %d, mau 8", len(amounts))
+	}
+	for i := 1; i < len(amounts); i++ {
+		if amounts[i] < amounts[i-1] {
+			t.Fatalf("urutan nominal salah: %v", amounts)
+		}
+	}
+
+	// Cursor dari urutan lain ditolak
+	first := app.do(http.MethodGet, "/api/transactions?limit=3", token, nil)
+	cursor := first.Body["meta"].(map[string]interface{})["next_cursor"].(string)
+	res = app.do(http.MethodGet, "/api/transactions?sort=amount&cursor="+cursor, token, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeCursorInvalid)
+	res = app.do(http.MethodGet, "/api/transactions?cursor=bukan-cursor", token, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeCursorInvalid)
+	res = app.do(http.MethodGet, "/api/transactions?sort=note", token, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+}
+
+func TestGetTransactionsFullTextSearchAndCategories(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("siti", "user", "trial")
+	token := app.token(user)
+
+	now := time.Now()
+	padang := app.createTransaction(user, "expense", 25000, "Makan", now)
+	app.createTransaction(user, "expense", 15000, "Makan", now)
+	app.createTransaction(user, "expense", 40000, "Transport", now)
+	app.createTransaction(user, "expense", 90000, "Belanja", now)
+	app.createTransaction(other, "expense", 30000, "Makan", now)
+	// Diubah setelah tersimpan: index full-text ikut diperbarui
+	if err := app.db.Model(padang).Update("note", "Nasi Padang dekat kantor").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	total := func(path string) float64 {
+		t.Helper()
+		res := app.do(http.MethodGet, path, token, nil)
+		expectStatus(t, res, http.StatusOK)
+		return res.Body["meta"].(map[string]interface{})["total_data"].(float64)
+	}
+
+	// Awalan kata, tidak peka huruf besar/kecil, semua kata harus ada
+	if got := total("/api/transactions?search=pada"); got != 1 {
+		t.Fatalf("search awalan: %v, mau 1", got)
+	}
+	if got := total("/api/transactions?search=NASI%20kantor"); got != 1 {
+		t.Fatalf("search dua kata: %v, mau 1", got)
+	}
+	if got := total("/api/transactions?search=nasi%20bandung"); got != 0 {
+		t.Fatalf("search kata tidak ada: %v, mau 0", got)
+	}
+	// Karakter khusus sintaks full-text diabaikan (bukan error)
+	if got := total(`/api/transactions?search=%22padang%22*%20OR`); got != 0 {
+		t.Fatalf("search karakter khusus: %v, mau 0", got)
+	}
+	if got := total("/api/transactions?category=makan,Transport"); got != 3 {
+		t.Fatalf("multi kategori: %v, mau 3", got)
+	}
+	if got := total("/api/transactions?category=Makan&category=Belanja&min_amount=20000"); got != 2 {
+		t.Fatalf("multi kategori + nominal: %v, mau 2", got)
+	}
+}
+
+func TestSummaryAndCategories(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	now := time.Now()
+	app.createTransaction(user, "income", 100000, "Gaji", now)
+	app.createTransaction(user, "expense", 15000, "Makan", now)
+	app.createTransaction(user, "expense", 5000, "Makan", now)
+	app.createTransaction(user, "expense", 20000, "Transport", now)
+
+	res := app.do(http.MethodGet, "/api/summary", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["total_income"] != float64(100000) || res.Body["total_expense"] != float64(40000) || res.Body["balance"] != float64(60000) {
+		t.Fatalf("summary = %v", res.Body)
+	}
+
+	res = app.do(http.MethodGet, "/api/categories", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	totals := map[string]float64{}
+	for _, row := range res.Body["data"].([]interface{}) {
+		r := row.(map[string]interface{})
+		totals[fmt.Sprintf("%s-%s", r["type"], r["category"])] = r["total"].(float64)
+	}
+	if totals["expense-Makan"] != 20000 || totals["expense-Transport"] != 20000 || totals["income-Gaji"] != 100000 {
+		t.Fatalf("categories = %v", totals)
+	}
+}
+
+func TestPeriodFilterAcrossReports(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	feb := func(d int) time.Time { return time.Date(2025, time.February, d, 12, 0, 0, 0, time.Local) }
+	app.createTransaction(user, "income", 100000, "Gaji", feb(1))
+	app.createTransaction(user, "expense", 15000, "Makan", feb(10))
+	app.createTransaction(user, "expense", 40000, "Transport", feb(20))
+	app.createTransaction(user, "expense", 99000, "Makan", time.Date(2025, time.March, 1, 8, 0, 0, 0, time.Local))
+
+	res := app.do(http.MethodGet, "/api/summary?from=2025-02-01&to=2025-02-28", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["total_income"] != float64(100000) || res.Body["total_expense"] != float64(55000) {
+		t.Fatalf("summary Februari = %v", res.Body)
+	}
+	period := res.Body["period"].(map[string]interface{})
+	if period["from"] != "2025-02-01" || period["to"] != "2025-02-28" {
+		t.Fatalf("period = %v", period)
+	}
+
+	res = app.do(http.MethodGet, "/api/categories?month=3&year=2025", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if data := res.Body["data"].([]interface{}); len(data) != 1 || data[0].(map[string]interface{})["total"] != float64(99000) {
+		t.Fatalf("categories Maret = %v", data)
+	}
+
+	// Awal bulan tanggal 10: "Februari" = 10 Feb s/d 9 Mar
+	res = app.do(http.MethodPut, "/api/user/settings", token, map[string]interface{}{"month_start_day": 10})
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodGet, "/api/summary?month=2&year=2025", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["total_income"] != float64(0) || res.Body["total_expense"] != float64(154000) {
+		t.Fatalf("summary bulan custom = %v", res.Body)
+	}
+
+	res = app.do(http.MethodGet, "/api/chart/daily?from=2025-02-10&to=2025-02-20", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if data := res.Body["data"].([]interface{}); len(data) != 11 {
+		t.Fatalf("jumlah hari grafik = %d, mau 11", len(data))
+	}
+
+	res = app.do(http.MethodGet, "/api/transactions?from=2025-02-05&to=2025-02-25&category=makan", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["total_data"] != float64(1) {
+		t.Fatalf("daftar per periode + kategori: total_data = %v, mau 1", meta["total_data"])
+	}
+
+	res = app.do(http.MethodGet, "/api/transactions?min_amount=20.000&max_amount=100.000", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["total_data"] != float64(3) {
+		t.Fatalf("filter nominal: total_data = %v, mau 3", meta["total_data"])
+	}
+}
+
+func TestPeriodFilterValidation(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	cases := []struct {
+		path, code string
+	}{
+		{"/api/summary?period=minggu_lalu", utils.CodePeriodInvalid},
+		{"/api/categories?from=2025-13-01", utils.CodeDateInvalid},
+		{"/api/transactions?from=2025-03-01&to=2025-02-01", utils.CodeDateRangeInvalid},
+		{"/api/transactions?period=today&month=1&year=2025", utils.CodePeriodConflict},
+		{"/api/export?month=abc&year=2025", utils.CodeInvalidInput},
+		{"/api/export?month=1&year=0", utils.CodeInvalidInput},
+		{"/api/chart/daily?period=all", utils.CodePeriodTooLong},
+		{"/api/chart/daily?from=2023-01-01&to=2025-01-01", utils.CodePeriodTooLong},
+		{"/api/analytics/trends?interval=day", utils.CodeInvalidInput},
+		{"/api/analytics/trends?period=all", utils.CodePeriodTooLong},
+		{"/api/analytics/trends?interval=week&from=2015-01-01&to=2025-01-01", utils.CodePeriodTooLong},
+		{"/api/summary?period=this_month&month_start_day=30", utils.CodeInvalidInput},
+		{"/api/transactions?min_amount=abc", utils.CodeInvalidInput},
+		{"/api/transactions?min_amount=500&max_amount=100", utils.CodeInvalidInput},
+	}
+	for _, tc := range cases {
+		res := app.do(http.MethodGet, tc.path, token, nil)
+		if res.Status != http.StatusBadRequest || res.Code() != tc.code {
+			t.Errorf("%s: status %d code %q, mau 400 %q", tc.path, res.Status, res.Code(), tc.code)
+		}
+	}
+}
+
+func TestDailyChartValidatesMonth(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	res := app.do(http.MethodGet, "/api/chart/daily?month=13&year=2025", token, nil)
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+}
+
+func TestDailyChartZeroFilledAndSorted(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	// Batas hari mengikuti zona waktu user (default Asia/Jakarta), bukan zona server
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	day := func(d, hour int) time.Time { return time.Date(2025, time.February, d, hour, 30, 0, 0, jakarta) }
+	app.createTransaction(user, "income", 100000, "Gaji", day(1, 0))
+	app.createTransaction(user, "expense", 15000, "Makan", day(14, 12))
+	app.createTransaction(user, "expense", 5000, "Makan", day(14, 23))
+	app.createTransaction(user, "expense", 7000, "Makan", day(28, 23))
+	// Di luar bulan: tidak ikut
+	app.createTransaction(user, "expense", 99000, "Makan", time.Date(2025, time.March, 1, 0, 10, 0, 0, jakarta))
+
+	res := app.do(http.MethodGet, "/api/chart/daily?month=2&year=2025", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	data := res.Body["data"].([]interface{})
+	if len(data) != 28 {
+		t.Fatalf("jumlah hari = %d, mau 28 (hari kosong tetap ada)", len(data))
+	}
+	for i, row := range data {
+		r := row.(map[string]interface{})
+		if want := fmt.Sprintf("2025-02-%02d", i+1); r["date"] != want {
+			t.Fatalf("data[%d].date = %v, mau %s (urut tanggal)", i, r["date"], want)
+		}
+	}
+	check := func(i int, income, expense float64) {
+		r := data[i].(map[string]interface{})
+		if r["income"] != income || r["expense"] != expense {
+			t.Errorf("%v: income %v expense %v, mau %v / %v", r["date"], r["income"], r["expense"], income, expense)
+		}
+	}
+	check(0, 100000, 0)
+	check(1, 0, 0)
+	check(13, 0, 20000)
+	check(27, 0, 7000)
+}
+
+func TestDailyChartUsesUserTimezone(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	// Disimpan dalam UTC; user default Asia/Jakarta (UTC+7)
+	app.createTransaction(user, "expense", 1000, "Makan", time.Date(2025, time.January, 31, 18, 0, 0, 0, time.UTC)) // 1 Feb 01:00 WIB
+	app.createTransaction(user, "expense", 2000, "Makan", time.Date(2025, time.February, 14, 20, 0, 0, 0, time.UTC)) // 15 Feb 03:00 WIB
+	app.createTransaction(user, "expense", 4000, "Makan", time.Date(2025, time.February, 28, 18, 0, 0, 0, time.UTC)) // 1 Mar 01:00 WIB
+
+	expense := func(path string) []float64 {
+		t.Helper()
+		res := app.do(http.MethodGet, path, token, nil)
+		expectStatus(t, res, http.StatusOK)
+		var out []float64
+		for _, row := range res.Body["data"].([]interface{}) {
+			out = append(out, row.(map[string]interface{})["expense"].(float64))
+		}
+		return out
+	}
+
+	days := expense("/api/chart/daily?month=2&year=2025")
+	if len(days) != 28 || days[0] != 1000 || days[13] != 0 || days[14] != 2000 || days[27] != 0 {
+		t.Fatalf("grafik WIB = %v", days)
+	}
+	res := app.do(http.MethodGet, "/api/summary?month=2&year=2025", token, nil)
+	if res.Body["total_expense"] != float64(3000) {
+		t.Fatalf("summary Februari WIB = %v, mau 3000", res.Body["total_expense"])
+	}
+
+	res = app.do(http.MethodPut, "/api/user/settings", token, map[string]interface{}{"timezone": "Mars/Olympus"})
+	expectError(t, res, http.StatusBadRequest, utils.CodeInvalidInput)
+	res = app.do(http.MethodPut, "/api/user/settings", token, map[string]interface{}{"timezone": "UTC"})
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodGet, "/api/user/settings", token, nil)
+	if res.Body["timezone"] != "UTC" {
+		t.Fatalf("timezone = %v, mau UTC", res.Body["timezone"])
+	}
+
+	days = expense("/api/chart/daily?month=2&year=2025")
+	if days[0] != 0 || days[13] != 2000 || days[27] != 4000 {
+		t.Fatalf("grafik UTC = %v", days)
+	}
+}
+
+func TestTrendsAnalytics(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 10, 0, 0, 0, jakarta) }
+	app.createTransaction(user, "expense", 100000, "Makan", day(2024, 3, 15))
+	app.createTransaction(user, "income", 1000000, "Gaji", day(2025, 1, 10))
+	app.createTransaction(user, "expense", 200000, "Makan", day(2025, 1, 10))
+	app.createTransaction(user, "income", 1000000, "Gaji", day(2025, 2, 5))
+	app.createTransaction(user, "expense", 300000, "Makan", day(2025, 2, 5))
+	app.createTransaction(user, "expense", 150000, "Makan", day(2025, 3, 3))
+	app.createTransaction(user, "expense", 50000, "Transport", day(2025, 3, 31))
+
+	res := app.do(http.MethodGet, "/api/analytics/trends?from=2025-01-01&to=2025-03-31", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	data := res.Body["data"].(map[string]interface{})
+
+	series := data["series"].([]interface{})
+	if len(series) != 3 {
+		t.Fatalf("jumlah titik bulanan = %d, mau 3", len(series))
+	}
+	wantNet := []float64{800000, 700000, -200000}
+	for i, point := range series {
+		p := point.(map[string]interface{})
+		if p["net"] != wantNet[i] {
+			t.Errorf("series[%d] (%v) net = %v, mau %v", i, p["start"], p["net"], wantNet[i])
+		}
+	}
+	if p := series[1].(map[string]interface{}); p["start"] != "2025-02-01" || p["end"] != "2025-02-28" {
+		t.Errorf("titik Februari = %v..%v", p["start"], p["end"])
+	}
+	if data["total_expense"] != float64(700000) || data["days"] != float64(90) || data["average_daily_expense"] != float64(7778) {
+		t.Errorf("total %v hari %v rata-rata %v, mau 700000 / 90 / 7778", data["total_expense"], data["days"], data["average_daily_expense"])
+	}
+	if data["savings_rate"] != float64(65) {
+		t.Errorf("savings_rate = %v, mau 65", data["savings_rate"])
+	}
+
+	// Kategori: Maret 2025 vs Februari 2025 & Maret 2024
+	if data["comparison_month"] != "2025-03-01" {
+		t.Fatalf("comparison_month = %v, mau 2025-03-01", data["comparison_month"])
+	}
+	categories := map[string]map[string]interface{}{}
+	for _, row := range data["categories"].([]interface{}) {
+		r := row.(map[string]interface{})
+		categories[r["type"].(string)+"/"+r["category"].(string)] = r
+	}
+	makan := categories["expense/Makan"]
+	if makan["current"] != float64(150000) || makan["mom_delta"] != float64(-150000) || makan["mom_percent"] != float64(-50) ||
+		makan["previous_year"] != float64(100000) || makan["yoy_percent"] != float64(50) {
+		t.Errorf("Makan = %v", makan)
+	}
+	if transport := categories["expense/Transport"]; transport["mom_percent"] != nil || transport["current"] != float64(50000) {
+		t.Errorf("Transport = %v (persen null kalau bulan lalu 0)", transport)
+	}
+	if gaji := categories["income/Gaji"]; gaji["current"] != float64(0) || gaji["previous_month"] != float64(1000000) {
+		t.Errorf("Gaji = %v", gaji)
+	}
+
+	// Mingguan: titik pertama terpotong dari Rabu 1 Januari, batas berikutnya hari Senin
+	res = app.do(http.MethodGet, "/api/analytics/trends?interval=week&from=2025-01-01&to=2025-01-12", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	series = res.Body["data"].(map[string]interface{})["series"].([]interface{})
+	if len(series) != 2 {
+		t.Fatalf("jumlah titik mingguan = %d, mau 2", len(series))
+	}
+	first, second := series[0].(map[string]interface{}), series[1].(map[string]interface{})
+	if first["start"] != "2025-01-01" || first["end"] != "2025-01-05" || second["start"] != "2025-01-06" || second["expense"] != float64(200000) {
+		t.Errorf("titik mingguan = %v / %v", first, second)
+	}
+
+	// Tahunan tanpa periode: 5 tahun terakhir
+	res = app.do(http.MethodGet, "/api/analytics/trends?interval=year", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if series = res.Body["data"].(map[string]interface{})["series"].([]interface{}); len(series) != 5 {
+		t.Fatalf("jumlah titik tahunan default = %d, mau 5", len(series))
+	}
+}
+
+func TestDeleteAndRestoreTransaction(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("siti", "user", "trial")
+	token := app.token(user)
+
+	trx := app.createTransaction(user, "expense", 15000, "Makan", time.Now())
+	path := fmt.Sprintf("/api/transactions/%d", trx.ID)
+
+	// Transaksi milik orang lain tidak bisa dihapus
+	res := app.do(http.MethodDelete, path, app.token(other), nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeTransactionNotFound)
+
+	res = app.do(http.MethodDelete, path, token, nil)
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodGet, "/api/transactions", token, nil)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["total_data"] != float64(0) {
+		t.Fatalf("transaksi terhapus masih muncul: %v", meta)
+	}
+
+	res = app.do(http.MethodGet, "/api/transactions/trash", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if got := len(res.Body["data"].([]interface{})); got != 1 {
+		t.Fatalf("isi tong sampah = %d, mau 1", got)
+	}
+
+	res = app.do(http.MethodPost, path+"/restore", app.token(other), nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeTransactionNotFound)
+
+	res = app.do(http.MethodPost, path+"/restore", token, nil)
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodGet, "/api/transactions", token, nil)
+	if meta := res.Body["meta"].(map[string]interface{}); meta["total_data"] != float64(1) {
+		t.Fatalf("transaksi belum kembali: %v", meta)
+	}
+
+	// Sudah tidak di tong sampah lagi
+	res = app.do(http.MethodPost, path+"/restore", token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeTransactionNotFound)
+}
+
+func TestRestoreTransactionAfterRetentionFails(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+
+	trx := app.createTransaction(user, "expense", 15000, "Makan", time.Now())
+	expired := time.Now().AddDate(0, 0, -31)
+	if err := app.db.Model(&models.Transaction{}).Where("id = ?", trx.ID).Update("deleted_at", expired).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	res := app.do(http.MethodPost, fmt.Sprintf("/api/transactions/%d/restore", trx.ID), token, nil)
+	expectError(t, res, http.StatusNotFound, utils.CodeTransactionNotFound)
+}
+
+// failingTransactions: fake repository yang selalu gagal saat membaca daftar transaksi
+type failingTransactions struct {
+	repository.TransactionRepository
+}
+
+func (failingTransactions) List(repository.TransactionFilter) ([]models.Transaction, int64, error) {
+	return nil, 0, errors.New("database down")
+}
+
+func TestGetTransactionsRepositoryErrorIsInternal(t *testing.T) {
+	base := newTestApp(t)
+	repos := *base.repos
+	repos.Transactions = failingTransactions{repos.Transactions}
+	app := newTestAppWith(t, base.db, &repos)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	res := app.do(http.MethodGet, "/api/transactions", token, nil)
+	expectError(t, res, http.StatusInternalServerError, utils.CodeInternal)
+	if res.Body["error"] == "database down" {
+		t.Fatal("detail error internal tidak boleh bocor ke client")
+	}
+}
+
+func TestCreateTransactionInForeignCurrency(t *testing.T) {
+	app := newTestApp(t)
+	token := app.token(app.createUser("budi", "user", "trial"))
+
+	// USD punya 2 desimal: disimpan dalam sen
+	res := app.do(http.MethodPost, "/api/transactions", token, map[string]string{
+		"type": "expense", "amount": "12.50", "currency": "usd", "category": "Makan",
+	})
+	expectStatus(t, res, http.StatusOK)
+	data := res.Body["data"].(map[string]interface{})
+	if data["amount"] != float64(1250) || data["currency"] != "USD" {
+		t.Fatalf("data = %v, mau 1250 sen USD", data)
+	}
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]string{
+		"type": "expense", "amount": "10", "currency": "XYZ", "category": "Makan",
+	})
+	expectError(t, res, http.StatusBadRequest, utils.CodeCurrencyInvalid)
+}
+
+func TestCreateTransactionInWallet(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	other := app.createUser("siti", "user", "trial")
+	token := app.token(user)
+
+	res := app.do(http.MethodPost, "/api/wallets", token, map[string]string{"name": "Tabungan Dolar", "currency": "USD"})
+	expectStatus(t, res, http.StatusOK)
+	walletID := res.Body["data"].(map[string]interface{})["id"].(float64)
+
+	// Mata uang ikut dompet
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]interface{}{
+		"type": "income", "amount": "100", "category": "Gaji", "wallet_id": walletID,
+	})
+	expectStatus(t, res, http.StatusOK)
+	if data := res.Body["data"].(map[string]interface{}); data["currency"] != "USD" || data["amount"] != float64(10000) {
+		t.Fatalf("data = %v, mau 10000 sen USD", data)
+	}
+
+	res = app.do(http.MethodPost, "/api/transactions", token, map[string]interface{}{
+		"type": "income", "amount": "100", "currency": "IDR", "category": "Gaji", "wallet_id": walletID,
+	})
+	expectError(t, res, http.StatusBadRequest, utils.CodeCurrencyMismatch)
+
+	// Dompet orang lain
+	res = app.do(http.MethodPost, "/api/transactions", app.token(other), map[string]interface{}{
+		"type": "income", "amount": "100", "category": "Gaji", "wallet_id": walletID,
+	})
+	expectError(t, res, http.StatusNotFound, utils.CodeWalletNotFound)
+
+	// Dompet yang sudah dipakai tidak bisa dihapus
+	res = app.do(http.MethodDelete, fmt.Sprintf("/api/wallets/%d", int(walletID)), token, nil)
+	expectError(t, res, http.StatusConflict, utils.CodeWalletInUse)
+}
+
+func TestSummaryConvertsToBaseCurrency(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	token := app.token(user)
+	admin := app.token(app.createUser("owner", "admin", "active"))
+
+	now := time.Now()
+	app.createTransaction(user, "income", 1000000, "Gaji", now)
+	usd := &models.Transaction{UserID: user.ID, Type: "expense", Amount: 1250, Currency: "USD", Category: "Makan", CreatedAt: now}
+	if err := app.repos.Transactions.Create(usd); err != nil {
+		t.Fatal(err)
+	}
+
+	// Belum ada kurs: jangan diam-diam dihitung 1:1
+	res := app.do(http.MethodGet, "/api/summary", token, nil)
+	expectError(t, res, http.StatusUnprocessableEntity, utils.CodeRateMissing)
+
+	res = app.do(http.MethodPost, "/api/admin/exchange-rates", admin, map[string]string{
+		"base_currency": "USD", "quote_currency": "IDR", "rate": "16000", "effective_date": now.AddDate(0, 0, -1).Format("2006-01-02"),
+	})
+	expectStatus(t, res, http.StatusOK)
+
+	res = app.do(http.MethodGet, "/api/summary", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["total_expense"] != float64(200000) || res.Body["balance"] != float64(800000) || res.Body["currency"] != "IDR" {
+		t.Fatalf("summary = %v, mau 12.50 USD = Rp 200000", res.Body)
+	}
+
+	// Ganti mata uang dasar: kurs kebalikan dipakai otomatis
+	res = app.do(http.MethodPut, "/api/user/settings", token, map[string]interface{}{"base_currency": "USD"})
+	expectStatus(t, res, http.StatusOK)
+	res = app.do(http.MethodGet, "/api/summary", token, nil)
+	expectStatus(t, res, http.StatusOK)
+	if res.Body["total_income"] != float64(6250) || res.Body["total_expense"] != float64(1250) || res.Body["currency"] != "USD" {
+		t.Fatalf("summary USD = %v, mau 6250 / 1250 sen", res.Body)
+	}
+}
+
+func TestSummaryConversionOverflow(t *testing.T) {
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	admin := app.token(app.createUser("owner", "admin", "active"))
+
+	now := time.Now()
+	huge := &models.Transaction{UserID: user.ID, Type: "expense", Amount: 100000000000000000, Currency: "USD", Category: "Makan", CreatedAt: now}
+	if err := app.repos.Transactions.Create(huge); err != nil {
+		t.Fatal(err)
+	}
+	res := app.do(http.MethodPost, "/api/admin/exchange-rates", admin, map[string]string{
+		"base_currency": "USD", "quote_currency": "IDR", "rate": "16000", "effective_date": now.AddDate(0, 0, -1).Format("2006-01-02"),
+	})
+	expectStatus(t, res, http.StatusOK)
+
+	// 10^15 USD x 16000 tidak muat di int64: ditolak, bukan angka terpotong
+	res = app.do(http.MethodGet, "/api/summary", app.token(user), nil)
+	expectError(t, res, http.StatusUnprocessableEntity, utils.CodeAmountTooLarge)
+}
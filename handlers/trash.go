@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Helper: Tanggal data akan dihapus permanen oleh job purge
+func purgeAt(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	t := deletedAt.Time.AddDate(0, 0, utils.TrashRetentionDays())
+	return &t
+}
+
+// GET /api/transactions/trash — Transaksi yang dihapus dan masih bisa dikembalikan
+func (h *Handler) GetTransactionTrash(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+
+	transactions, err := h.trxService.Trash(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	data := make([]gin.H, 0, len(transactions))
+	for _, trx := range transactions {
+		data = append(data, gin.H{
+			"transaction": trx,
+			"purge_at":    purgeAt(trx.DeletedAt),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":           data,
+		"retention_days": utils.TrashRetentionDays(),
+	})
+}
+
+// POST /api/transactions/:id/restore
+func (h *Handler) RestoreTransaction(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrTransactionNotFound)
+		return
+	}
+
+	restored, err := h.trxService.Restore(userID, uint(id))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	if !restored {
+		utils.RespondError(c, utils.ErrTransactionNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.transaction_restored")})
+}
+
+// GET /api/admin/users/trash — User yang dihapus admin dan masih bisa dikembalikan
+func (h *Handler) GetUserTrash(c *gin.Context) {
+	if !isAdmin(c) {
+		utils.RespondError(c, utils.ErrForbidden)
+		return
+	}
+
+	users, err := h.userService.Trash()
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	data := make([]gin.H, 0, len(users))
+	for _, u := range users {
+		data = append(data, gin.H{
+			"user":     u,
+			"purge_at": purgeAt(u.DeletedAt),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":           data,
+		"retention_days": utils.TrashRetentionDays(),
+	})
+}
+
+// POST /api/admin/users/:id/restore — Kembalikan user beserta transaksi yang ikut terhapus bersamanya
+func (h *Handler) RestoreUser(c *gin.Context) {
+	if !isAdmin(c) {
+		utils.RespondError(c, utils.ErrForbidden)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound)
+		return
+	}
+
+	// Transaksi yang dihapus sendiri oleh user sebelumnya tetap di tong sampah,
+	// hanya yang waktu hapusnya sama dengan akun yang dikembalikan
+	user, trxCount, err := h.userService.Restore(uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	after := auditUserSnapshot(*user)
+	after["transactions"] = trxCount
+	h.recordAudit(c, AuditAdminUserRestore, "user", user.ID, nil, after)
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.user_restored")})
+}
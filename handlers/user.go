@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"backend-gin/database"
-	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
 	"net/http"
+	"time"
 "golang.org/x/crypto/bcrypt"
 	"github.com/gin-gonic/gin"
 )
@@ -18,12 +19,11 @@ func getUserIDFromContext(c *gin.Context) uint {
 }
 
 // GET Settings (Untuk ditampilkan di form frontend nanti)
-func GetUserSettings(c *gin.Context) {
+func (h *Handler) GetUserSettings(c *gin.Context) {
 	userID := getUserIDFromContext(c)
-	var user models.User
-	
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
 		return
 	}
 
@@ -35,36 +35,87 @@ func GetUserSettings(c *gin.Context) {
         "telegram_id":   user.TelegramID,   // <--- PENTING: Tambahkan ini!
         "daily_limit":   user.DailyLimit,
         "alert_message": user.AlertMessage,
+		"base_currency": user.BaseCurrency,
+		"month_start_day": monthStartDay(user),
+		"timezone":      utils.UserLocation(user.Timezone).String(),
+		"language":      utils.UserLanguage(user.Language),
+		"monthly_report": user.MonthlyReport,
+		"delete_after":  user.DeleteAfter,
 	})
 }
 
 // UPDATE Settings (Simpan Limit & Pesan)
-func UpdateUserSettings(c *gin.Context) {
+func (h *Handler) UpdateUserSettings(c *gin.Context) {
 	userID := getUserIDFromContext(c)
-	var user models.User
-
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
 		return
 	}
 
+	before := auditUserSnapshot(*user)
+
 	var input struct {
-		DailyLimit   int    `json:"daily_limit"`
-		AlertMessage string `json:"alert_message"`
+		DailyLimit   int64   `json:"daily_limit"` // Minor unit mata uang dasar
+		AlertMessage string  `json:"alert_message"`
+		Language     *string `json:"language"`      // Opsional: bahasa balasan bot ('id' / 'en')
+		BaseCurrency *string `json:"base_currency"` // Opsional: mata uang ringkasan & laporan (ISO 4217)
+		MonthStartDay *int   `json:"month_start_day"` // Opsional: tanggal awal bulan laporan (1-28)
+		Timezone     *string `json:"timezone"`        // Opsional: zona waktu IANA, mis. "Asia/Makassar"
+		MonthlyReport *bool  `json:"monthly_report"`  // Opsional: kirim laporan Excel bulan lalu ke Telegram tiap awal bulan
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data invalid"})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
 	// Update Data
 	user.DailyLimit = input.DailyLimit
 	user.AlertMessage = input.AlertMessage
+	if input.Language != nil {
+		lang := utils.NormalizeLanguage(*input.Language)
+		if lang == "" {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("language", utils.CodeFieldInvalid))
+			return
+		}
+		user.Language = lang
+	}
+	if input.BaseCurrency != nil {
+		currency := money.Normalize(*input.BaseCurrency)
+		if currency == "" {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("base_currency", utils.CodeCurrencyInvalid))
+			return
+		}
+		user.BaseCurrency = currency
+	}
+	if input.MonthStartDay != nil {
+		if *input.MonthStartDay < 1 || *input.MonthStartDay > utils.MaxMonthStartDay {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("month_start_day", utils.CodeMonthStartDayInvalid))
+			return
+		}
+		user.MonthStartDay = *input.MonthStartDay
+	}
+	if input.Timezone != nil {
+		timezone := utils.NormalizeTimezone(*input.Timezone)
+		if timezone == "" {
+			utils.RespondError(c, utils.ErrInvalidInput.WithField("timezone", utils.CodeTimezoneInvalid))
+			return
+		}
+		user.Timezone = timezone
+	}
+	if input.MonthlyReport != nil {
+		// Dicek setelah zona waktu & awal bulan supaya periode pertama ikut pengaturan baru
+		setMonthlyReport(user, *input.MonthlyReport, time.Now())
+	}
 
-	database.DB.Save(&user)
+	if err := h.users.Save(user); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	h.recordAudit(c, AuditSettingsUpdate, "user", user.ID, before, auditUserSnapshot(*user))
 
-	c.JSON(http.StatusOK, gin.H{"message": "Pengaturan budget berhasil disimpan!"})
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.settings_saved")})
 }
 
 
@@ -73,36 +124,73 @@ func UpdateUserSettings(c *gin.Context) {
 
 // Struct Input Khusus Update Profil
 type UpdateProfileInput struct {
-	Username   string `json:"username"`
-	Password   string `json:"password"`
-	TelegramID *int64 `json:"telegram_id"`
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	CurrentPassword string `json:"current_password"` // Wajib kalau ganti password / Telegram ID / username
+	TelegramID      *int64 `json:"telegram_id"`
 }
 
 // ENDPOINT: PUT /api/user/profile
-func UpdateUserProfile(c *gin.Context) {
+func (h *Handler) UpdateUserProfile(c *gin.Context) {
 	userID := getUserIDFromContext(c)
-	var user models.User
-
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, err := h.users.FindByID(userID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrUserNotFound.Wrap(err))
 		return
 	}
 
+	before := auditUserSnapshot(*user)
+
 	var input UpdateProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak valid"})
+		utils.RespondError(c, utils.BindError(err))
 		return
 	}
 
+	// Ganti username tanpa ganti password harus dicek terhadap password lama (plaintext dari current_password)
+	input.Username = utils.NormalizeUsername(input.Username)
+	usernameOnly := input.Username != "" && input.Username != user.Username && input.Password == ""
+
+	// Ganti password, Telegram ID (jalur reset password) atau username saja wajib konfirmasi password lama
+	telegramChanged := input.TelegramID != nil && (user.TelegramID == nil || *user.TelegramID != *input.TelegramID)
+	if input.Password != "" || telegramChanged || usernameOnly {
+		if verr := utils.ValidateCurrentPassword(user.Password, input.CurrentPassword); verr != nil {
+			utils.RespondError(c, verr)
+			return
+		}
+	}
+
 	// 1. Update Username (Cek duplikat otomatis handled by Gorm Unique Index)
 	if input.Username != "" {
+		if verr := utils.ValidateUsername(input.Username); verr != nil {
+			utils.RespondError(c, verr)
+			return
+		}
+		if usernameOnly {
+			if verr := utils.ValidateUsernameForPassword(input.Username, input.CurrentPassword); verr != nil {
+				utils.RespondError(c, verr)
+				return
+			}
+		}
 		user.Username = input.Username
 	}
 
-	// 2. Update Password (Hash dulu)
+	// 2. Update Password (Validasi & Hash dulu)
+	passwordChanged := false
 	if input.Password != "" {
-		hashed, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		if verr := utils.ValidatePassword(input.Password, user.Username); verr != nil {
+			utils.RespondError(c, verr)
+			return
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		if err != nil {
+			utils.RespondError(c, utils.ErrInternal.Wrap(err))
+			return
+		}
 		user.Password = string(hashed)
+		// Sesi di perangkat lain ikut logout, perangkat ini dapat token baru di bawah
+		user.SessionVersion++
+		passwordChanged = true
 	}
 
 	// 3. Update Telegram ID (Bisa diset angka atau null/0)
@@ -111,19 +199,30 @@ func UpdateUserProfile(c *gin.Context) {
 		user.TelegramID = input.TelegramID
 	}
 
-	if err := database.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Gagal update. Username/TelegramID mungkin sudah dipakai orang lain."})
+	if err := h.users.Save(user); err != nil {
+		utils.RespondError(c, utils.ErrProfileConflict.Wrap(err))
 		return
 	}
+	h.recordAudit(c, AuditProfileUpdate, "user", user.ID, before, auditUserSnapshot(*user))
+	if passwordChanged {
+		h.recordAudit(c, AuditPasswordChange, "user", user.ID, nil, nil)
+	}
 
 	// Kembalikan data user terbaru agar frontend bisa update localStorage
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Profil berhasil diperbarui!",
+	response := gin.H{
+		"message": utils.T(utils.Lang(c), "msg.profile_updated"),
 		"user": gin.H{
 			"username":      user.Username,
 			"status":        user.Status,
 			"telegram_id":   user.TelegramID,
 			"trial_ends_at": user.TrialEndsAt,
 		},
-	})
+	}
+	// Token lama sudah tidak berlaku setelah ganti password, kirim yang baru
+	if passwordChanged {
+		if token, err := utils.GenerateToken(user.ID, user.Role, user.SessionVersion); err == nil {
+			response["token"] = token
+		}
+	}
+	c.JSON(http.StatusOK, response)
 }
\ No newline at end of file
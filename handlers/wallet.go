@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/wallets
+func (h *Handler) GetWallets(c *gin.Context) {
+	wallets, err := h.wallets.ListForUser(getUserID(c))
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": wallets})
+}
+
+// POST /api/wallets
+func (h *Handler) CreateWallet(c *gin.Context) {
+	userID := getUserID(c)
+
+	var input struct {
+		Name     string `json:"name" binding:"required,max=100"`
+		Currency string `json:"currency"` // Opsional, default mata uang dasar user
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondError(c, utils.BindError(err))
+		return
+	}
+
+	currency, err := h.trxService.ResolveCurrency(userID, nil, input.Currency)
+	if err != nil {
+		utils.RespondError(c, serviceError(err))
+		return
+	}
+
+	wallet := models.Wallet{
+		UserID:    userID,
+		Name:      strings.TrimSpace(input.Name),
+		Currency:  currency,
+		CreatedAt: time.Now(),
+	}
+	if err := h.wallets.Create(&wallet); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.wallet_created"), "data": wallet})
+}
+
+// DELETE /api/wallets/:id (hanya kalau belum dipakai transaksi maupun target tabungan)
+func (h *Handler) DeleteWallet(c *gin.Context) {
+	userID := getUserID(c)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.RespondError(c, utils.ErrWalletNotFound)
+		return
+	}
+	wallet, err := h.wallets.FindForUser(userID, uint(id))
+	if err != nil {
+		utils.RespondError(c, utils.ErrWalletNotFound.Wrap(err))
+		return
+	}
+
+	// Transaksi di tong sampah ikut dihitung, supaya restore tidak menunjuk dompet yang hilang
+	used, err := h.transactions.CountInWallet(wallet.ID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	if used > 0 {
+		utils.RespondError(c, utils.ErrWalletInUse.WithArgs(used))
+		return
+	}
+	goals, err := h.goals.CountInWallet(wallet.ID)
+	if err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+	if goals > 0 {
+		utils.RespondError(c, utils.ErrWalletHasGoals.WithArgs(goals))
+		return
+	}
+
+	if err := h.wallets.Delete(wallet); err != nil {
+		utils.RespondError(c, utils.ErrInternal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": utils.T(utils.Lang(c), "msg.wallet_deleted")})
+}
+
+// GET /api/currencies: daftar mata uang yang didukung (untuk pilihan di form)
+func (h *Handler) GetCurrencies(c *gin.Context) {
+	type currency struct {
+		Code     string `json:"code"`
+		Exponent int    `json:"exponent"`
+		Symbol   string `json:"symbol"`
+	}
+	var result []currency
+	for _, code := range money.Codes() {
+		cur, _ := money.Lookup(code)
+		result = append(result, currency{Code: cur.Code, Exponent: cur.Exponent, Symbol: cur.Symbol})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
-	"backend-gin/database"
 	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/services"
+	"backend-gin/utils"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"strconv"
@@ -34,21 +39,25 @@ type TelegramResponse struct {
 }
 
 type EditMessageResponse struct {
-	ChatID    int64  `json:"chat_id"`
-	MessageID int    `json:"message_id"`
-	Text      string `json:"text"`
-	ParseMode string `json:"parse_mode"`
+	ChatID      int64                 `json:"chat_id"`
+	MessageID   int                   `json:"message_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
 // -------------------------------------------
 
-func TelegramWebhook(c *gin.Context) {
+func (h *Handler) TelegramWebhook(c *gin.Context) {
 	var payload struct {
 		Message *struct {
 			Text string `json:"text"`
 			Chat struct {
 				ID int64 `json:"id"`
 			} `json:"chat"`
+			From struct {
+				LanguageCode string `json:"language_code"`
+			} `json:"from"`
 		} `json:"message"`
 		CallbackQuery *struct {
 			ID      string `json:"id"`
@@ -77,54 +86,93 @@ func TelegramWebhook(c *gin.Context) {
 		data := payload.CallbackQuery.Data
 		clickerID := payload.CallbackQuery.From.ID
 
-		var user models.User
-		if err := database.DB.Where("telegram_id = ?", clickerID).First(&user).Error; err != nil {
-			return 
+		user, err := h.users.FindByTelegramID(clickerID)
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+			return
 		}
+		lang := utils.UserLanguage(user.Language)
 
 		if strings.HasPrefix(data, "del_yes_") {
 			idStr := strings.TrimPrefix(data, "del_yes_")
 			id, _ := strconv.Atoi(idStr)
-			res := database.DB.Where("id = ? AND user_id = ?", id, user.ID).Delete(&models.Transaction{})
+			deleted, err := h.transactions.Delete(user.ID, uint(id))
+			if err != nil {
+				log.Printf("[BOT] Gagal hapus transaksi %d: %v", id, err)
+			}
 			
-			if res.RowsAffected > 0 {
-				editMessage(chatID, messageID, fmt.Sprintf("✅ *Sukses!* Data ID %d berhasil dihapus.", id))
+			if deleted {
+				// Data masuk tong sampah, kasih tombol undo selama masa simpan
+				undo := &InlineKeyboardMarkup{
+					InlineKeyboard: [][]InlineKeyboardButton{
+						{{Text: utils.T(lang, "bot.button.undo"), CallbackData: fmt.Sprintf("del_undo_%d", id)}},
+					},
+				}
+				editMessage(chatID, messageID, utils.T(lang, "bot.deleted", id, utils.TrashRetentionDays()), undo)
+			} else {
+				editMessage(chatID, messageID, utils.T(lang, "bot.delete_failed"), nil)
+			}
+		} else if strings.HasPrefix(data, "del_undo_") {
+			id, _ := strconv.Atoi(strings.TrimPrefix(data, "del_undo_"))
+			restored, err := h.trxService.Restore(user.ID, uint(id))
+			if err != nil {
+				log.Printf("[BOT] Gagal restore transaksi %d: %v", id, err)
+			}
+			if restored {
+				editMessage(chatID, messageID, utils.T(lang, "bot.restored", id), nil)
 			} else {
-				editMessage(chatID, messageID, "❌ Gagal hapus. Data mungkin sudah hilang.")
+				editMessage(chatID, messageID, utils.T(lang, "bot.restore_failed"), nil)
+			}
+		} else if data == callbackExportThisMonth || data == callbackExportLastMonth {
+			period := utils.PeriodThisMonth
+			if data == callbackExportLastMonth {
+				period = utils.PeriodLastMonth
 			}
+			editMessage(chatID, messageID, utils.T(lang, "bot.export_sending"), nil)
+			h.sendExport(chatID, user, lang, utils.PeriodQuery{Period: period})
 		} else if data == "del_cancel" {
-			editMessage(chatID, messageID, "👌 Penghapusan dibatalkan.")
+			editMessage(chatID, messageID, utils.T(lang, "bot.delete_cancelled"), nil)
 		} else if strings.HasPrefix(data, "save_") {
+			// save_<tipe>_<nominal minor unit>_<kategori>[_<mata uang>]
+			// Tombol lama tanpa mata uang berarti mata uang dasar user
 			parts := strings.Split(data, "_")
 			if len(parts) >= 4 {
-				tipe := parts[1]
-				amount, _ := strconv.Atoi(parts[2])
+				// Data tombol tetap divalidasi ulang oleh service (tipe, nominal, kategori)
+				tipe := models.TransactionType(parts[1])
+				amount, _ := strconv.ParseInt(parts[2], 10, 64)
 				category := parts[3]
+				currency := ""
+				if len(parts) >= 5 {
+					currency = parts[4]
+				}
 
 				trx := models.Transaction{
 					UserID:   user.ID,
 					Amount:   amount,
+					Currency: currency,
 					Type:     tipe,
 					Category: category,
 					Note:     "Via Quick Button",
 				}
-				database.DB.Create(&trx)
-
-				database.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("last_transaction_at", time.Now())
+				alert, err := h.trxService.Create(&trx)
+				if err != nil {
+					log.Printf("[BOT] Gagal simpan transaksi user %d: %v", user.ID, err)
+					editMessage(chatID, messageID, botErrorText(lang, err), nil)
+					c.JSON(http.StatusOK, gin.H{"status": "callback_failed"})
+					return
+				}
 
 				icon := "Dn"
 				alertMsg := ""
 
-				if tipe == "income" { 
+				if tipe == models.TypeIncome { 
 					icon = "UP" 
-				} else {
-					// GUNAKAN HELPER DARI TRANSACTION.GO
-					alertMsg = "\n\n🚨 " + CheckDailyLimit(user.ID, 0)
-					if alertMsg == "\n\n🚨 " { alertMsg = "" } // Bersihkan jika kosong
+				} else if alert != "" {
+					alertMsg = "\n\n🚨 " + alert
 				}
 				
-				finalMsg := fmt.Sprintf("✅ *Tersimpan!*\nID: %d\n%s Rp %d\n📂 %s%s", trx.ID, icon, amount, category, alertMsg)
-				editMessage(chatID, messageID, finalMsg)
+				finalMsg := utils.T(lang, "bot.saved", trx.ID, icon, money.Format(trx.Amount, trx.Currency), category, botTime(user, trx.CreatedAt), alertMsg)
+				editMessage(chatID, messageID, finalMsg, nil)
 			}
 		}
 
@@ -134,163 +182,304 @@ func TelegramWebhook(c *gin.Context) {
 
 	// --- 2. HANDLING CHAT BIASA (MESSAGE) ---
 	if payload.Message == nil {
-		return 
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
 	}
 
 	text := payload.Message.Text
 	chatID := payload.Message.Chat.ID
 
 	// Cek User di DB
-	var user models.User
-	if err := database.DB.Where("telegram_id = ?", chatID).First(&user).Error; err != nil {
+	user, err := h.users.FindByTelegramID(chatID)
+	if err != nil {
 		// PERUBAHAN: Menampilkan ID Telegram user secara langsung
-		pesan := fmt.Sprintf("🚫 <b>Akses Ditolak</b>\n\n"+
-			"Anda belum terdaftar dalam sistem ini.\n\n"+
-			"👉 <b>Cara Daftar:</b>\n"+
-			"1. ID Telegram kamu adalah: <code>%d</code>\n"+
-			"2. Teruskan (forward) ID tersebut ke admin <b>@unxpctedd</b> untuk didaftarkan.", chatID)
+		// User belum terdaftar: pakai bahasa aplikasi Telegram-nya
+		pesan := utils.T(utils.UserLanguage(payload.Message.From.LanguageCode), "bot.unregistered", chatID)
 		
 		sendReply(chatID, pesan, nil)
 		c.JSON(http.StatusOK, gin.H{"status": "replied_unregistered"})
 		return
 	}
 
+	lang := utils.UserLanguage(user.Language)
+
 	if strings.HasPrefix(text, "/del ") {
 		idStr := strings.TrimPrefix(text, "/del ")
 		id, err := strconv.Atoi(idStr)
 		if err != nil {
-			sendReply(chatID, "⚠️ ID harus angka.", nil)
+			sendReply(chatID, utils.T(lang, "bot.id_not_number"), nil)
+			c.JSON(http.StatusOK, gin.H{"status": "replied"})
 			return
 		}
-		var trx models.Transaction
-		if err := database.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&trx).Error; err != nil {
-			sendReply(chatID, "❌ Data tidak ditemukan.", nil)
+		trx, err := h.transactions.FindForUser(user.ID, uint(id))
+		if err != nil {
+			sendReply(chatID, utils.T(lang, "bot.not_found"), nil)
+			c.JSON(http.StatusOK, gin.H{"status": "replied"})
 			return
 		}
 		keyboard := &InlineKeyboardMarkup{
 			InlineKeyboard: [][]InlineKeyboardButton{
-				{{Text: "✅ Ya, Hapus", CallbackData: fmt.Sprintf("del_yes_%d", trx.ID)}, {Text: "❌ Batal", CallbackData: "del_cancel"}},
+				{{Text: utils.T(lang, "bot.button.confirm_delete"), CallbackData: fmt.Sprintf("del_yes_%d", trx.ID)}, {Text: utils.T(lang, "bot.button.cancel"), CallbackData: "del_cancel"}},
 			},
 		}
-		msg := fmt.Sprintf("⚠️ *KONFIRMASI HAPUS*\n\nKategori: %s\nNominal: %d\n\nYakin hapus?", trx.Category, trx.Amount)
+		msg := utils.T(lang, "bot.delete_confirm", trx.Category, money.Format(trx.Amount, trx.Currency), botTime(user, trx.CreatedAt))
 		sendReply(chatID, msg, keyboard)
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
 		return
 	}
 
 	if text == "/saldo" || text == "/summary" || text == "cek" {
-		handleCekSaldo(chatID, user.ID)
+		h.handleCekSaldo(chatID, user.ID, lang)
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
 		return
 	}
 
+	// Ganti bahasa balasan bot: /lang id atau /lang en
+	if text == "/lang" || strings.HasPrefix(text, "/lang ") {
+		newLang := utils.NormalizeLanguage(strings.TrimSpace(strings.TrimPrefix(text, "/lang")))
+		if newLang == "" {
+			sendReply(chatID, utils.T(lang, "bot.lang_usage"), nil)
+		} else if err := h.users.UpdateFields(user.ID, map[string]interface{}{"language": newLang}); err != nil {
+			log.Printf("[BOT] Gagal ganti bahasa user %d: %v", user.ID, err)
+			sendReply(chatID, utils.T(lang, "INTERNAL_ERROR"), nil)
+		} else {
+			sendReply(chatID, utils.T(newLang, "bot.lang_changed"), nil)
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
+		return
+	}
 
-	if text == "/start" || text == "/help" {
-		helpText := `🤖 <b>DompetPintarBot</b>
+	// Ganti zona waktu (batas "hari ini" untuk limit harian & laporan): /tz Asia/Jakarta
+	if text == "/tz" || strings.HasPrefix(text, "/tz ") {
+		timezone := utils.NormalizeTimezone(strings.TrimPrefix(text, "/tz"))
+		if timezone == "" {
+			sendReply(chatID, utils.T(lang, "bot.tz_usage", utils.UserLocation(user.Timezone).String()), nil)
+		} else if err := h.users.UpdateFields(user.ID, map[string]interface{}{"timezone": timezone}); err != nil {
+			log.Printf("[BOT] Gagal ganti zona waktu user %d: %v", user.ID, err)
+			sendReply(chatID, utils.T(lang, "INTERNAL_ERROR"), nil)
+		} else {
+			user.Timezone = timezone
+			sendReply(chatID, utils.T(lang, "bot.tz_changed", timezone, botTime(user, time.Now())), nil)
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
+		return
+	}
 
-<b>1. Perintah Dasar</b>
-• /saldo — Cek total uang masuk, keluar, dan sisa saldo.
-• /del &lt;ID&gt; — Hapus transaksi (akan muncul tombol konfirmasi).
+	// Laporan Excel ke chat ini: /export, /export 11 2025, /export auto on|off
+	if text == "/export" || strings.HasPrefix(text, "/export ") {
+		h.handleExport(chatID, user, lang, strings.TrimPrefix(text, "/export"))
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
+		return
+	}
 
-<b>2. Cara Input di Telegram</b>
-• <code>+50000</code> — Input Pemasukan (Bot akan tanya kategori).
-• <code>-20000</code> — Input Pengeluaran (Bot akan tanya kategori).
-• <code>+50000 Gaji</code> — Input Pemasukan Langsung.
-• <code>-20000 Makan</code> — Input Pengeluaran Langsung.
+	// Rapikan kategori: /recat makan Makan
+	if text == "/recat" || strings.HasPrefix(text, "/recat ") {
+		h.handleRecategorize(chatID, user, lang, strings.Fields(strings.TrimPrefix(text, "/recat")))
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
+		return
+	}
 
-<b>3. Dashboard Web (www.dompet-pintar.work.gd)</b>
-• 🌐 <b>Login:</b> Buka website untuk input data, edit, dan hapus dengan lebih leluasa.
-• 📊 <b>Pantau:</b> Lihat grafik analisa harian/bulanan dan download laporan Excel.
+	// Target tabungan: /target (progres), /target 3 500000 (setor)
+	if text == "/target" || strings.HasPrefix(text, "/target ") {
+		h.handleTarget(chatID, user, lang, strings.Fields(strings.TrimPrefix(text, "/target")))
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
+		return
+	}
 
-<i>Perlu bantuan, hubungi @unxpctedd</i>`
+	if text == "/start" || text == "/help" {
+		helpText := utils.T(lang, "bot.help")
 
 		sendReply(chatID, helpText, nil)
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
 		return
 	}
 
 	isTransaction := strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-")
 	if !isTransaction {
-		sendReply(chatID, "⚠️ Perintah tidak dikenali. ketik /help", nil)
+		sendReply(chatID, utils.T(lang, "bot.unknown_command"), nil)
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
 		return
 	}
 
 	parts := strings.Fields(text)
 	nominalStr := parts[0]
-	tipe := "expense"
-	if strings.HasPrefix(nominalStr, "+") { tipe = "income" }
+	tipe := models.TypeExpense
+	if strings.HasPrefix(nominalStr, "+") { tipe = models.TypeIncome }
+
+	// Mata uang opsional setelah nominal: "-12.50 USD Makan"
+	currency := ""
+	if len(parts) > 1 && len(parts[1]) == 3 && money.Normalize(parts[1]) != "" {
+		currency = money.Normalize(parts[1])
+		parts = append(parts[:1], parts[2:]...)
+	}
+	if currency == "" {
+		if currency = money.Normalize(user.BaseCurrency); currency == "" {
+			currency = money.DefaultCurrency
+		}
+	}
 
 	cleanNominal := strings.TrimPrefix(strings.TrimPrefix(nominalStr, "+"), "-")
-	amount, err := strconv.Atoi(cleanNominal)
-	if err != nil {
-		sendReply(chatID, "⚠️ Angka tidak valid.", nil)
+	amount, err := money.Parse(cleanNominal, currency)
+	if err != nil || amount <= 0 {
+		sendReply(chatID, utils.T(lang, "bot.invalid_number"), nil)
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
 		return
 	}
 
 	if len(parts) == 1 {
+		callback := func(category string) string {
+			return fmt.Sprintf("save_%s_%d_%s_%s", tipe, amount, category, currency)
+		}
 		var buttons [][]InlineKeyboardButton
-		if tipe == "income" {
+		if tipe == models.TypeIncome {
 			buttons = [][]InlineKeyboardButton{
-				{{Text: "💰 Gaji", CallbackData: fmt.Sprintf("save_income_%d_Gaji", amount)}},
-				{{Text: "🎁 Bonus", CallbackData: fmt.Sprintf("save_income_%d_Bonus", amount)}},
-				{{Text: "💵 Usaha", CallbackData: fmt.Sprintf("save_income_%d_Usaha", amount)}},
+				{{Text: "💰 Gaji", CallbackData: callback("Gaji")}},
+				{{Text: "🎁 Bonus", CallbackData: callback("Bonus")}},
+				{{Text: "💵 Usaha", CallbackData: callback("Usaha")}},
 			}
 		} else {
 			buttons = [][]InlineKeyboardButton{
-				{{Text: "🍲 Makan", CallbackData: fmt.Sprintf("save_expense_%d_Makan", amount)}},
-				{{Text: "🚕 Transport", CallbackData: fmt.Sprintf("save_expense_%d_Transport", amount)}},
-				{{Text: "🛒 Belanja", CallbackData: fmt.Sprintf("save_expense_%d_Belanja", amount)}},
-				{{Text: "⚡ Tagihan", CallbackData: fmt.Sprintf("save_expense_%d_Tagihan", amount)}},
+				{{Text: "🍲 Makan", CallbackData: callback("Makan")}},
+				{{Text: "🚕 Transport", CallbackData: callback("Transport")}},
+				{{Text: "🛒 Belanja", CallbackData: callback("Belanja")}},
+				{{Text: "⚡ Tagihan", CallbackData: callback("Tagihan")}},
 			}
 		}
 		replyMarkup := &InlineKeyboardMarkup{InlineKeyboard: buttons}
-		sendReply(chatID, fmt.Sprintf("📂 Pilih Kategori untuk *%s Rp %d*:", strings.ToUpper(tipe), amount), replyMarkup)
+		sendReply(chatID, utils.T(lang, "bot.pick_category", utils.T(lang, "bot.type."+string(tipe)), money.Format(amount, currency)), replyMarkup)
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
+		return
+	}
+
+	// Split per kategori: "-150000 Belanja:100000 Jajan:50000 [catatan]"
+	if strings.Contains(parts[1], ":") {
+		h.saveBotSplit(chatID, user, lang, tipe, amount, currency, parts[1:])
+		c.JSON(http.StatusOK, gin.H{"status": "replied"})
 		return
 	}
 
 	trx := models.Transaction{
 		UserID:   user.ID,
 		Amount:   amount,
+		Currency: currency,
 		Type:     tipe,
 		Category: parts[1],
 		Note:     strings.Join(parts[2:], " "),
 	}
-	database.DB.Create(&trx)
-database.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("last_transaction_at", time.Now())
+	alert, err := h.trxService.Create(&trx)
+	if err != nil {
+		log.Printf("[BOT] Gagal simpan transaksi user %d: %v", user.ID, err)
+		sendReply(chatID, botErrorText(lang, err), nil)
+		c.JSON(http.StatusOK, gin.H{"status": "failed"})
+		return
+	}
 	icon := "Dn"
 	alertMsg := ""
-	if tipe == "income" { 
+	if tipe == models.TypeIncome { 
 		icon = "UP" 
-	} else {
-		// GUNAKAN HELPER DARI TRANSACTION.GO
-		alertMsg = "\n\n🚨 " + CheckDailyLimit(user.ID, 0)
-		if alertMsg == "\n\n🚨 " { alertMsg = "" }
+	} else if alert != "" {
+		alertMsg = "\n\n🚨 " + alert
 	}
 	
-	pesan := fmt.Sprintf("✅ *Tersimpan!*\nID: %d\n%s Rp %d\n📂 %s%s", trx.ID, icon, amount, parts[1], alertMsg)
+	pesan := utils.T(lang, "bot.saved", trx.ID, icon, money.Format(trx.Amount, trx.Currency), parts[1], botTime(user, trx.CreatedAt), alertMsg)
 	sendReply(chatID, pesan, nil)
 	c.JSON(http.StatusOK, gin.H{"status": "saved"})
 }
 
-func handleCekSaldo(chatID int64, userID uint) {
-	var trx []models.Transaction
-	database.DB.Where("user_id = ?", userID).Find(&trx)
-	var inc, exp int
-	for _, t := range trx {
-		if t.Type == "income" { inc += t.Amount } else { exp += t.Amount }
+// botTime: waktu untuk pesan bot, di zona waktu user (mis. "12-11-2025 15:04 WIB")
+func botTime(user *models.User, t time.Time) string {
+	return t.In(utils.UserLocation(user.Timezone)).Format("02-01-2006 15:04 MST")
+}
+
+// botErrorText: pesan error untuk balasan bot. Error validasi ditampilkan apa adanya,
+// error internal cukup pesan umum (detailnya sudah masuk log).
+func botErrorText(lang string, err error) string {
+	appErr := serviceError(err)
+	if appErr.Status >= http.StatusInternalServerError {
+		return utils.T(lang, utils.CodeInternal)
+	}
+	return "⚠️ " + utils.T(lang, appErr.Code, appErr.Args...)
+}
+
+func (h *Handler) handleCekSaldo(chatID int64, userID uint, lang string) {
+	inc, exp, currency, err := h.trxService.Summary(userID, time.Time{}, time.Time{})
+	var missing *services.MissingRateError
+	if errors.As(err, &missing) {
+		sendReply(chatID, utils.T(lang, "bot.rate_missing", missing.From, missing.To), nil)
+		return
+	}
+	if err != nil {
+		log.Printf("[BOT] Gagal hitung saldo user %d: %v", userID, err)
+		sendReply(chatID, utils.T(lang, "INTERNAL_ERROR"), nil)
+		return
+	}
+	sendReply(chatID, utils.T(lang, "bot.balance", money.Format(inc-exp, currency), money.Format(inc, currency), money.Format(exp, currency)), nil)
+}
+
+// telegramURL: endpoint Bot API untuk method tertentu.
+// TELEGRAM_API_URL opsional (default https://api.telegram.org), mis. untuk Bot API server sendiri / test.
+func telegramURL(method string) string {
+	base := strings.TrimRight(os.Getenv("TELEGRAM_API_URL"), "/")
+	if base == "" {
+		base = "https://api.telegram.org"
 	}
-	sendReply(chatID, fmt.Sprintf("💰 Saldo: Rp %d\n(Masuk: %d, Keluar: %d)", inc-exp, inc, exp), nil)
+	return fmt.Sprintf("%s/bot%s/%s", base, os.Getenv("TELEGRAM_BOT_TOKEN"), method)
 }
 
 func sendReply(chatID int64, text string, markup *InlineKeyboardMarkup) {
-	token := os.Getenv("TELEGRAM_BOT_TOKEN")
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", token)
 	msg := TelegramResponse{ChatID: chatID, Text: text, ParseMode: "HTML", ReplyMarkup: markup}
-	body, _ := json.Marshal(msg)
-	http.Post(url, "application/json", bytes.NewBuffer(body))
+	postTelegram(telegramURL("sendMessage"), msg)
 }
 
-func editMessage(chatID int64, messageID int, text string) {
-	token := os.Getenv("TELEGRAM_BOT_TOKEN")
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/editMessageText", token)
-	msg := EditMessageResponse{ChatID: chatID, MessageID: messageID, Text: text, ParseMode: "HTML"}
-	body, _ := json.Marshal(msg)
-	http.Post(url, "application/json", bytes.NewBuffer(body))
+func editMessage(chatID int64, messageID int, text string, markup *InlineKeyboardMarkup) {
+	msg := EditMessageResponse{ChatID: chatID, MessageID: messageID, Text: text, ParseMode: "HTML", ReplyMarkup: markup}
+	postTelegram(telegramURL("editMessageText"), msg)
+}
+
+// sendDocument mengirim file (multipart) ke chat. Beda dengan sendReply, error dikembalikan
+// supaya pengiriman otomatis bisa dicoba lagi nanti.
+func sendDocument(chatID int64, fileName string, content []byte, caption string) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	form.WriteField("caption", caption)
+	form.WriteField("parse_mode", "HTML")
+	part, err := form.CreateFormFile("document", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(content); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	resp, err := http.Post(telegramURL("sendDocument"), form.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram menolak dokumen: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Helper: Kirim request JSON ke Bot API, error cukup dicatat di log (webhook tetap balas 200)
+func postTelegram(url string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[BOT] Gagal encode pesan: %v", err)
+		return
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		log.Printf("[BOT] Gagal kirim ke Telegram: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("[BOT] Telegram menolak pesan: status %d", resp.StatusCode)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,220 @@
+package handlers_test
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/models"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// fakeTelegram mencatat semua panggilan Bot API (TELEGRAM_API_URL diarahkan ke sini)
+type fakeTelegram struct {
+	mu        sync.Mutex
+	messages  []telegramMessage
+	documents []telegramDocument
+}
+
+type telegramMessage struct {
+	ChatID int64  `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type telegramDocument struct {
+	ChatID   string
+	FileName string
+	Caption  string
+	Content  []byte
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	t.Helper()
+	fake := &fakeTelegram{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendDocument"):
+			file, header, err := r.FormFile("document")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(file)
+			fake.documents = append(fake.documents, telegramDocument{
+				ChatID:   r.FormValue("chat_id"),
+				FileName: header.Filename,
+				Caption:  r.FormValue("caption"),
+				Content:  content,
+			})
+		default:
+			var msg telegramMessage
+			json.NewDecoder(r.Body).Decode(&msg)
+			fake.messages = append(fake.messages, msg)
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("TELEGRAM_API_URL", srv.URL)
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test")
+	return fake
+}
+
+// reset mengembalikan lalu mengosongkan catatan panggilan
+func (f *fakeTelegram) reset() ([]telegramMessage, []telegramDocument) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	messages, documents := f.messages, f.documents
+	f.messages, f.documents = nil, nil
+	return messages, documents
+}
+
+func (a *testApp) linkTelegram(user *models.User, chatID int64) {
+	a.t.Helper()
+	if err := a.repos.Users.UpdateFields(user.ID, map[string]interface{}{"telegram_id": chatID}); err != nil {
+		a.t.Fatalf("link telegram: %v", err)
+	}
+}
+
+func (a *testApp) botMessage(chatID int64, text string) {
+	a.t.Helper()
+	res := a.do(http.MethodPost, "/telegram/webhook", "", map[string]interface{}{
+		"message": map[string]interface{}{"text": text, "chat": map[string]interface{}{"id": chatID}},
+	})
+	expectStatus(a.t, res, http.StatusOK)
+}
+
+func TestBotExport(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	user := app.createUser("budi", "user", "trial")
+	app.linkTelegram(user, 777)
+
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+	app.createTransaction(user, "income", 7500000, "Gaji", time.Date(2025, time.April, 1, 9, 0, 0, 0, jakarta))
+	app.createTransaction(user, "expense", 32500, "Makan", time.Date(2025, time.April, 2, 12, 0, 0, 0, jakarta))
+	app.createTransaction(user, "expense", 10000, "Parkir", time.Date(2025, time.May, 1, 8, 0, 0, 0, jakarta))
+
+	// Tanpa argumen: pilihan bulan ini / bulan lalu
+	app.botMessage(777, "/export")
+	messages, documents := telegram.reset()
+	if len(messages) != 1 || len(documents) != 0 || !strings.Contains(messages[0].Text, "Pilih periode") {
+		t.Fatalf("/export: messages = %+v, documents = %d", messages, len(documents))
+	}
+
+	// Workbook yang sama dengan GET /api/export, hanya transaksi April
+	app.botMessage(777, "/export 4 2025")
+	messages, documents = telegram.reset()
+	if len(messages) != 0 || len(documents) != 1 {
+		t.Fatalf("/export 4 2025: messages = %+v, documents = %d", messages, len(documents))
+	}
+	doc := documents[0]
+	if doc.ChatID != "777" || !strings.HasSuffix(doc.FileName, ".xlsx") || !strings.Contains(doc.Caption, "01-04-2025 s/d 30-04-2025") {
+		t.Fatalf("document = %s %s %q", doc.ChatID, doc.FileName, doc.Caption)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(doc.Content))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	rows, err := f.GetRows(exporter.SheetName)
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("rows = %d (%v)", len(rows), err)
+	}
+
+	// Bulan tidak valid
+	app.botMessage(777, "/export 13 2025")
+	messages, documents = telegram.reset()
+	if len(messages) != 1 || len(documents) != 0 || !strings.Contains(messages[0].Text, "/export [bulan] [tahun]") {
+		t.Fatalf("/export 13 2025: messages = %+v", messages)
+	}
+
+	// Tombol "bulan lalu"
+	res := app.do(http.MethodPost, "/telegram/webhook", "", map[string]interface{}{
+		"callback_query": map[string]interface{}{
+			"data":    "export_last_month",
+			"from":    map[string]interface{}{"id": 777},
+			"message": map[string]interface{}{"message_id": 5, "chat": map[string]interface{}{"id": 777}},
+		},
+	})
+	expectStatus(t, res, http.StatusOK)
+	if _, documents = telegram.reset(); len(documents) != 1 {
+		t.Fatalf("callback: documents = %d", len(documents))
+	}
+
+	// Laporan bulanan otomatis: bulan berjalan dianggap sudah terkirim
+	app.botMessage(777, "/export auto on")
+	telegram.reset()
+	updated, err := app.repos.Users.FindByID(user.ID)
+	if err != nil {
+		t.Fatalf("find user: %v", err)
+	}
+	lastMonth := time.Now().In(jakarta).AddDate(0, 0, 1-time.Now().In(jakarta).Day()).AddDate(0, -1, 0)
+	if !updated.MonthlyReport || updated.LastMonthlyReport != lastMonth.Format("2006-01-02") {
+		t.Fatalf("monthly report = %v %q", updated.MonthlyReport, updated.LastMonthlyReport)
+	}
+}
+
+func TestSendMonthlyReports(t *testing.T) {
+	telegram := newFakeTelegram(t)
+	app := newTestApp(t)
+	jakarta := utils.UserLocation(utils.DefaultTimezone)
+
+	active := app.createUser("budi", "user", "trial")
+	app.linkTelegram(active, 777)
+	app.createTransaction(active, "expense", 32500, "Makan", time.Date(2025, time.March, 14, 12, 0, 0, 0, jakarta))
+	empty := app.createUser("sari", "user", "trial")
+	app.linkTelegram(empty, 778)
+	off := app.createUser("andi", "user", "trial")
+	app.linkTelegram(off, 779)
+	app.createTransaction(off, "expense", 15000, "Kopi", time.Date(2025, time.March, 14, 12, 0, 0, 0, jakarta))
+	for _, user := range []*models.User{active, empty} {
+		if err := app.repos.Users.UpdateFields(user.ID, map[string]interface{}{"monthly_report": true}); err != nil {
+			t.Fatalf("enable monthly report: %v", err)
+		}
+	}
+
+	// Belum jam kirim di tanggal 1
+	if err := app.handler.SendMonthlyReports(time.Date(2025, time.April, 1, 5, 0, 0, 0, jakarta)); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if _, documents := telegram.reset(); len(documents) != 0 {
+		t.Fatalf("before hour: documents = %d", len(documents))
+	}
+
+	now := time.Date(2025, time.April, 1, 8, 0, 0, 0, jakarta)
+	if err := app.handler.SendMonthlyReports(now); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	_, documents := telegram.reset()
+	if len(documents) != 1 || documents[0].ChatID != strconv.Itoa(777) || !strings.Contains(documents[0].Caption, "01-03-2025 s/d 31-03-2025") {
+		t.Fatalf("documents = %+v", documents)
+	}
+	// Bulan kosong tidak dikirim, tapi tetap ditandai
+	for _, user := range []*models.User{active, empty} {
+		updated, err := app.repos.Users.FindByID(user.ID)
+		if err != nil {
+			t.Fatalf("find user: %v", err)
+		}
+		if updated.LastMonthlyReport != "2025-03-01" {
+			t.Fatalf("user %s last report = %q", user.Username, updated.LastMonthlyReport)
+		}
+	}
+
+	// Periode yang sama tidak dikirim dua kali
+	if err := app.handler.SendMonthlyReports(now.Add(time.Hour)); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if _, documents := telegram.reset(); len(documents) != 0 {
+		t.Fatalf("second run: documents = %d", len(documents))
+	}
+}
@@ -0,0 +1,242 @@
+// Package importer membaca riwayat transaksi dari file lain (CSV & XLSX termasuk hasil /api/export, OFX, QIF)
+// jadi baris siap simpan, juga rekening koran bank (CSV/PDF) untuk rekonsiliasi.
+// Aturan bisnis (validasi kategori, deteksi duplikat, simpan, pencocokan) ada di services.
+package importer
+
+import (
+	"backend-gin/models"
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format file yang didukung
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatOFX  Format = "ofx"
+	FormatQIF  Format = "qif"
+	FormatPDF  Format = "pdf" // Hanya rekening koran (ParseStatement), bukan import biasa
+)
+
+// MaxRows: batas baris per file (lebih dari ini dipecah dulu oleh user)
+const MaxRows = 5000
+
+// DefaultCategory: kategori untuk baris tanpa kategori (OFX/QIF, CSV tanpa kolom kategori)
+const DefaultCategory = "Import"
+
+var (
+	ErrFormatUnknown  = errors.New("format file tidak dikenal")
+	ErrFileInvalid    = errors.New("file tidak bisa dibaca")
+	ErrTooManyRows    = errors.New("baris file terlalu banyak")
+	ErrMappingInvalid = errors.New("pemetaan kolom tidak lengkap / tidak cocok")
+)
+
+// Options: pengaturan pembacaan file
+type Options struct {
+	Format Format
+	// Mapping: CSV/XLSX, field -> judul kolom. Kosong = ditebak dari judul kolom.
+	Mapping Mapping
+	// Currency: mata uang kalau file tidak menyebutkan (kode baku, mis. mata uang dasar user)
+	Currency string
+	// DateLayout: layout Go dari DateLayout(), kosong = coba format umum
+	DateLayout      string
+	Location        *time.Location
+	DefaultCategory string
+}
+
+// Row: satu transaksi hasil baca file. Code/Field terisi kalau barisnya tidak bisa dibaca.
+type Row struct {
+	Line     int
+	Date     time.Time
+	Type     models.TransactionType
+	Amount   int64 // minor unit, selalu positif (arah ada di Type)
+	Currency string
+	Category string
+	Note     string
+	Field    string
+	Code     string
+}
+
+// Valid: baris terbaca tanpa error
+func (r Row) Valid() bool {
+	return r.Code == ""
+}
+
+func (r *Row) fail(field, code string) {
+	if r.Code == "" {
+		r.Field, r.Code = field, code
+	}
+}
+
+// Result: isi file + kolom & pemetaan yang dipakai (untuk preview pemetaan kolom CSV/XLSX)
+type Result struct {
+	Format  Format
+	Columns []string
+	Mapping Mapping
+	Rows    []Row
+}
+
+// DetectFormat: format dari parameter eksplisit, kalau kosong dari ekstensi nama file
+func DetectFormat(explicit, filename string) (Format, error) {
+	name := strings.ToLower(strings.TrimSpace(explicit))
+	if name == "" {
+		name = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	switch name {
+	case "csv", "txt":
+		return FormatCSV, nil
+	case "xlsx":
+		return FormatXLSX, nil
+	case "ofx", "qfx":
+		return FormatOFX, nil
+	case "qif":
+		return FormatQIF, nil
+	case "pdf":
+		return FormatPDF, nil
+	}
+	return "", ErrFormatUnknown
+}
+
+// Parse membaca seluruh file sesuai opts.Format
+func Parse(r io.Reader, opts Options) (*Result, error) {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.DefaultCategory == "" {
+		opts.DefaultCategory = DefaultCategory
+	}
+
+	var result *Result
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		result, err = parseCSV(r, opts)
+	case FormatXLSX:
+		result, err = parseXLSX(r, opts)
+	case FormatOFX:
+		result, err = parseOFX(r, opts)
+	case FormatQIF:
+		result, err = parseQIF(r, opts)
+	default:
+		return nil, ErrFormatUnknown
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) > MaxRows {
+		return nil, ErrTooManyRows
+	}
+	result.Format = opts.Format
+	return result, nil
+}
+
+// DateLayout mengubah pola tanggal ("DD/MM/YYYY", "MM/DD/YY", "YYYY-MM-DD") jadi layout Go.
+// false kalau polanya tidak lengkap / berisi karakter lain.
+func DateLayout(pattern string) (string, bool) {
+	pattern = strings.ToUpper(strings.TrimSpace(pattern))
+	layout := strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02").Replace(pattern)
+	if !strings.Contains(layout, "01") || !strings.Contains(layout, "02") || !strings.Contains(layout, "06") {
+		return "", false
+	}
+	if strings.Trim(layout, "0126/-. ") != "" {
+		return "", false
+	}
+	return layout, true
+}
+
+// Format tanggal yang dicoba kalau user tidak menentukan. Urutan penting:
+// tanggal-bulan-tahun (kebiasaan Indonesia & format /api/export) sebelum format lain.
+var dateLayouts = []string{
+	"2006-01-02",
+	"02-01-2006",
+	"02/01/2006",
+	"2/1/2006",
+	"02.01.2006",
+	"2006/01/02",
+	"02-01-06",
+	"02/01/06",
+}
+
+// parseDate: tanggal (boleh + jam) di zona waktu user. RFC3339 dengan offset ikut offset-nya.
+func parseDate(value, layout string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), true
+	}
+
+	layouts := dateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, l := range layouts {
+		for _, suffix := range []string{"", " 15:04", " 15:04:05", "T15:04:05"} {
+			if t, err := time.ParseInLocation(l+suffix, value, loc); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseClock: jam "15:04", "15:04:05" atau "15.04" jadi durasi sejak tengah malam
+func parseClock(value string) (time.Duration, bool) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ".", ":")
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// parseType: jenis transaksi dari teks bebas (/api/export menulis "INCOME"/"EXPENSE")
+func parseType(value string) (models.TransactionType, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "income", "pemasukan", "masuk", "credit", "kredit", "cr", "in", "+":
+		return models.TypeIncome, true
+	case "expense", "pengeluaran", "keluar", "debit", "debet", "db", "dr", "out", "-":
+		return models.TypeExpense, true
+	}
+	return "", false
+}
+
+// addClock: tanggal + jam dari kolom terpisah
+func addClock(day time.Time, clock time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Add(clock)
+}
+
+// isNumber: nilai mentah angka (sel XLSX tanpa format)
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return err == nil
+}
+
+// rowNote: gabungan dua teks (mis. payee + memo) tanpa pengulangan
+func rowNote(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		duplicate := false
+		for _, seen := range out {
+			if strings.EqualFold(seen, p) {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " - ")
+}
@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"backend-gin/models"
+	"backend-gin/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+var wib = time.FixedZone("WIB", 7*3600)
+
+func parse(t *testing.T, format Format, content string, opts Options) *Result {
+	t.Helper()
+	opts.Format = format
+	opts.Location = wib
+	if opts.Currency == "" {
+		opts.Currency = "IDR"
+	}
+	result, err := Parse(strings.NewReader(content), opts)
+	if err != nil {
+		t.Fatalf("Parse %s: %v", format, err)
+	}
+	return result
+}
+
+func TestParseCSVDebitCredit(t *testing.T) {
+	content := "Tanggal,Jam,Uraian,Debit,Kredit\n" +
+		"2025-03-01,08.30,TARIK TUNAI,\"100,000.00\",\n" +
+		"\n" +
+		"2025-03-02,,TRANSFER MASUK,,250000\n" +
+		"2025-03-03,25:00,SALAH JAM,5000,\n"
+	rows := parse(t, FormatCSV, content, Options{}).Rows
+	if len(rows) != 3 {
+		t.Fatalf("jumlah baris = %d, mau 3 (baris kosong dilewati)", len(rows))
+	}
+
+	if r := rows[0]; r.Type != models.TypeExpense || r.Amount != 100000 || r.Note != "TARIK TUNAI" ||
+		!r.Date.Equal(time.Date(2025, 3, 1, 8, 30, 0, 0, wib)) {
+		t.Errorf("baris debit = %+v", r)
+	}
+	if r := rows[1]; r.Type != models.TypeIncome || r.Amount != 250000 || r.Line != 4 || r.Category != DefaultCategory {
+		t.Errorf("baris kredit = %+v", r)
+	}
+	if r := rows[2]; r.Valid() || r.Field != FieldTime || r.Code != utils.CodeDateInvalid {
+		t.Errorf("baris jam salah = %+v", r)
+	}
+}
+
+func TestParseCSVDateLayout(t *testing.T) {
+	layout, ok := DateLayout("mm/dd/yyyy")
+	if !ok || layout != "01/02/2006" {
+		t.Fatalf("DateLayout = %q, %v", layout, ok)
+	}
+	if _, ok := DateLayout("DD/MM"); ok {
+		t.Fatal("pola tanpa tahun harusnya ditolak")
+	}
+
+	rows := parse(t, FormatCSV, "date,amount,type\n03/01/2025,1500,INCOME\n", Options{DateLayout: layout}).Rows
+	if r := rows[0]; !r.Date.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, wib)) || r.Type != models.TypeIncome {
+		t.Errorf("baris = %+v, mau 1 Maret (bulan/tanggal)", r)
+	}
+}
+
+func TestParseOFX(t *testing.T) {
+	content := `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250301
+<TRNAMT>-12.50
+<FITID>1
+<NAME>COFFEE SHOP
+<MEMO>Latte
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250302170000.000[-5:EST]
+<TRNAMT>1500.00
+<FITID>2
+<NAME>PAYROLL
+<MEMO>PAYROLL
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+	rows := parse(t, FormatOFX, content, Options{}).Rows
+	if len(rows) != 2 {
+		t.Fatalf("jumlah baris = %d, mau 2", len(rows))
+	}
+	if r := rows[0]; r.Type != models.TypeExpense || r.Amount != 1250 || r.Currency != "USD" ||
+		r.Note != "COFFEE SHOP - Latte" || !r.Date.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, wib)) {
+		t.Errorf("baris 1 = %+v", r)
+	}
+	// 17:00 EST = 05:00 WIB keesokan harinya
+	if r := rows[1]; r.Type != models.TypeIncome || r.Amount != 150000 || r.Note != "PAYROLL" ||
+		!r.Date.Equal(time.Date(2025, 3, 3, 5, 0, 0, 0, wib)) || r.Line != 16 {
+		t.Errorf("baris 2 = %+v", r)
+	}
+}
+
+func TestParseQIF(t *testing.T) {
+	content := "!Account\nNChecking\nTBank\n^\n" +
+		"!Type:Bank\n" +
+		"D3/1'25\nT-1,234.00\nPSupermarket\nLBelanja:Bulanan\n^\n" +
+		"D03/02/2025\nT500000\nPTransfer\nL[Tabungan]\n^\n" +
+		"Dbesok\nT10\n^\n"
+	rows := parse(t, FormatQIF, content, Options{}).Rows
+	if len(rows) != 3 {
+		t.Fatalf("jumlah baris = %d, mau 3 (blok !Account dilewati)", len(rows))
+	}
+	if r := rows[0]; r.Type != models.TypeExpense || r.Amount != 1234 || r.Category != "Belanja" ||
+		!r.Date.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, wib)) || r.Line != 6 {
+		t.Errorf("baris 1 = %+v", r)
+	}
+	if r := rows[1]; r.Type != models.TypeIncome || r.Category != DefaultCategory || r.Date.Day() != 2 {
+		t.Errorf("baris transfer = %+v", r)
+	}
+	if r := rows[2]; r.Valid() || r.Field != FieldDate {
+		t.Errorf("baris tanggal salah = %+v", r)
+	}
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseOFX membaca <STMTTRN> dari file OFX/QFX. Versi SGML (1.x, tag daun tanpa penutup)
+// maupun XML (2.x) sama-sama dibaca sebagai urutan "<TAG>nilai".
+func parseOFX(r io.Reader, opts Options) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+	}
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, fmt.Errorf("%w: tag <OFX> tidak ada", ErrFileInvalid)
+	}
+
+	result := &Result{}
+	currency := opts.Currency
+	var fields map[string]string
+	line := 1 + bytes.Count(data[:start], []byte("\n"))
+	trnLine := 0
+
+	finish := func() {
+		if fields != nil {
+			result.Rows = append(result.Rows, ofxRow(fields, trnLine, currency, opts))
+			fields = nil
+		}
+	}
+
+	rest := data[start:]
+	for len(rest) > 0 {
+		open := bytes.IndexByte(rest, '<')
+		if open < 0 {
+			break
+		}
+		line += bytes.Count(rest[:open], []byte("\n"))
+		rest = rest[open+1:]
+		end := bytes.IndexByte(rest, '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.ToUpper(strings.TrimSpace(string(rest[:end])))
+		rest = rest[end+1:]
+
+		next := bytes.IndexByte(rest, '<')
+		if next < 0 {
+			next = len(rest)
+		}
+		value := strings.TrimSpace(string(rest[:next]))
+
+		switch {
+		case tag == "STMTTRN":
+			finish()
+			fields, trnLine = map[string]string{}, line
+		case tag == "/STMTTRN" || tag == "/BANKTRANLIST":
+			finish()
+		case tag == "CURDEF":
+			if code := money.Normalize(value); code != "" {
+				currency = code
+			} else {
+				currency = ""
+			}
+		case fields != nil && !strings.HasPrefix(tag, "/"):
+			fields[tag] = value
+		}
+		if len(result.Rows) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+	finish()
+	return result, nil
+}
+
+func ofxRow(fields map[string]string, line int, currency string, opts Options) Row {
+	row := Row{
+		Line:     line,
+		Currency: currency,
+		Category: opts.DefaultCategory,
+		Note:     rowNote(fields["NAME"], fields["PAYEE"], fields["MEMO"]),
+	}
+	if currency == "" {
+		row.fail(FieldCurrency, utils.CodeCurrencyInvalid)
+	}
+
+	date, ok := ofxDate(fields["DTPOSTED"], opts.Location)
+	if !ok {
+		row.fail(FieldDate, utils.CodeDateInvalid)
+	}
+	row.Date = date
+
+	amount, err := money.ParseDecimal(fields["TRNAMT"], currency)
+	if err != nil {
+		row.fail(FieldAmount, utils.CodeInvalidAmount)
+	}
+	row.Type = models.TypeIncome
+	if amount < 0 {
+		row.Type, amount = models.TypeExpense, -amount
+	}
+	row.Amount = amount
+	return row
+}
+
+// ofxDate: "YYYYMMDD[HHMMSS[.XXX]][[+7:WIB]]". Tanpa jam = tanggal itu di zona waktu user;
+// dengan jam & offset = momen persis, lalu dipindah ke zona user.
+func ofxDate(value string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	zone := loc
+	if i := strings.IndexByte(value, '['); i >= 0 {
+		offset := strings.TrimSuffix(value[i+1:], "]")
+		value = value[:i]
+		if j := strings.IndexByte(offset, ':'); j >= 0 {
+			offset = offset[:j]
+		}
+		if hours, err := strconv.ParseFloat(offset, 64); err == nil {
+			zone = time.FixedZone("", int(hours*3600))
+		}
+	}
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	switch len(value) {
+	case 8:
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, err == nil
+	case 12, 14:
+		layout := "200601021504"
+		if len(value) == 14 {
+			layout += "05"
+		}
+		t, err := time.ParseInLocation(layout, value, zone)
+		return t.In(loc), err == nil
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,486 @@
+package importer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// pdfLines: teks e-statement PDF per baris, tanpa library PDF. Content stream (FlateDecode atau tanpa
+// kompresi) dibaca operator teksnya (Tj, TJ, ', "), lalu potongan teks dikelompokkan menurut posisi y
+// per halaman dan diurutkan menurut x. Cukup untuk PDF cetakan sistem bank; PDF hasil scan (gambar)
+// atau font tanpa encoding standar tidak menghasilkan teks dan berakhir ErrStatementEmpty.
+func pdfLines(r io.Reader) ([]statementLine, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+	}
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF")) {
+		return nil, fmt.Errorf("%w: bukan file PDF", ErrFileInvalid)
+	}
+	// E-statement yang dikunci password harus dibuka / disimpan ulang tanpa password dulu
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return nil, ErrStatementEncrypted
+	}
+
+	var lines []statementLine
+	for _, content := range pdfStreams(data) {
+		for _, cells := range pdfText(content) {
+			lines = append(lines, statementLine{number: len(lines) + 1, cells: cells})
+		}
+	}
+	return lines, nil
+}
+
+// pdfStreams: isi semua stream yang mungkin berisi teks halaman, urut kemunculan di file
+func pdfStreams(data []byte) [][]byte {
+	var out [][]byte
+	pos := 0
+	for {
+		i := bytes.Index(data[pos:], []byte("stream"))
+		if i < 0 {
+			break
+		}
+		start := pos + i
+		// "endstream" juga mengandung "stream"
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			pos = start + len("stream")
+			continue
+		}
+		body := start + len("stream")
+		if body < len(data) && data[body] == '\r' {
+			body++
+		}
+		if body < len(data) && data[body] == '\n' {
+			body++
+		}
+		end := bytes.Index(data[body:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+
+		// Dictionary stream = antara "N 0 obj" terakhir dan kata "stream"
+		dict := data[pos:start]
+		if j := bytes.LastIndex(dict, []byte("obj")); j >= 0 {
+			dict = dict[j:]
+		}
+		raw := bytes.TrimRight(data[body:body+end], "\r\n")
+		pos = body + end + len("endstream")
+
+		if content, ok := pdfDecode(string(dict), raw); ok {
+			out = append(out, content)
+		}
+	}
+	return out
+}
+
+// pdfDecode: isi stream yang sudah didekompresi, false untuk gambar, font, metadata & filter lain
+func pdfDecode(dict string, raw []byte) ([]byte, bool) {
+	for _, skip := range []string{"/Image", "/XRef", "/ObjStm", "/Metadata", "/Length1", "/Length2", "/Length3",
+		"/DCTDecode", "/JPXDecode", "/CCITTFaxDecode", "/JBIG2Decode", "/LZWDecode", "/ASCII85Decode",
+		"/ASCIIHexDecode", "/RunLengthDecode"} {
+		if strings.Contains(dict, skip) {
+			return nil, false
+		}
+	}
+	if strings.Contains(dict, "/FlateDecode") {
+		zr, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, false
+		}
+		// Stream yang terpotong tetap dipakai sebagian
+		raw, _ = io.ReadAll(zr)
+	}
+	if !bytes.Contains(raw, []byte("BT")) {
+		return nil, false
+	}
+	return raw, true
+}
+
+// pdfMatrix: matriks transformasi PDF [a b c d e f]
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// mul: m × n (konvensi PDF, vektor baris)
+func (m pdfMatrix) mul(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// pdfOperand: operand operator content stream (angka, string, atau array untuk TJ)
+type pdfOperand struct {
+	num    float64
+	isNum  bool
+	text   string
+	isText bool
+	array  []pdfOperand
+}
+
+type pdfItem struct {
+	x, y float64
+	seq  int
+	text string
+}
+
+// pdfText: teks satu content stream (satu halaman) dikelompokkan per baris, tiap potongan teks = satu sel
+func pdfText(content []byte) [][]string {
+	lex := &pdfLexer{data: content}
+	var (
+		items   []pdfItem
+		stack   []pdfOperand
+		ctm     = pdfIdentity
+		saved   []pdfMatrix
+		tm, tlm = pdfIdentity, pdfIdentity
+		leading float64
+	)
+	numbers := func(n int) ([]float64, bool) {
+		if len(stack) < n {
+			return nil, false
+		}
+		out := make([]float64, n)
+		for i, op := range stack[len(stack)-n:] {
+			if !op.isNum {
+				return nil, false
+			}
+			out[i] = op.num
+		}
+		return out, true
+	}
+	moveLine := func(tx, ty float64) {
+		tlm = pdfMatrix{1, 0, 0, 1, tx, ty}.mul(tlm)
+		tm = tlm
+	}
+	show := func(text string) {
+		text = strings.TrimSpace(text)
+		if text == "" || !readableText(text) {
+			return
+		}
+		at := tm.mul(ctm)
+		items = append(items, pdfItem{x: at[4], y: at[5], seq: len(items), text: text})
+	}
+
+	for {
+		op, operand, ok := lex.next()
+		if !ok {
+			break
+		}
+		if op == "" {
+			stack = append(stack, operand)
+			continue
+		}
+
+		switch op {
+		case "q":
+			saved = append(saved, ctm)
+		case "Q":
+			if n := len(saved); n > 0 {
+				ctm, saved = saved[n-1], saved[:n-1]
+			}
+		case "cm":
+			if v, ok := numbers(6); ok {
+				ctm = pdfMatrix{v[0], v[1], v[2], v[3], v[4], v[5]}.mul(ctm)
+			}
+		case "BT":
+			tm, tlm = pdfIdentity, pdfIdentity
+		case "Tm":
+			if v, ok := numbers(6); ok {
+				tlm = pdfMatrix{v[0], v[1], v[2], v[3], v[4], v[5]}
+				tm = tlm
+			}
+		case "Td":
+			if v, ok := numbers(2); ok {
+				moveLine(v[0], v[1])
+			}
+		case "TD":
+			if v, ok := numbers(2); ok {
+				leading = -v[1]
+				moveLine(v[0], v[1])
+			}
+		case "TL":
+			if v, ok := numbers(1); ok {
+				leading = v[0]
+			}
+		case "T*":
+			moveLine(0, -leading)
+		case "Tj", "'", "\"":
+			if op != "Tj" {
+				moveLine(0, -leading)
+			}
+			if n := len(stack); n > 0 && stack[n-1].isText {
+				show(stack[n-1].text)
+			}
+		case "TJ":
+			if n := len(stack); n > 0 {
+				var sb strings.Builder
+				for _, part := range stack[n-1].array {
+					switch {
+					case part.isText:
+						sb.WriteString(part.text)
+					// Jarak besar antar potongan (satuan 1/1000 em) = spasi
+					case part.isNum && part.num < -200:
+						sb.WriteByte(' ')
+					}
+				}
+				show(sb.String())
+			}
+		}
+		stack = stack[:0]
+	}
+
+	// Baris = potongan dengan y (hampir) sama, atas ke bawah; sel kiri ke kanan
+	sort.SliceStable(items, func(i, j int) bool { return items[i].y > items[j].y })
+	var lines [][]string
+	for i := 0; i < len(items); {
+		j := i + 1
+		for j < len(items) && items[i].y-items[j].y <= 2 {
+			j++
+		}
+		row := items[i:j]
+		sort.SliceStable(row, func(a, b int) bool {
+			if row[a].x != row[b].x {
+				return row[a].x < row[b].x
+			}
+			return row[a].seq < row[b].seq
+		})
+		cells := make([]string, len(row))
+		for k, item := range row {
+			cells[k] = item.text
+		}
+		lines = append(lines, cells)
+		i = j
+	}
+	return lines
+}
+
+// readableText: teks dari font tanpa encoding standar (kode glyph 2 byte) keluar sebagai karakter kontrol
+func readableText(text string) bool {
+	printable, total := 0, 0
+	for _, r := range text {
+		total++
+		if unicode.IsPrint(r) && r != unicode.ReplacementChar {
+			printable++
+		}
+	}
+	return printable*5 >= total*4
+}
+
+// pdfLexer: pemecah token content stream PDF
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func pdfWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func pdfDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// next: operator (op tidak kosong) atau operand. false = akhir stream.
+func (l *pdfLexer) next() (string, pdfOperand, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case pdfWhitespace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '(':
+			return "", pdfOperand{text: l.literal(), isText: true}, true
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+			l.skipDict()
+		case c == '<':
+			return "", pdfOperand{text: l.hex(), isText: true}, true
+		case c == '[':
+			l.pos++
+			var array []pdfOperand
+			for {
+				op, operand, ok := l.next()
+				if !ok || op == "]" {
+					break
+				}
+				if op == "" {
+					array = append(array, operand)
+				}
+			}
+			return "", pdfOperand{array: array}, true
+		case c == ']':
+			l.pos++
+			return "]", pdfOperand{}, true
+		case c == '/':
+			l.pos++
+			l.word()
+			return "", pdfOperand{}, true
+		case pdfDelimiter(c):
+			l.pos++
+		default:
+			word := l.word()
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				return "", pdfOperand{num: n, isNum: true}, true
+			}
+			if word == "ID" {
+				l.skipInlineImage()
+			}
+			return word, pdfOperand{}, true
+		}
+	}
+	return "", pdfOperand{}, false
+}
+
+func (l *pdfLexer) word() string {
+	start := l.pos
+	for l.pos < len(l.data) && !pdfWhitespace(l.data[l.pos]) && !pdfDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// literal: string "(...)" dengan escape & kurung bersarang
+func (l *pdfLexer) literal() string {
+	l.pos++
+	var buf []byte
+	for depth := 1; l.pos < len(l.data); l.pos++ {
+		c := l.data[l.pos]
+		switch c {
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.data) {
+				break
+			}
+			e := l.data[l.pos]
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b', 'f':
+			case '\r':
+				if l.pos+1 < len(l.data) && l.data[l.pos+1] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && l.pos+1 < len(l.data) && l.data[l.pos+1] >= '0' && l.data[l.pos+1] <= '7'; k++ {
+						l.pos++
+						v = v*8 + int(l.data[l.pos]-'0')
+					}
+					buf = append(buf, byte(v))
+				} else {
+					buf = append(buf, e)
+				}
+			}
+		case '(':
+			depth++
+			buf = append(buf, c)
+		case ')':
+			if depth--; depth == 0 {
+				l.pos++
+				return pdfString(buf)
+			}
+			buf = append(buf, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return pdfString(buf)
+}
+
+// hex: string "<48656C6C6F>"
+func (l *pdfLexer) hex() string {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !pdfWhitespace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	buf := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		buf = append(buf, byte(v))
+	}
+	return pdfString(buf)
+}
+
+// skipDict: dictionary di content stream (properti marked content) tidak dipakai
+func (l *pdfLexer) skipDict() {
+	depth := 0
+	for l.pos+1 < len(l.data) {
+		switch {
+		case l.data[l.pos] == '<' && l.data[l.pos+1] == '<':
+			depth++
+			l.pos += 2
+		case l.data[l.pos] == '>' && l.data[l.pos+1] == '>':
+			l.pos += 2
+			if depth--; depth == 0 {
+				return
+			}
+		case l.data[l.pos] == '(':
+			l.literal()
+		default:
+			l.pos++
+		}
+	}
+	l.pos = len(l.data)
+}
+
+// skipInlineImage: data biner gambar inline sampai "EI"
+func (l *pdfLexer) skipInlineImage() {
+	for l.pos+2 < len(l.data) {
+		if l.data[l.pos] == 'E' && l.data[l.pos+1] == 'I' && pdfWhitespace(l.data[l.pos-1]) &&
+			(l.pos+2 == len(l.data) || pdfWhitespace(l.data[l.pos+2])) {
+			l.pos += 2
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+// pdfString: UTF-16BE dengan BOM, selain itu dibaca per byte (PDFDocEncoding ≈ Latin-1)
+func pdfString(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		units := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
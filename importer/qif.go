@@ -0,0 +1,119 @@
+package importer
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format tanggal QIF (Quicken, format Amerika: bulan/tanggal). "'" sebelum tahun diganti "/".
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "1-2-2006", "1-2-06"}
+
+// parseQIF membaca record transaksi QIF (!Type:Bank / Cash / CCard), satu record diakhiri "^".
+// Blok !Account dilewati, split (S/E/$) diabaikan karena total ada di T.
+func parseQIF(r io.Reader, opts Options) (*Result, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	result := &Result{}
+	var fields map[byte]string
+	recordLine := 0
+	skipping := false
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if text[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(text))
+			skipping = header == "!account" || strings.HasPrefix(header, "!option")
+			continue
+		}
+		if text[0] == '^' {
+			if fields != nil && !skipping {
+				result.Rows = append(result.Rows, qifRow(fields, recordLine, opts))
+				if len(result.Rows) > MaxRows {
+					return nil, ErrTooManyRows
+				}
+			}
+			fields = nil
+			skipping = false
+			continue
+		}
+
+		if fields == nil {
+			fields, recordLine = map[byte]string{}, line
+		}
+		code, value := text[0], strings.TrimSpace(text[1:])
+		// Field pertama yang menang (split memakai huruf lain, jadi tidak menimpa)
+		if _, ok := fields[code]; !ok {
+			fields[code] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+	}
+	// Record terakhir tanpa "^"
+	if fields != nil && !skipping {
+		result.Rows = append(result.Rows, qifRow(fields, recordLine, opts))
+	}
+	return result, nil
+}
+
+func qifRow(fields map[byte]string, line int, opts Options) Row {
+	row := Row{
+		Line:     line,
+		Currency: opts.Currency,
+		Category: opts.DefaultCategory,
+		Note:     rowNote(fields['P'], fields['M']),
+	}
+	// Kategori "[Nama Akun]" = transfer antar akun, bukan kategori
+	if category := fields['L']; category != "" && !strings.HasPrefix(category, "[") {
+		// "Makan:Restoran" -> kategori utama saja
+		row.Category = strings.TrimSpace(strings.SplitN(category, ":", 2)[0])
+	}
+
+	date, ok := qifDate(fields['D'], opts)
+	if !ok {
+		row.fail(FieldDate, utils.CodeDateInvalid)
+	}
+	row.Date = date
+
+	value := fields['T']
+	if value == "" {
+		value = fields['U']
+	}
+	amount, err := money.ParseDecimal(value, row.Currency)
+	if err != nil {
+		row.fail(FieldAmount, utils.CodeInvalidAmount)
+	}
+	row.Type = models.TypeIncome
+	if amount < 0 {
+		row.Type, amount = models.TypeExpense, -amount
+	}
+	row.Amount = amount
+	return row
+}
+
+func qifDate(value string, opts Options) (time.Time, bool) {
+	value = strings.ReplaceAll(strings.ReplaceAll(strings.TrimSpace(value), "'", "/"), " ", "")
+	if opts.DateLayout != "" {
+		return parseDate(value, opts.DateLayout, opts.Location)
+	}
+	for _, layout := range qifDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, opts.Location); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
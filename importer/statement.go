@@ -0,0 +1,527 @@
+package importer
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Bank yang rekening korannya dikenali ParseStatement
+const (
+	BankBCA     = "bca"
+	BankMandiri = "mandiri"
+)
+
+// descriptionMaxLength: sama dengan kolom statement_entries.description
+const descriptionMaxLength = 255
+
+var (
+	ErrBankUnknown        = errors.New("bank tidak dikenal")
+	ErrStatementEmpty     = errors.New("tidak ada mutasi yang dikenali")
+	ErrStatementEncrypted = errors.New("PDF terkunci password")
+)
+
+// ValidBank: bank yang didukung ParseStatement
+func ValidBank(bank string) bool {
+	return bank == BankBCA || bank == BankMandiri
+}
+
+// StatementEntry: satu mutasi rekening koran
+type StatementEntry struct {
+	Line        int
+	Date        time.Time
+	Description string
+	Type        models.TransactionType
+	Amount      int64  // minor unit, selalu positif
+	Balance     *int64 // saldo setelah mutasi, kalau tercetak
+	Pending     bool   // belum dibukukan bank (PEND di BCA), tanggalnya = akhir periode
+}
+
+// Statement: isi rekening koran
+type Statement struct {
+	Bank          string
+	AccountNumber string
+	Currency      string
+	PeriodFrom    time.Time
+	PeriodTo      time.Time // hari terakhir (inklusif)
+	Entries       []StatementEntry
+}
+
+// statementLine: satu baris file (sel CSV, atau potongan teks PDF yang sebaris)
+type statementLine struct {
+	number int
+	cells  []string
+}
+
+// ParseStatement membaca rekening koran BCA / Mandiri: CSV unduhan internet banking atau e-statement PDF.
+// Baris mutasi = baris yang diawali tanggal dan diakhiri nominal ber-2 desimal; arahnya dari penanda
+// DB/CR, tanda +/-, kolom debit/kredit, atau selisih dengan saldo sebelumnya.
+// opts.Currency dipakai kalau file tidak menyebutkan mata uang.
+func ParseStatement(r io.Reader, bank string, opts Options) (*Statement, error) {
+	if !ValidBank(bank) {
+		return nil, ErrBankUnknown
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+
+	var lines []statementLine
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		lines, err = csvLines(r)
+	case FormatPDF:
+		lines, err = pdfLines(r)
+	default:
+		return nil, ErrFormatUnknown
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &statementParser{
+		loc:          opts.Location,
+		statement:    &Statement{Bank: bank, Currency: opts.Currency},
+		continuation: opts.Format == FormatPDF,
+		last:         -1,
+	}
+	for _, line := range lines {
+		// Info rekening hanya dari kepala dokumen, keterangan mutasi bisa saja memuat "NO REK ..."
+		if len(p.statement.Entries) == 0 {
+			p.header(line.cells)
+		}
+		p.entry(line)
+		if len(p.statement.Entries) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+
+	st := p.statement
+	if len(st.Entries) == 0 {
+		return nil, ErrStatementEmpty
+	}
+	// Periode tidak tercetak: dari tanggal mutasi pertama & terakhir
+	if st.PeriodFrom.IsZero() {
+		for _, e := range st.Entries {
+			if st.PeriodFrom.IsZero() || e.Date.Before(st.PeriodFrom) {
+				st.PeriodFrom = e.Date
+			}
+			if e.Date.After(st.PeriodTo) {
+				st.PeriodTo = e.Date
+			}
+		}
+	}
+	return st, nil
+}
+
+func csvLines(r io.Reader) ([]statementLine, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = statementDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var lines []statementLine
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+		}
+		line, _ := reader.FieldPos(0)
+		lines = append(lines, statementLine{number: line, cells: record})
+	}
+	return lines, nil
+}
+
+// statementDelimiter: baris pertama rekening koran biasanya judul tanpa pemisah, jadi dihitung dari seluruh isi
+func statementDelimiter(data []byte) rune {
+	best, count := ',', 0
+	for _, sep := range []rune{';', '\t', '|', ','} {
+		if n := bytes.Count(data, []byte(string(sep))); n > count {
+			best, count = sep, n
+		}
+	}
+	return best
+}
+
+type statementParser struct {
+	loc          *time.Location
+	statement    *Statement
+	continuation bool   // PDF: keterangan panjang berlanjut ke baris berikutnya
+	last         int    // indeks mutasi terakhir (tujuan baris lanjutan), -1 = tidak ada
+	balance      *int64 // saldo terakhir yang diketahui
+}
+
+var (
+	accountPattern  = regexp.MustCompile(`(?i)(?:no(?:mor)?\.?\s*rek(?:ening)?|account\s*(?:no|number))\.?\s*:?\s*(\d[\d -]{4,}\d)`)
+	dateToken       = `\d{1,2}[ /.-](?:\d{1,2}|[A-Za-z]{3,9})[ /.-]\d{2,4}`
+	periodPattern   = regexp.MustCompile(`(?i)periode?\s*:?\s*(` + dateToken + `)\s*(?:-|s/?d\.?|sampai|hingga|to)\s*(` + dateToken + `)`)
+	periodMonth     = regexp.MustCompile(`(?i)periode?\s*:?\s*([A-Za-z]+)\s+(\d{4})\b`)
+	currencyPattern = regexp.MustCompile(`(?i)(?:mata uang|currency)\s*:?\s*([A-Za-z]{3})\b`)
+	datePattern     = regexp.MustCompile(`^(\d{1,2})[ /.-](\d{1,2}|[A-Za-z]{3,9})(?:[ /.-](\d{4}|\d{2}))?$`)
+	isoDatePattern  = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	// Nominal rekening koran selalu tercetak dengan 2 desimal: "1,000,000.00", "1.000.000,00", "-25.000,00", "25,000.00 DB"
+	amountPattern = regexp.MustCompile(`^([+-])?\s*(?:[Rr][Pp]\.?\s*)?(\d{1,3}(?:[.,]\d{3})+|\d+)([.,])(\d{2})\s*(DB|CR|db|cr)?\s*(-)?$`)
+)
+
+// Nama bulan Indonesia & Inggris (lengkap / singkatan) di kepala rekening koran dan tanggal Mandiri
+var monthNames = map[string]time.Month{
+	"jan": time.January, "januari": time.January, "january": time.January,
+	"feb": time.February, "februari": time.February, "february": time.February,
+	"mar": time.March, "maret": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"mei": time.May, "may": time.May,
+	"jun": time.June, "juni": time.June, "june": time.June,
+	"jul": time.July, "juli": time.July, "july": time.July,
+	"agu": time.August, "agt": time.August, "agustus": time.August, "aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"okt": time.October, "oktober": time.October, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"des": time.December, "desember": time.December, "dec": time.December, "december": time.December,
+}
+
+// header: nomor rekening, periode & mata uang dari kepala dokumen
+func (p *statementParser) header(cells []string) {
+	text := strings.Join(cleanCells(cells), " ")
+	st := p.statement
+	if st.AccountNumber == "" {
+		if m := accountPattern.FindStringSubmatch(text); m != nil {
+			st.AccountNumber = strings.NewReplacer(" ", "", "-", "").Replace(m[1])
+		}
+	}
+	if st.PeriodFrom.IsZero() {
+		if m := periodPattern.FindStringSubmatch(text); m != nil {
+			from, okFrom := p.date(m[1])
+			to, okTo := p.date(m[2])
+			if okFrom && okTo && !to.Before(from) {
+				st.PeriodFrom, st.PeriodTo = from, to
+			}
+		} else if m := periodMonth.FindStringSubmatch(text); m != nil {
+			if month, ok := monthNames[strings.ToLower(m[1])]; ok {
+				year, _ := strconv.Atoi(m[2])
+				st.PeriodFrom = time.Date(year, month, 1, 0, 0, 0, 0, p.loc)
+				st.PeriodTo = st.PeriodFrom.AddDate(0, 1, -1)
+			}
+		}
+	}
+	if m := currencyPattern.FindStringSubmatch(text); m != nil {
+		if code := money.Normalize(m[1]); code != "" {
+			st.Currency = code
+		}
+	}
+}
+
+// statementAmount: satu sel nominal
+type statementAmount struct {
+	value  int64
+	sign   string // "+" / "-" / kosong
+	marker string // "DB" / "CR" / kosong
+}
+
+func (a statementAmount) direction() models.TransactionType {
+	switch {
+	case a.marker == "DB" || a.sign == "-":
+		return models.TypeExpense
+	case a.marker == "CR" || a.sign == "+":
+		return models.TypeIncome
+	}
+	return ""
+}
+
+func (p *statementParser) parseAmount(cell string) (statementAmount, bool) {
+	m := amountPattern.FindStringSubmatch(cell)
+	if m == nil || strings.Contains(m[2], m[3]) {
+		return statementAmount{}, false
+	}
+	digits := strings.NewReplacer(".", "", ",", "").Replace(m[2])
+	value, err := money.ParseDecimal(digits+"."+m[4], p.statement.Currency)
+	if err != nil {
+		return statementAmount{}, false
+	}
+	a := statementAmount{value: value, sign: m[1], marker: strings.ToUpper(m[5])}
+	if m[6] != "" {
+		a.sign = "-"
+	}
+	return a, true
+}
+
+// entry mengenali satu baris mutasi; baris lain diabaikan (atau jadi lanjutan keterangan di PDF)
+func (p *statementParser) entry(line statementLine) {
+	cells := cleanCells(line.cells)
+	if len(cells) == 0 {
+		return
+	}
+	// Kolom pertama nomor rekening (sebagian CSV Mandiri)
+	if isDigitString(cells[0]) && len(cells[0]) >= 6 {
+		if p.statement.AccountNumber == "" {
+			p.statement.AccountNumber = cells[0]
+		}
+		cells = cells[1:]
+	}
+	if len(cells) == 0 {
+		return
+	}
+
+	date, pending, rest, ok := p.leadingDate(cells)
+	if !ok {
+		p.continueDescription(cells)
+		return
+	}
+
+	// Nominal (+ penanda DB/CR & kode cabang) ada di ujung baris, sisanya keterangan
+	var amounts []statementAmount
+	marker := ""
+	k := len(rest) - 1
+	for ; k >= 0; k-- {
+		cell := rest[k]
+		if upper := strings.ToUpper(cell); upper == "DB" || upper == "CR" {
+			marker = upper
+			continue
+		}
+		if a, ok := p.parseAmount(cell); ok {
+			if a.marker == "" {
+				a.marker = marker
+			}
+			marker = ""
+			amounts = append([]statementAmount{a}, amounts...)
+			continue
+		}
+		if isDigitString(cell) && len(cell) <= 4 {
+			continue
+		}
+		break
+	}
+	p.last = -1
+	if len(amounts) == 0 {
+		return
+	}
+
+	var words []string
+	for _, cell := range rest[:k+1] {
+		if isDigitString(cell) && len(cell) <= 4 {
+			continue
+		}
+		words = append(words, cell)
+	}
+	description := truncateDescription(strings.Join(strings.Fields(strings.Join(words, " ")), " "))
+
+	// Saldo awal / akhir bukan mutasi, tapi jadi patokan arah mutasi berikutnya
+	if balanceRow(description) {
+		balance := amounts[len(amounts)-1].value
+		p.balance = &balance
+		return
+	}
+
+	e := StatementEntry{Line: line.number, Date: date, Description: description, Pending: pending}
+	var balance *int64
+	first := amounts[0]
+	switch {
+	case len(amounts) >= 2 && first.direction() == "" && amounts[1].direction() == "" &&
+		(first.value == 0 || amounts[1].value == 0):
+		// Kolom debit & kredit terpisah (+ saldo)
+		if first.value > 0 {
+			e.Type, e.Amount = models.TypeExpense, first.value
+		} else {
+			e.Type, e.Amount = models.TypeIncome, amounts[1].value
+		}
+		if len(amounts) >= 3 {
+			balance = &amounts[len(amounts)-1].value
+		}
+	default:
+		e.Type, e.Amount = first.direction(), first.value
+		if len(amounts) >= 2 {
+			balance = &amounts[len(amounts)-1].value
+		}
+	}
+	if e.Amount <= 0 {
+		return
+	}
+	if e.Type == "" {
+		switch {
+		case balance != nil && p.balance != nil && *p.balance-e.Amount == *balance:
+			e.Type = models.TypeExpense
+		default:
+			// BCA hanya menandai mutasi debit
+			e.Type = models.TypeIncome
+		}
+	}
+	e.Balance = balance
+	if balance != nil {
+		p.balance = balance
+	}
+
+	p.statement.Entries = append(p.statement.Entries, e)
+	p.last = len(p.statement.Entries) - 1
+}
+
+// leadingDate: tanggal di awal baris (boleh diikuti jam / tanggal valuta) + sel sisanya
+func (p *statementParser) leadingDate(cells []string) (time.Time, bool, []string, bool) {
+	if strings.EqualFold(cells[0], "PEND") {
+		date := p.statement.PeriodTo
+		if date.IsZero() {
+			now := time.Now().In(p.loc)
+			date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.loc)
+		}
+		return date, true, cells[1:], true
+	}
+
+	fields := strings.Fields(cells[0])
+	for n := min(3, len(fields)); n >= 1; n-- {
+		date, ok := p.date(strings.Join(fields[:n], " "))
+		if !ok {
+			continue
+		}
+		rest := cells[1:]
+		if remainder := strings.Join(fields[n:], " "); remainder != "" {
+			rest = append([]string{remainder}, rest...)
+		}
+		if len(rest) > 0 {
+			if clock, ok := parseClock(rest[0]); ok {
+				date, rest = addClock(date, clock), rest[1:]
+			}
+		}
+		// Tanggal valuta setelah tanggal transaksi
+		if len(rest) > 0 {
+			if _, ok := p.date(rest[0]); ok {
+				rest = rest[1:]
+			}
+		}
+		return date, false, rest, true
+	}
+	return time.Time{}, false, nil, false
+}
+
+// date: "01/03", "01/03/2025", "01-03-25", "01 Mar 2025", "1 Mei 2025", "2025-03-01".
+// Tanpa tahun (BCA) tahunnya diambil dari periode rekening koran.
+func (p *statementParser) date(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	var year, day int
+	var month time.Month
+	if m := isoDatePattern.FindStringSubmatch(value); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		mon, _ := strconv.Atoi(m[2])
+		day, _ = strconv.Atoi(m[3])
+		month = time.Month(mon)
+	} else if m := datePattern.FindStringSubmatch(value); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		if mon, err := strconv.Atoi(m[2]); err == nil {
+			month = time.Month(mon)
+		} else if month = monthNames[strings.ToLower(m[2])]; month == 0 {
+			return time.Time{}, false
+		}
+		switch {
+		case len(m[3]) == 2:
+			year, _ = strconv.Atoi(m[3])
+			year += 2000
+		case m[3] != "":
+			year, _ = strconv.Atoi(m[3])
+		default:
+			year = p.yearOf(month)
+		}
+	} else {
+		return time.Time{}, false
+	}
+
+	if month < time.January || month > time.December {
+		return time.Time{}, false
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, p.loc)
+	if date.Day() != day || date.Month() != month {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// yearOf: tahun untuk tanggal tanpa tahun. Periode Desember-Januari: bulan sebelum bulan awal = tahun berikutnya.
+func (p *statementParser) yearOf(month time.Month) int {
+	st := p.statement
+	if st.PeriodFrom.IsZero() {
+		return time.Now().In(p.loc).Year()
+	}
+	if month < st.PeriodFrom.Month() {
+		return st.PeriodTo.Year()
+	}
+	return st.PeriodFrom.Year()
+}
+
+// Kata pertama baris yang menandai judul tabel / catatan kaki, bukan lanjutan keterangan
+var statementHeadings = map[string]bool{
+	"TANGGAL": true, "TGL": true, "DATE": true, "KETERANGAN": true, "DESCRIPTION": true, "HALAMAN": true,
+	"PAGE": true, "BERSAMBUNG": true, "SALDO": true, "MUTASI": true, "TOTAL": true, "CATATAN": true,
+}
+
+// continueDescription: PDF memotong keterangan panjang ke baris berikutnya (tanpa tanggal & nominal)
+func (p *statementParser) continueDescription(cells []string) {
+	if !p.continuation || p.last < 0 {
+		return
+	}
+	text := strings.Join(strings.Fields(strings.Join(cells, " ")), " ")
+	first := strings.ToUpper(strings.Fields(text)[0])
+	if strings.Contains(text, ":") || statementHeadings[strings.Trim(first, ".")] {
+		p.last = -1
+		return
+	}
+	e := &p.statement.Entries[p.last]
+	e.Description = truncateDescription(e.Description + " " + text)
+}
+
+// balanceRow: baris saldo awal / akhir
+func balanceRow(description string) bool {
+	upper := strings.ToUpper(description)
+	for _, keyword := range []string{"SALDO AWAL", "SALDO AKHIR", "SALDO PEMBUKAAN", "SALDO PENUTUPAN",
+		"OPENING BALANCE", "CLOSING BALANCE", "BEGINNING BALANCE", "ENDING BALANCE"} {
+		if strings.Contains(upper, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanCells: sel tanpa spasi berlebih & tanpa awalan "'" (BCA memaksa Excel membaca teks), sel kosong dibuang
+func cleanCells(cells []string) []string {
+	out := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		cell = strings.TrimSpace(strings.ReplaceAll(cell, "\u00a0", " "))
+		cell = strings.TrimSpace(strings.TrimPrefix(cell, "'"))
+		if cell != "" {
+			out = append(out, cell)
+		}
+	}
+	return out
+}
+
+func isDigitString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func truncateDescription(s string) string {
+	if utf8.RuneCountInString(s) <= descriptionMaxLength {
+		return s
+	}
+	return string([]rune(s)[:descriptionMaxLength])
+}
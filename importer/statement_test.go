@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"backend-gin/models"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseStatement(t *testing.T, bank string, format Format, content []byte) *Statement {
+	t.Helper()
+	st, err := ParseStatement(bytes.NewReader(content), bank, Options{Format: format, Currency: "IDR", Location: wib})
+	if err != nil {
+		t.Fatalf("ParseStatement %s %s: %v", bank, format, err)
+	}
+	return st
+}
+
+func TestParseStatementBCACSV(t *testing.T) {
+	content := "No. rekening : 1234567890\n" +
+		"Nama : BUDI SANTOSO\n" +
+		"Periode : 25/12/2024 - 24/01/2025\n" +
+		"Kode Mata Uang : Rp\n" +
+		"\n" +
+		"Tanggal Transaksi,Keterangan,Cabang,Jumlah,,Saldo\n" +
+		"'25/12,SALDO AWAL,'0000,,,\"1,000,000.00\"\n" +
+		"'28/12,TRSF E-BANKING DB 2812/FTSCY/WS95031 25000.00 KOPI,'0000,\"25,000.00\",DB,\"975,000.00\"\n" +
+		"'02/01,TRSF E-BANKING CR 0201/FTSCY/WS95051 GAJI,'0998,\"5,000,000.00\",CR,\"5,975,000.00\"\n" +
+		"PEND,TARIKAN ATM 24/01,'0000,\"100,000.00\",DB,\n" +
+		"\n" +
+		"Saldo Awal : \"1,000,000.00\"\n" +
+		"Mutasi Debet : \"125,000.00\"\n"
+	st := parseStatement(t, BankBCA, FormatCSV, []byte(content))
+
+	if st.AccountNumber != "1234567890" || st.Currency != "IDR" ||
+		!st.PeriodFrom.Equal(time.Date(2024, 12, 25, 0, 0, 0, 0, wib)) || !st.PeriodTo.Equal(time.Date(2025, 1, 24, 0, 0, 0, 0, wib)) {
+		t.Fatalf("kepala rekening koran = %+v", st)
+	}
+	if len(st.Entries) != 3 {
+		t.Fatalf("jumlah mutasi = %d, mau 3 (saldo awal & ringkasan dilewati)", len(st.Entries))
+	}
+	// Tanggal tanpa tahun: Desember = 2024, Januari = 2025
+	if e := st.Entries[0]; e.Type != models.TypeExpense || e.Amount != 25000 || e.Line != 8 ||
+		!e.Date.Equal(time.Date(2024, 12, 28, 0, 0, 0, 0, wib)) || e.Balance == nil || *e.Balance != 975000 ||
+		e.Description != "TRSF E-BANKING DB 2812/FTSCY/WS95031 25000.00 KOPI" {
+		t.Errorf("mutasi debit = %+v", e)
+	}
+	if e := st.Entries[1]; e.Type != models.TypeIncome || e.Amount != 5000000 || !e.Date.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, wib)) {
+		t.Errorf("mutasi kredit = %+v", e)
+	}
+	if e := st.Entries[2]; !e.Pending || e.Type != models.TypeExpense || !e.Date.Equal(st.PeriodTo) || e.Balance != nil {
+		t.Errorf("mutasi pending = %+v", e)
+	}
+}
+
+func TestParseStatementMandiriCSV(t *testing.T) {
+	content := "Nomor Rekening;137-00-0123456-7\n" +
+		"Periode;01 Mar 2025 - 31 Mar 2025\n" +
+		"Tanggal;Keterangan;Debit;Kredit;Saldo\n" +
+		"01/03/2025 08:15:22;TRANSFER KE SARI;150.000,00;0,00;850.000,00\n" +
+		"2 Mei 2025;GAJI;0,00;5.000.000,00;5.850.000,00\n" +
+		"03/03/2025;BIAYA ADM;0,00;0,00;5.850.000,00\n"
+	st := parseStatement(t, BankMandiri, FormatCSV, []byte(content))
+
+	if st.AccountNumber != "1370001234567" || st.PeriodFrom.Month() != time.March || st.PeriodTo.Day() != 31 {
+		t.Fatalf("kepala rekening koran = %+v", st)
+	}
+	if len(st.Entries) != 2 {
+		t.Fatalf("jumlah mutasi = %d, mau 2 (nominal nol dilewati)", len(st.Entries))
+	}
+	if e := st.Entries[0]; e.Type != models.TypeExpense || e.Amount != 150000 || e.Description != "TRANSFER KE SARI" ||
+		!e.Date.Equal(time.Date(2025, 3, 1, 8, 15, 22, 0, wib)) {
+		t.Errorf("mutasi debit = %+v", e)
+	}
+	if e := st.Entries[1]; e.Type != models.TypeIncome || e.Amount != 5000000 || !e.Date.Equal(time.Date(2025, 5, 2, 0, 0, 0, 0, wib)) {
+		t.Errorf("mutasi kredit = %+v", e)
+	}
+}
+
+// testPDF: PDF minimal dengan satu halaman berisi baris-baris teks (content stream FlateDecode)
+func testPDF(t *testing.T, rows [][]string, trailer string) []byte {
+	t.Helper()
+	var content strings.Builder
+	content.WriteString("BT /F1 9 Tf\n")
+	for i, row := range rows {
+		y := 760 - i*12
+		for j, cell := range row {
+			fmt.Fprintf(&content, "1 0 0 1 %d %d Tm (%s) Tj\n", 40+j*110, y, strings.NewReplacer("(", `\(`, ")", `\)`).Replace(cell))
+		}
+	}
+	content.WriteString("ET\n")
+
+	var stream bytes.Buffer
+	zw := zlib.NewWriter(&stream)
+	zw.Write([]byte(content.String()))
+	zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	pdf.WriteString("1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n")
+	pdf.WriteString("2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj\n")
+	pdf.WriteString("3 0 obj << /Type /Page /Parent 2 0 R /Contents 4 0 R >> endobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj << /Length %d /Filter /FlateDecode >>\nstream\n", stream.Len())
+	pdf.Write(stream.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("trailer << /Root 1 0 R " + trailer + ">>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestParseStatementPDF(t *testing.T) {
+	pdf := testPDF(t, [][]string{
+		{"REKENING TAHAPAN"},
+		{"NO. REKENING", ": 0987654321"},
+		{"PERIODE", ": MARET 2025"},
+		{"TANGGAL", "KETERANGAN", "CBG", "MUTASI", "SALDO"},
+		{"01/03", "SALDO AWAL", "", "", "1,000,000.00"},
+		{"03/03", "TRSF E-BANKING DB", "0000", "25,000.00 DB", "975,000.00"},
+		{"", "0303/FTSCY/WS95031"},
+		{"", "WARUNG (KOPI)"},
+		{"10/03", "SETORAN TUNAI", "0998", "500,000.00", "1,475,000.00"},
+		{"Bersambung ke Halaman berikut"},
+	}, "")
+	st := parseStatement(t, BankBCA, FormatPDF, pdf)
+
+	if st.AccountNumber != "0987654321" || !st.PeriodFrom.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, wib)) ||
+		!st.PeriodTo.Equal(time.Date(2025, 3, 31, 0, 0, 0, 0, wib)) {
+		t.Fatalf("kepala rekening koran = %+v", st)
+	}
+	if len(st.Entries) != 2 {
+		t.Fatalf("jumlah mutasi = %d, mau 2: %+v", len(st.Entries), st.Entries)
+	}
+	if e := st.Entries[0]; e.Type != models.TypeExpense || e.Amount != 25000 ||
+		e.Description != "TRSF E-BANKING DB 0303/FTSCY/WS95031 WARUNG (KOPI)" || e.Date.Day() != 3 {
+		t.Errorf("mutasi debit = %+v (keterangan lanjutan harus digabung)", e)
+	}
+	// Tanpa penanda: arah dari saldo (975.000 + 500.000)
+	if e := st.Entries[1]; e.Type != models.TypeIncome || e.Amount != 500000 || e.Description != "SETORAN TUNAI" {
+		t.Errorf("mutasi kredit = %+v", e)
+	}
+
+	_, err := ParseStatement(bytes.NewReader(testPDF(t, nil, "/Encrypt 5 0 R ")), BankBCA, Options{Format: FormatPDF})
+	if !errors.Is(err, ErrStatementEncrypted) {
+		t.Errorf("PDF terkunci: err = %v", err)
+	}
+	_, err = ParseStatement(bytes.NewReader(testPDF(t, [][]string{{"HALO"}}, "")), BankBCA, Options{Format: FormatPDF})
+	if !errors.Is(err, ErrStatementEmpty) {
+		t.Errorf("PDF tanpa mutasi: err = %v", err)
+	}
+}
@@ -0,0 +1,360 @@
+package importer
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/utils"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Field tujuan pemetaan kolom CSV/XLSX
+const (
+	FieldDate     = "date"
+	FieldTime     = "time"
+	FieldType     = "type"
+	FieldCategory = "category"
+	FieldNote     = "note"
+	FieldCurrency = "currency"
+	FieldAmount   = "amount" // bertanda: minus = pengeluaran (kalau tidak ada kolom type)
+	FieldDebit    = "debit"  // kolom uang keluar (format mutasi bank)
+	FieldCredit   = "credit" // kolom uang masuk
+)
+
+// Mapping: field -> judul kolom di baris pertama file
+type Mapping map[string]string
+
+// Judul kolom yang dikenali otomatis per field (huruf kecil). Urutan field = urutan prioritas.
+var headerAliases = []struct {
+	field   string
+	aliases []string
+}{
+	{FieldDate, []string{"tanggal", "tgl", "date", "tanggal transaksi", "transaction date", "posting date"}},
+	{FieldTime, []string{"jam", "waktu", "time"}},
+	{FieldType, []string{"tipe", "jenis", "type", "db/cr"}},
+	{FieldCategory, []string{"kategori", "category"}},
+	{FieldNote, []string{"catatan", "keterangan", "note", "notes", "description", "deskripsi", "memo", "uraian"}},
+	{FieldCurrency, []string{"mata uang", "currency", "valuta"}},
+	{FieldAmount, []string{"jumlah", "amount", "nominal", "nilai"}},
+	{FieldDebit, []string{"debit", "debet", "keluar", "uang keluar", "withdrawal"}},
+	{FieldCredit, []string{"kredit", "credit", "masuk", "uang masuk", "deposit"}},
+}
+
+func normalizeHeader(h string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimPrefix(h, "\uFEFF"))), " ")
+}
+
+// resolveMapping: indeks kolom per field, dari pemetaan user atau tebakan judul kolom
+func resolveMapping(header []string, mapping Mapping) (map[string]int, Mapping, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		if key := normalizeHeader(h); key != "" {
+			if _, ok := index[key]; !ok {
+				index[key] = i
+			}
+		}
+	}
+
+	columns := map[string]int{}
+	used := Mapping{}
+	if len(mapping) > 0 {
+		for field, title := range mapping {
+			if !knownField(field) {
+				return nil, nil, fmt.Errorf("%w: field %q", ErrMappingInvalid, field)
+			}
+			if strings.TrimSpace(title) == "" {
+				continue
+			}
+			i, ok := index[normalizeHeader(title)]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: kolom %q tidak ada", ErrMappingInvalid, title)
+			}
+			columns[field] = i
+			used[field] = header[i]
+		}
+	} else {
+		taken := map[int]bool{}
+		for _, f := range headerAliases {
+			for _, alias := range f.aliases {
+				if i, ok := index[alias]; ok && !taken[i] {
+					columns[f.field], used[f.field] = i, header[i]
+					taken[i] = true
+					break
+				}
+			}
+		}
+	}
+
+	_, hasAmount := columns[FieldAmount]
+	_, hasDebit := columns[FieldDebit]
+	_, hasCredit := columns[FieldCredit]
+	if _, ok := columns[FieldDate]; !ok || !(hasAmount || hasDebit || hasCredit) {
+		return nil, used, fmt.Errorf("%w: kolom tanggal & jumlah wajib", ErrMappingInvalid)
+	}
+	return columns, used, nil
+}
+
+func knownField(field string) bool {
+	for _, f := range headerAliases {
+		if f.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// table: isi CSV / sheet XLSX, baris pertama = judul kolom
+type table struct {
+	header []string
+	rows   [][]string
+	lines  []int // nomor baris asli di file (untuk laporan error)
+}
+
+func parseCSV(r io.Reader, opts Options) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var t table
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+		}
+		line, _ := reader.FieldPos(0)
+		t.add(record, line)
+		if len(t.rows) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+	return t.parse(opts, false)
+}
+
+// detectDelimiter: pemisah yang paling sering muncul di baris pertama (Excel Indonesia pakai ";")
+func detectDelimiter(data []byte) rune {
+	first := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		first = data[:i]
+	}
+	best, count := ',', bytes.Count(first, []byte{','})
+	for _, sep := range []rune{';', '\t', '|'} {
+		if n := bytes.Count(first, []byte(string(sep))); n > count {
+			best, count = sep, n
+		}
+	}
+	return best
+}
+
+// parseXLSX: sheet pertama yang judul kolomnya cocok (layout /api/export dikenali otomatis)
+func parseXLSX(r io.Reader, opts Options) (*Result, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+	}
+	defer f.Close()
+
+	var firstErr error
+	for _, sheet := range f.GetSheetList() {
+		// Nilai mentah: angka tetap "12.5" & tanggal asli Excel jadi nomor seri
+		rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFileInvalid, err)
+		}
+		var t table
+		for i, record := range rows {
+			t.add(record, i+1)
+			if len(t.rows) > MaxRows {
+				return nil, ErrTooManyRows
+			}
+		}
+		result, err := t.parse(opts, true)
+		if err == nil {
+			return result, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = fmt.Errorf("%w: tidak ada sheet", ErrMappingInvalid)
+	}
+	return nil, firstErr
+}
+
+// add: baris pertama yang tidak kosong jadi judul kolom, baris kosong dilewati
+func (t *table) add(record []string, line int) {
+	empty := true
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return
+	}
+	if t.header == nil {
+		t.header = record
+		return
+	}
+	t.rows = append(t.rows, record)
+	t.lines = append(t.lines, line)
+}
+
+func (t *table) parse(opts Options, raw bool) (*Result, error) {
+	columns, used, err := resolveMapping(t.header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Columns: t.header, Mapping: used, Rows: make([]Row, 0, len(t.rows))}
+	for i, record := range t.rows {
+		cell := func(field string) string {
+			if col, ok := columns[field]; ok && col < len(record) {
+				return strings.TrimSpace(record[col])
+			}
+			return ""
+		}
+		result.Rows = append(result.Rows, tableRow(cell, t.lines[i], opts, raw))
+	}
+	return result, nil
+}
+
+// tableRow: satu baris CSV/XLSX jadi Row. raw = nilai mentah XLSX (titik desimal, tanggal nomor seri).
+func tableRow(cell func(string) string, line int, opts Options, raw bool) Row {
+	row := Row{Line: line, Category: cell(FieldCategory), Note: cell(FieldNote), Currency: opts.Currency}
+	if row.Category == "" {
+		row.Category = opts.DefaultCategory
+	}
+
+	if code := cell(FieldCurrency); code != "" {
+		if row.Currency = money.Normalize(code); row.Currency == "" {
+			row.fail(FieldCurrency, utils.CodeCurrencyInvalid)
+		}
+	}
+
+	date, ok := tableDate(cell(FieldDate), opts, raw)
+	if !ok {
+		row.fail(FieldDate, utils.CodeDateInvalid)
+	} else if clock := cell(FieldTime); clock != "" {
+		d, ok := tableClock(clock, raw)
+		if !ok {
+			row.fail(FieldTime, utils.CodeDateInvalid)
+		}
+		date = addClock(date, d)
+	}
+	row.Date = date
+
+	// Debit/kredit terpisah (mutasi bank) atau satu kolom jumlah bertanda
+	var negative bool
+	var err error
+	if debit, credit := cell(FieldDebit), cell(FieldCredit); cell(FieldAmount) == "" && (debit != "" || credit != "") {
+		row.Type = models.TypeIncome
+		field, value := FieldCredit, credit
+		if amountOrZero(debit, row.Currency, raw) != 0 {
+			row.Type, field, value = models.TypeExpense, FieldDebit, debit
+		}
+		if row.Amount, _, err = parseAmount(value, row.Currency, raw); err != nil {
+			row.fail(field, utils.CodeInvalidAmount)
+		}
+	} else {
+		if row.Amount, negative, err = parseAmount(cell(FieldAmount), row.Currency, raw); err != nil {
+			row.fail(FieldAmount, utils.CodeInvalidAmount)
+		}
+		row.Type = models.TypeIncome
+		if negative {
+			row.Type = models.TypeExpense
+		}
+	}
+
+	if value := cell(FieldType); value != "" {
+		typ, ok := parseType(value)
+		if !ok {
+			row.fail(FieldType, utils.CodeTransactionTypeInvalid)
+		}
+		row.Type = typ
+	}
+	return row
+}
+
+func tableDate(value string, opts Options, raw bool) (time.Time, bool) {
+	if raw && isNumber(value) {
+		serial, _ := strconv.ParseFloat(value, 64)
+		t, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil {
+			return time.Time{}, false
+		}
+		// Tanggal Excel tidak punya zona waktu: jam dinding dipakai apa adanya di zona user
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, opts.Location), true
+	}
+	return parseDate(value, opts.DateLayout, opts.Location)
+}
+
+func tableClock(value string, raw bool) (time.Duration, bool) {
+	if raw && isNumber(value) {
+		// Jam asli Excel = pecahan hari
+		fraction, _ := strconv.ParseFloat(value, 64)
+		if fraction < 0 || fraction >= 1 {
+			return 0, false
+		}
+		return time.Duration(fraction * float64(24*time.Hour)).Round(time.Second), true
+	}
+	return parseClock(value)
+}
+
+// parseAmount: nominal positif + tanda. Menerima "-25.000", "(25.000)", "Rp 25.000", "12,50".
+func parseAmount(value, currency string, raw bool) (int64, bool, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative, value = true, strings.TrimSpace(value[1:len(value)-1])
+	}
+	if strings.HasPrefix(value, "-") {
+		negative, value = true, strings.TrimSpace(value[1:])
+	} else if strings.HasSuffix(value, "-") {
+		negative, value = true, strings.TrimSpace(value[:len(value)-1])
+	}
+	value = strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(value, "Rp"), ".$€£¥ "))
+	// Sen nol di belakang ("100,000.00" / "25.000,00") dibuang: tanpa ini IDR membacanya sebagai ribuan
+	if n := len(value); n > 3 && (value[n-3] == '.' || value[n-3] == ',') && value[n-2:] == "00" {
+		value = value[:n-3]
+	}
+
+	var amount int64
+	var err error
+	if raw && isNumber(value) {
+		amount, err = money.ParseDecimal(value, currency)
+	} else {
+		amount, err = money.Parse(value, currency)
+	}
+	return amount, negative, err
+}
+
+func amountOrZero(value, currency string, raw bool) int64 {
+	if value == "" {
+		return 0
+	}
+	amount, _, err := parseAmount(value, currency, raw)
+	if err != nil {
+		// Tidak terbaca: anggap terisi supaya errornya dilaporkan di kolom debit
+		return 1
+	}
+	return amount
+}
@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// StartMonthlyReports menjalankan send (laporan bulanan otomatis ke Telegram) di background setiap interval.
+// send sendiri yang memastikan tiap periode hanya terkirim sekali.
+func StartMonthlyReports(send func(now time.Time) error, interval time.Duration) {
+	go func() {
+		for {
+			if err := send(time.Now()); err != nil {
+				log.Printf("[REPORT] Gagal kirim laporan bulanan: %v", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+)
+
+// PurgeTrash menghapus PERMANEN data tong sampah yang sudah lewat masa simpan,
+// juga akun yang minta dihapus sendiri dan masa tunggunya sudah habis
+func PurgeTrash(repos *repository.Repositories, now time.Time) error {
+	cutoff := utils.TrashCutoff(now)
+
+	// 1. User yang sudah kadaluarsa di tong sampah, beserta semua data miliknya
+	trashed, err := repos.Users.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return err
+	}
+	removeFiles(trashed.Files)
+
+	// 2. Akun yang minta dihapus (lihat POST /api/user/delete-request)
+	requested, err := repos.Users.PurgeDeletionDue(now)
+	if err != nil {
+		return err
+	}
+	removeFiles(requested.Files)
+
+	// 3. Transaksi yang dihapus user dan sudah lewat masa simpan
+	transactions, err := repos.Transactions.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return err
+	}
+
+	if users := trashed.Count + requested.Count; users > 0 || transactions > 0 {
+		log.Printf("[PURGE] %d user dan %d transaksi dihapus permanen", users, transactions)
+	}
+	return nil
+}
+
+// removeFiles menghapus file bukti pembayaran milik user yang sudah dihapus permanen.
+// File yang sudah tidak ada dilewati.
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[PURGE] Gagal hapus file %s: %v", path, err)
+		}
+	}
+}
+
+// StartTrashPurge menjalankan PurgeTrash di background setiap interval
+func StartTrashPurge(repos *repository.Repositories, interval time.Duration) {
+	go func() {
+		for {
+			if err := PurgeTrash(repos, time.Now()); err != nil {
+				log.Printf("[PURGE] Gagal membersihkan tong sampah: %v", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
@@ -4,11 +4,13 @@ import (
 	"log"
 	"backend-gin/database"
 	"backend-gin/handlers"
-	"backend-gin/middleware"
-	"github.com/gin-gonic/gin"
+	"backend-gin/jobs"
+	"backend-gin/repository"
+	"backend-gin/router"
+	"backend-gin/services"
 	"github.com/joho/godotenv"
-	"github.com/gin-contrib/cors"
 	"os"
+	"time"
 
 )
 
@@ -25,77 +27,48 @@ func main() {
 
 	
 
-	database.ConnectDatabase()
-
-	r := gin.Default()
-
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "ngrok-skip-browser-warning"}
-	r.Use(cors.New(config))
-
-	// Public Routes
-	r.POST("/login", handlers.Login)
-	r.POST("/register", handlers.Register) // Dulu register-admin, sekarang register umum
-	r.POST("/telegram/webhook", handlers.TelegramWebhook)
-	r.POST("/setup-owner", handlers.RegisterOwner)
-	cwd, _ := os.Getwd()
-log.Println("CWD:", cwd)
-
-uploadDir := "/home/ubuntu/moneybot/uploads"
-log.Println("Serving uploads from:", uploadDir)
+	// Subcommand CLI: go run . migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+	// Subcommand CLI: go run . repair-transactions [--fix]
+	if len(os.Args) > 1 && os.Args[1] == "repair-transactions" {
+		os.Exit(runRepairTransactionsCommand(os.Args[2:]))
+	}
 
-r.Static("/uploads", uploadDir)
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatal(err)
+	}
 
+	// Jalankan migration yang belum dijalankan (matikan dengan AUTO_MIGRATE=false
+	// kalau migration dijalankan terpisah lewat "migrate up")
+	if os.Getenv("AUTO_MIGRATE") != "false" {
+		results, err := database.MigrateUp(db, false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, r := range results {
+			log.Printf("[MIGRATE] %s (%s)", r.ID, r.Description)
+		}
+	}
 
+	// Susun dependency: database -> repository -> service -> handler -> router
+	repos := repository.New(db)
+	h := handlers.New(repos, services.New(repos))
 
+	// Hapus permanen isi tong sampah yang sudah lewat masa simpan (cek tiap jam)
+	jobs.StartTrashPurge(repos, time.Hour)
 
-	// Protected Routes (Butuh Token)
-	api := r.Group("/api")
-	api.Use(middleware.JwtAuthMiddleware())
+	// Laporan Excel bulan lalu ke Telegram untuk user yang menyalakan laporan bulanan (cek tiap jam)
+	jobs.StartMonthlyReports(h.SendMonthlyReports, time.Hour)
 
-    // 1. ROUTE BEBAS (Verify Payment bisa diakses walau status Suspended)
-    // Diletakkan LANGSUNG di bawah 'api', sebelum middleware 'RequireActiveOrTrial'
-	api.POST("/verify-payment", handlers.VerifyPayment)
-	api.POST("/manual-payment", handlers.ManualPaymentUpload)
-	
+	cwd, _ := os.Getwd()
+	log.Println("CWD:", cwd)
 
-    // 2. ROUTE KETAT (Butuh Token + Status Active/Trial)
-    // Kita buat grup baru 'strictApi' yang menerapkan middleware tambahan
-	strictApi := api.Group("/")
-    // strictApi.Use(middleware.RequireActiveOrTrial())
-	{
-		// Fitur User Biasa
-		strictApi.GET("/transactions", handlers.GetTransactions)
-		strictApi.GET("/summary", handlers.GetSummary)
-		strictApi.GET("/chart/daily", handlers.GetDailyChart)
-		strictApi.GET("/categories", handlers.GetCategorySummary)
-		strictApi.GET("/user/settings", handlers.GetUserSettings)
-		strictApi.PUT("/user/settings", handlers.UpdateUserSettings)
-		strictApi.GET("/export", handlers.ExportExcel)
-
-		strictApi.POST("/transactions", handlers.CreateTransaction) // Input Data
-		strictApi.GET("/transactions/today", handlers.GetTodayTransactions) // Data Hari Ini
-		strictApi.DELETE("/transactions/:id", handlers.DeleteTransaction) // Hapus Data
-		strictApi.PUT("/user/profile", handlers.UpdateUserProfile)
-
-		// Fitur Super Admin (BARU)
-		// Aksesnya nanti: POST /api/admin/users
-		admin := strictApi.Group("/admin")
-		{
-			admin.GET("/users", handlers.GetAllUsers)      // Lihat semua user
-			admin.POST("/users", handlers.CreateUser)      // Tambah user baru
-			admin.DELETE("/users/:id", handlers.DeleteUser) // Hapus user
-
-			admin.GET("/users/:id/stats", handlers.GetUserStats) // Get Detail
-			admin.PUT("/users/:id", handlers.UpdateUser)         // Edit User
-			admin.PATCH("/users/:id/status", handlers.UpdateUserStatus) // Edit Status/Trial
-			admin.GET("/payments", handlers.GetRecentPayments) // <--- ROUTE BARU
-			admin.DELETE("/payments/:id", handlers.DeletePaymentLog) // Hapus Satu
-        admin.DELETE("/payments", handlers.DeleteAllPaymentLogs) // Hapus Semua
-		
-		}
-	}
+	uploadDir := "/home/ubuntu/moneybot/uploads"
+	log.Println("Serving uploads from:", uploadDir)
 
-r.Run("0.0.0.0:8080")
-}
\ No newline at end of file
+	r := router.New(h, repos.Users, uploadDir)
+	r.Run("0.0.0.0:8080")
+}
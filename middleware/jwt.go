@@ -1,10 +1,8 @@
 package middleware
 
 import (
-	"backend-gin/database"
-	"backend-gin/models"
+	"backend-gin/repository"
 	"backend-gin/utils"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +10,11 @@ import (
 )
 
 // Middleware 1: HANYA Cek Apakah Token Valid (Tanpa Cek Status)
-func JwtAuthMiddleware() gin.HandlerFunc {
+func JwtAuthMiddleware(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Butuh token akses!"})
-			c.Abort()
+			utils.AbortWithError(c, utils.ErrTokenRequired)
 			return
 		}
 
@@ -27,26 +24,36 @@ func JwtAuthMiddleware() gin.HandlerFunc {
 		})
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid"})
-			c.Abort()
+			utils.AbortWithError(c, utils.ErrTokenInvalid)
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token invalid"})
-			c.Abort()
+			utils.AbortWithError(c, utils.ErrTokenInvalid)
 			return
 		}
 
 		// FIX: Pastikan konversi float64 ke uint aman
 		userIDFloat, okID := claims["user_id"].(float64)
 		if !okID {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token corrupt (user_id)"})
-			c.Abort()
+			utils.AbortWithError(c, utils.ErrTokenInvalid)
 			return
 		}
 		
+		// Tolak token yang dibuat sebelum password di-reset (versi sesi sudah naik)
+		// Token lama tanpa claim "sv" dianggap versi 0
+		sessionVersion, _ := claims["sv"].(float64)
+		user, err := users.FindByID(uint(userIDFloat))
+		if err != nil {
+			utils.AbortWithError(c, utils.ErrSessionExpired.Wrap(err))
+			return
+		}
+		if user.SessionVersion != uint(sessionVersion) {
+			utils.AbortWithError(c, utils.ErrSessionExpired)
+			return
+		}
+
 		// KONSISTENSI KEY: Gunakan "user_id" (snake_case) di seluruh aplikasi
 		c.Set("user_id", uint(userIDFloat))
 
@@ -62,24 +69,24 @@ func JwtAuthMiddleware() gin.HandlerFunc {
 }
 
 // Middleware 2: Penjaga Pintu Dashboard (Cek Status)
-func RequireActiveOrTrial() gin.HandlerFunc {
+func RequireActiveOrTrial(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Ambil pakai key yang SAMA dengan middleware di atas
 		userID, exists := c.Get("user_id")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			utils.AbortWithError(c, utils.ErrUnauthorized)
 			return
 		}
 
-		var user models.User
-		if err := database.DB.First(&user, userID).Error; err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		user, err := users.FindByID(userID.(uint))
+		if err != nil {
+			utils.AbortWithError(c, utils.ErrUnauthorized.Wrap(err))
 			return
 		}
 
 		// Skenario: User Suspended
 		if user.Status == "suspended" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Masa trial berakhir. Silakan lakukan pembayaran."})
+			utils.AbortWithError(c, utils.ErrTrialExpired)
 			return
 		}
 
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"backend-gin/database"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `Pemakaian:
+  go run . migrate up [--dry-run]        Jalankan semua migration yang belum dijalankan
+  go run . migrate down [N] [--dry-run]  Rollback N migration terakhir (default 1)
+  go run . migrate status                Lihat migration yang sudah / belum dijalankan`
+
+// Subcommand "migrate": kelola skema database tanpa menyalakan server
+func runMigrateCommand(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "tampilkan SQL tanpa mengubah database")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, migrateUsage) }
+
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	// Flag boleh ditaruh sebelum/sesudah angka: "down 2 --dry-run" atau "down --dry-run 2"
+	command := args[0]
+	var positional []string
+	rest := args[1:]
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var results []database.MigrationResult
+	switch command {
+	case "up":
+		results, err = database.MigrateUp(db, *dryRun)
+	case "down":
+		steps := 1
+		if len(positional) > 0 {
+			steps, err = strconv.Atoi(positional[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "jumlah langkah tidak valid: %s\n", positional[0])
+				return 2
+			}
+		}
+		results, err = database.MigrateDown(db, steps, *dryRun)
+	case "status":
+		return printMigrationStatus(db)
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	for _, r := range results {
+		fmt.Printf("== %s %s (%s)\n", command, r.ID, r.Description)
+		if *dryRun {
+			for _, sql := range r.SQL {
+				fmt.Printf("   %s;\n", sql)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch {
+	case *dryRun:
+		fmt.Printf("Dry run: %d migration, database tidak diubah.\n", len(results))
+	case len(results) == 0:
+		fmt.Println("Tidak ada migration yang perlu dijalankan.")
+	default:
+		fmt.Printf("Selesai: %d migration.\n", len(results))
+	}
+	return 0
+}
+
+func printMigrationStatus(db *gorm.DB) int {
+	states, err := database.MigrationStatus(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, s := range states {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-40s %-20s %s\n", s.ID, applied, s.Description)
+	}
+	return 0
+}
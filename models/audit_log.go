@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrAuditLogImmutable = errors.New("audit log bersifat append-only, tidak boleh diubah/dihapus")
+
+// AuditLog mencatat siapa melakukan apa terhadap data apa (append-only)
+type AuditLog struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ActorID       *uint     `gorm:"index" json:"actor_id"` // NULL = belum login (mis. gagal login, lupa password)
+	ActorUsername string    `json:"actor_username"`        // Snapshot username saat aksi dilakukan
+	ActorRole     string    `json:"actor_role"`
+	Action        string    `gorm:"index" json:"action"` // Contoh: admin.user.delete, auth.login
+	TargetType    string    `gorm:"index" json:"target_type"`
+	TargetID      string    `gorm:"index" json:"target_id"`
+	Before        string    `json:"before"`  // JSON snapshot sebelum perubahan
+	After         string    `json:"after"`   // JSON snapshot sesudah perubahan
+	Changes       string    `json:"changes"` // JSON diff: {"field": {"from": .., "to": ..}}
+	IP            string    `json:"ip"`
+	UserAgent     string    `json:"user_agent"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// Tolak update & delete lewat GORM supaya log tidak bisa diutak-atik dari aplikasi
+func (a *AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+func (a *AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ExchangeRate: kurs yang diisi admin. 1 BaseCurrency = Rate QuoteCurrency,
+// berlaku mulai EffectiveDate sampai ada kurs yang lebih baru untuk pasangan yang sama.
+type ExchangeRate struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	BaseCurrency  string    `gorm:"size:3;uniqueIndex:idx_exchange_rates_pair_date" json:"base_currency"`
+	QuoteCurrency string    `gorm:"size:3;uniqueIndex:idx_exchange_rates_pair_date" json:"quote_currency"`
+	Rate          string    `gorm:"size:40" json:"rate"` // Desimal persis (dibaca pakai big.Rat), bukan float
+	EffectiveDate time.Time `gorm:"uniqueIndex:idx_exchange_rates_pair_date" json:"effective_date"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
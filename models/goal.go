@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// GoalCategory: kategori transaksi setoran ke target yang tertaut dompet
+const GoalCategory = "Tabungan"
+
+// SavingsGoal: target tabungan. Isinya tidak disimpan di sini, tapi dihitung dari transaksi:
+// saldo dompet tertaut (masuk - keluar), atau kategori tertaut (keluar - masuk sejak target dibuat,
+// mis. uang yang disisihkan dengan kategori "Tabungan"). Salah satu dari WalletID / Category wajib diisi.
+type SavingsGoal struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"index" json:"user_id"`
+	Name         string     `gorm:"size:100" json:"name"`
+	TargetAmount int64      `json:"target_amount"`          // Minor unit dalam Currency
+	Currency     string     `gorm:"size:3" json:"currency"` // Mata uang dompet, atau mata uang dasar user
+	Deadline     *time.Time `json:"deadline"`               // Opsional: tengah malam hari terakhir (zona waktu user)
+	WalletID     *uint      `gorm:"index" json:"wallet_id"`
+	Category     string     `gorm:"size:100" json:"category"`
+	ReachedAt    *time.Time `json:"reached_at"` // Pertama kali tercapai (ucapan selamat cukup sekali)
+	CreatedAt    time.Time  `json:"created_at"`
+
+	// Relasi
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
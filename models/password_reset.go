@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PasswordReset menyimpan kode reset password sekali pakai yang dikirim lewat bot Telegram
+type PasswordReset struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"index" json:"user_id"`
+	CodeHash  string     `json:"-"`        // Hash SHA-256 dari kode, kode asli tidak pernah disimpan
+	Attempts  int        `json:"attempts"` // Jumlah percobaan kode yang salah
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"` // NULL = belum dipakai
+	CreatedAt time.Time  `json:"created_at"`
+	IP        string     `gorm:"size:45;index" json:"-"` // IP yang meminta kode (batas permintaan per IP)
+
+	// Relasi
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// BankStatement: rekening koran (e-statement) yang diupload user untuk dicocokkan dengan catatan di aplikasi
+type BankStatement struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"index" json:"user_id"`
+	Bank          string    `gorm:"size:20" json:"bank"` // bca / mandiri
+	AccountNumber string    `gorm:"size:40" json:"account_number"`
+	FileName      string    `gorm:"size:255" json:"file_name"`
+	Currency      string    `gorm:"size:3" json:"currency"`
+	PeriodFrom    time.Time `json:"period_from"`
+	PeriodTo      time.Time `json:"period_to"` // Hari terakhir periode (inklusif)
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relasi
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// StatementEntry: satu mutasi di rekening koran.
+// TransactionID terisi kalau user menautkan mutasi ini ke transaksi (atau membuat transaksi dari mutasi ini).
+type StatementEntry struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	StatementID   uint            `gorm:"index" json:"statement_id"`
+	Line          int             `json:"line"` // Nomor baris di file (CSV) / urutan mutasi (PDF)
+	PostedAt      time.Time       `json:"posted_at"`
+	Description   string          `gorm:"size:255" json:"description"`
+	Type          TransactionType `gorm:"size:10" json:"type"`
+	Amount        int64           `json:"amount"` // Minor unit, selalu positif
+	Currency      string          `gorm:"size:3" json:"currency"`
+	Balance       *int64          `json:"balance"` // Saldo setelah mutasi, kalau tercetak di file
+	Pending       bool            `json:"pending"` // Mutasi yang belum dibukukan bank (PEND di BCA)
+	TransactionID *uint           `gorm:"index" json:"transaction_id"`
+
+	// Relasi
+	Statement BankStatement `gorm:"foreignKey:StatementID" json:"-"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// Tag: label bebas lintas kategori (mis. #liburan-bali, #kantor), diambil dari hashtag di catatan transaksi.
+// Nama selalu huruf kecil tanpa '#', unik per user.
+type Tag struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"uniqueIndex:idx_tags_user_name,priority:1" json:"-"`
+	Name   string `gorm:"size:50;uniqueIndex:idx_tags_user_name,priority:2" json:"name"`
+
+	// Relasi
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TransactionTag: tabel penghubung many-to-many transaksi <-> tag
+type TransactionTag struct {
+	TransactionID uint `gorm:"primaryKey"`
+	TagID         uint `gorm:"primaryKey;index"`
+}
@@ -1,15 +1,55 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TransactionType: jenis transaksi. Nilai lain ditolak di aplikasi & lewat CHECK constraint di database.
+type TransactionType string
+
+const (
+	TypeIncome  TransactionType = "income"
+	TypeExpense TransactionType = "expense"
+)
+
+// CategoryMaxLength: batas panjang nama kategori (dalam karakter)
+const CategoryMaxLength = 50
+
+// Valid: true kalau termasuk jenis transaksi yang dikenal
+func (t TransactionType) Valid() bool {
+	return t == TypeIncome || t == TypeExpense
+}
 
 type Transaction struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `json:"user_id"`   // Baru: Penanda pemilik data
-	Amount    int       `json:"amount"`
-	Type      string    `json:"type"`
+	UserID    uint      `gorm:"index:idx_transactions_user_created,priority:1" json:"user_id"` // Penanda pemilik data
+	Amount    int64     `json:"amount"`                                  // Minor unit (lihat package money)
+	Currency  string    `gorm:"size:3;default:'IDR'" json:"currency"` // Kode ISO 4217
+	WalletID  *uint     `gorm:"index" json:"wallet_id"`               // Opsional: dompet asal/tujuan
+	Type      TransactionType `json:"type"`
 	Category  string    `json:"category"`
 	Note      string    `json:"note"`
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time `gorm:"index:idx_transactions_user_created,priority:2" json:"created_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"` // Soft delete: masuk tong sampah dulu
+	// Split: rincian per kategori (kosong = transaksi biasa). Category induk = kategori baris terbesar.
+	Splits []TransactionSplit `gorm:"foreignKey:TransactionID" json:"splits,omitempty"`
+	// Tag dari hashtag di Note (diisi otomatis saat disimpan)
+	Tags []Tag `gorm:"many2many:transaction_tags" json:"tags,omitempty"`
 	// Optional: Relasi ke User (biar GORM tahu)
 	User User `gorm:"foreignKey:UserID" json:"-"`
-}
\ No newline at end of file
+}
+
+// SplitMinLines: transaksi split minimal punya 2 baris rincian
+const SplitMinLines = 2
+
+// TransactionSplit: satu baris rincian transaksi split (mis. struk belanja: Belanja + Jajan).
+// Jumlah semua baris = Amount transaksi induknya, mata uang & tanggal ikut induk.
+type TransactionSplit struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	TransactionID uint   `gorm:"index" json:"transaction_id"`
+	Category      string `json:"category"`
+	Note          string `json:"note"`
+	Amount        int64  `json:"amount"` // Minor unit, mata uang transaksi induk
+}
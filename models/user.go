@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
@@ -20,8 +24,33 @@ type User struct {
 	TrialEndsAt  time.Time `json:"trial_ends_at"` // Kapan trial berakhir
 
 	// Settingan Budget (Fitur Lama)
-	DailyLimit   int       `json:"daily_limit"`
+	DailyLimit   int64     `json:"daily_limit"` // Minor unit, dalam BaseCurrency
 	AlertMessage string    `json:"alert_message"`
+
+	// Mata uang utama: semua ringkasan, grafik & limit harian dihitung dalam mata uang ini
+	BaseCurrency string    `json:"base_currency" gorm:"size:3;default:'IDR'"`
+
+	// Tanggal awal "bulan" untuk laporan (mis. tanggal gajian), 1-28
+	MonthStartDay int      `json:"month_start_day" gorm:"default:1"`
+
+	// Zona waktu IANA: batas hari/bulan untuk limit harian, grafik & laporan
+	Timezone     string    `json:"timezone" gorm:"size:64;default:'Asia/Jakarta'"`
+
+	// Laporan Excel bulan lalu dikirim otomatis lewat bot tiap awal bulan
+	MonthlyReport bool `json:"monthly_report" gorm:"default:false"`
+	// Awal periode laporan bulanan terakhir yang sudah dikirim (2006-01-02), supaya tidak terkirim dua kali
+	LastMonthlyReport string `json:"-" gorm:"size:10"`
+
+	// Bahasa untuk balasan bot ('id' atau 'en')
+	Language     string    `json:"language" gorm:"default:'id'"`
+
+	// Versi sesi: dinaikkan saat password di-reset supaya semua token JWT lama ditolak
+	SessionVersion uint `json:"-" gorm:"default:0"`
 	
 	CreatedAt    time.Time `json:"created_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"` // Soft delete: bisa di-restore admin sebelum dihapus permanen
+
+	// Permintaan hapus akun dari user sendiri: akun + semua datanya dihapus permanen setelah waktu ini.
+	// NULL = tidak ada permintaan. Selama masa tunggu akun tetap bisa dipakai & permintaan bisa dibatalkan.
+	DeleteAfter *time.Time `gorm:"index" json:"delete_after"`
 }
\ No newline at end of file
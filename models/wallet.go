@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Wallet: dompet / rekening milik user, masing-masing punya satu mata uang
+type Wallet struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	Name      string    `gorm:"size:100" json:"name"`
+	Currency  string    `gorm:"size:3" json:"currency"` // Kode ISO 4217
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relasi
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,220 @@
+// Package money: nominal uang disimpan sebagai int64 dalam satuan terkecil (minor unit)
+// mata uangnya, mis. sen untuk USD. Tidak pernah pakai float untuk menyimpan / menghitung.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency: mata uang data lama & default user baru
+const DefaultCurrency = "IDR"
+
+var (
+	ErrInvalidAmount = errors.New("nominal tidak valid")
+	ErrInvalidRate   = errors.New("kurs tidak valid")
+	// ErrAmountTooLarge: hasil hitungan di luar jangkauan int64 (mis. kurs yang sangat besar)
+	ErrAmountTooLarge = errors.New("nominal terlalu besar")
+)
+
+// Currency: kode ISO 4217 + jumlah digit desimal (minor unit)
+type Currency struct {
+	Code     string
+	Exponent int
+	Symbol   string
+}
+
+// Mata uang yang didukung. IDR sengaja tanpa desimal (sen tidak dipakai lagi),
+// jadi data lama yang tersimpan dalam Rupiah utuh tetap bernilai sama.
+var currencies = map[string]Currency{
+	"IDR": {Code: "IDR", Exponent: 0, Symbol: "Rp"},
+	"USD": {Code: "USD", Exponent: 2, Symbol: "US$"},
+	"EUR": {Code: "EUR", Exponent: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", Exponent: 2, Symbol: "£"},
+	"SGD": {Code: "SGD", Exponent: 2, Symbol: "S$"},
+	"MYR": {Code: "MYR", Exponent: 2, Symbol: "RM"},
+	"AUD": {Code: "AUD", Exponent: 2, Symbol: "A$"},
+	"CNY": {Code: "CNY", Exponent: 2, Symbol: "CN¥"},
+	"SAR": {Code: "SAR", Exponent: 2, Symbol: "SAR"},
+	"THB": {Code: "THB", Exponent: 2, Symbol: "฿"},
+	"JPY": {Code: "JPY", Exponent: 0, Symbol: "¥"},
+	"KRW": {Code: "KRW", Exponent: 0, Symbol: "₩"},
+}
+
+// Lookup mencari mata uang (tidak peka huruf besar/kecil)
+func Lookup(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// Normalize mengembalikan kode baku (huruf besar), atau "" kalau tidak didukung
+func Normalize(code string) string {
+	c, ok := Lookup(code)
+	if !ok {
+		return ""
+	}
+	return c.Code
+}
+
+// Codes: semua kode mata uang yang didukung, urut abjad
+func Codes() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func exponent(code string) int {
+	if c, ok := Lookup(code); ok {
+		return c.Exponent
+	}
+	return 0
+}
+
+// Parse mengubah input user ("100.000", "12,50", "1,234.56") jadi minor unit.
+//
+// Mata uang tanpa desimal (IDR): semua titik/koma dianggap pemisah ribuan, sama seperti dulu.
+// Mata uang dengan desimal: pemisah TERAKHIR dianggap desimal kalau digit di belakangnya
+// tidak lebih dari jumlah desimal mata uang itu ("1.234" USD = 1234.00, "12.5" USD = 12.50).
+func Parse(input, code string) (int64, error) {
+	s := strings.ReplaceAll(strings.TrimSpace(input), " ", "")
+	exp := exponent(code)
+
+	whole, frac := s, ""
+	if exp > 0 {
+		if i := strings.LastIndexAny(s, ".,"); i >= 0 {
+			digits := len(s) - i - 1
+			sep := s[i]
+			// "1.234.567" = ribuan semua, "1,234.5" = desimal
+			if digits >= 1 && digits <= exp && strings.Count(s, string(sep)) == 1 {
+				whole, frac = s[:i], s[i+1:]
+			}
+		}
+	}
+	whole = strings.NewReplacer(".", "", ",", "").Replace(whole)
+	if (whole == "" && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+	// Batas aman int64 (±9,2 x 10^18): 15 digit sudah lebih dari cukup
+	if len(whole) > 15 {
+		return 0, ErrInvalidAmount
+	}
+
+	frac += strings.Repeat("0", exp-len(frac))
+	var amount int64
+	for _, ch := range whole + frac {
+		amount = amount*10 + int64(ch-'0')
+	}
+	return amount, nil
+}
+
+// ParseDecimal membaca angka dari file bank / spreadsheet ("-1,234.56", "50000.00"):
+// titik selalu desimal, koma pemisah ribuan, boleh bertanda minus.
+// Digit desimal yang lebih dari minor unit dibulatkan (IDR "50000.50" = 50001).
+func ParseDecimal(input, code string) (int64, error) {
+	s := strings.NewReplacer(" ", "", ",", "").Replace(strings.TrimSpace(input))
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if (whole == "" && frac == "") || !isDigits(whole) || !isDigits(frac) || len(whole) > 15 {
+		return 0, ErrInvalidAmount
+	}
+	v, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+	v.Mul(v, new(big.Rat).SetInt(pow10(exponent(code))))
+	return roundHalfAway(v)
+}
+
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal: minor unit jadi teks desimal tanpa simbol, mis. 1250 USD -> "12.50"
+func Decimal(amount int64, code string) string {
+	exp := exponent(code)
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	pow := int64(1)
+	for i := 0; i < exp; i++ {
+		pow *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/pow, exp, amount%pow)
+}
+
+// Format untuk ditampilkan ke user, mis. "Rp 25000" / "US$ 12.50"
+func Format(amount int64, code string) string {
+	symbol := strings.ToUpper(code)
+	if c, ok := Lookup(code); ok {
+		symbol = c.Symbol
+	}
+	return symbol + " " + Decimal(amount, code)
+}
+
+// Major: nilai dalam satuan utama (untuk angka di Excel / grafik, BUKAN untuk disimpan)
+func Major(amount int64, code string) float64 {
+	f, _ := new(big.Rat).SetFrac(big.NewInt(amount), pow10(exponent(code))).Float64()
+	return f
+}
+
+// ParseRate membaca kurs desimal positif ("16250", "0.0000615") secara presisi
+func ParseRate(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "/eE") {
+		return nil, ErrInvalidRate
+	}
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return rate, nil
+}
+
+// Convert mengubah minor unit mata uang "from" ke minor unit "to".
+// rate = berapa satuan utama "to" untuk 1 satuan utama "from" (mis. USD->IDR = 16250).
+// Hasil dibulatkan ke minor unit terdekat (setengah menjauhi nol), ErrAmountTooLarge kalau tidak muat di int64.
+func Convert(amount int64, from, to string, rate *big.Rat) (int64, error) {
+	v := new(big.Rat).SetInt64(amount)
+	v.Mul(v, rate)
+	v.Mul(v, new(big.Rat).SetFrac(pow10(exponent(to)), pow10(exponent(from))))
+	return roundHalfAway(v)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundHalfAway: bulatkan ke bilangan bulat terdekat, setengah menjauhi nol
+func roundHalfAway(v *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(v.Num())
+	den := v.Denom()
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	// sisa*2 >= penyebut berarti bulatkan ke atas
+	if r.Mul(r, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	// Int64() diam-diam memotong nilai di luar jangkauan, jadi dicek dulu
+	if !q.IsInt64() {
+		return 0, ErrAmountTooLarge
+	}
+	return q.Int64(), nil
+}
@@ -0,0 +1,156 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		input, code string
+		want        int64
+		wantErr     bool
+	}{
+		{"100.000", "IDR", 100000, false},
+		{"100,000", "IDR", 100000, false},
+		{"50000", "IDR", 50000, false},
+		{"12.50", "USD", 1250, false},
+		{"12,5", "USD", 1250, false},
+		{"1,234.56", "USD", 123456, false},
+		{"1.234,56", "EUR", 123456, false},
+		{"1.234", "USD", 123400, false},
+		{"1.234.567", "USD", 123456700, false},
+		{"7", "USD", 700, false},
+		{"1500", "JPY", 1500, false},
+		{"", "IDR", 0, true},
+		{".", "USD", 0, true},
+		{"abc", "IDR", 0, true},
+		{"-5", "IDR", 0, true},
+		{"1234567890123456", "IDR", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := Parse(tc.input, tc.code)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("Parse(%q, %s) error = %v, mau error %v", tc.input, tc.code, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Parse(%q, %s) = %d, mau %d", tc.input, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	cases := []struct {
+		input, code string
+		want        int64
+		wantErr     bool
+	}{
+		{"50000.00", "IDR", 50000, false},
+		{"-50000.00", "IDR", -50000, false},
+		{"50000.50", "IDR", 50001, false},
+		{"1,234.56", "USD", 123456, false},
+		{"-12.5", "USD", -1250, false},
+		{"+7", "USD", 700, false},
+		{"0.005", "USD", 1, false},
+		{"", "IDR", 0, true},
+		{"1e5", "IDR", 0, true},
+		{"1/2", "USD", 0, true},
+		{"12.3.4", "USD", 0, true},
+		{"--5", "IDR", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := ParseDecimal(tc.input, tc.code)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseDecimal(%q, %s) error = %v, mau error %v", tc.input, tc.code, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseDecimal(%q, %s) = %d, mau %d", tc.input, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestDecimalAndFormat(t *testing.T) {
+	if got := Decimal(1205, "USD"); got != "12.05" {
+		t.Errorf("Decimal = %q", got)
+	}
+	if got := Decimal(-5, "USD"); got != "-0.05" {
+		t.Errorf("Decimal negatif = %q", got)
+	}
+	if got := Format(25000, "IDR"); got != "Rp 25000" {
+		t.Errorf("Format IDR = %q", got)
+	}
+	if got := Format(1250, "usd"); got != "US$ 12.50" {
+		t.Errorf("Format USD = %q", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	rate := func(s string) *big.Rat {
+		r, err := ParseRate(s)
+		if err != nil {
+			t.Fatalf("ParseRate(%q): %v", s, err)
+		}
+		return r
+	}
+
+	cases := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     string
+		want     int64
+	}{
+		{"USD->IDR", 1250, "USD", "IDR", "16250", 203125},     // 12.50 USD x 16250 = 203125 IDR
+		{"IDR->USD", 100000, "IDR", "USD", "0.0000615", 615},  // 100000 IDR x 0.0000615 = 6.15 USD
+		{"pembulatan ke atas", 1, "IDR", "USD", "0.005", 1},   // 0.005 -> 0.01
+		{"pembulatan negatif", -1, "IDR", "USD", "0.005", -1}, // -0.005 -> -0.01
+		{"di bawah setengah", 1, "IDR", "USD", "0.0049", 0},   // 0.0049 -> 0.00
+		{"batas atas int64", math.MaxInt64, "IDR", "IDR", "1", math.MaxInt64},
+		{"batas bawah int64", math.MinInt64, "IDR", "IDR", "1", math.MinInt64},
+		// Kurs dengan banyak desimal tetap presisi (tidak lewat float)
+		{"konversi besar", 1000000000000, "IDR", "USD", "0.000061538461538", 6153846154},
+	}
+	for _, tc := range cases {
+		got, err := Convert(tc.amount, tc.from, tc.to, rate(tc.rate))
+		if err != nil || got != tc.want {
+			t.Errorf("%s: Convert = %d, %v, mau %d", tc.name, got, err, tc.want)
+		}
+	}
+}
+
+func TestConvertOverflow(t *testing.T) {
+	cases := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     string
+	}{
+		// 10^15 Rupiah x 16250 = 1,6 x 10^19 > MaxInt64: dulu terpotong diam-diam jadi angka acak
+		{"kurs besar", 1000000000000000, "IDR", "IDR", "16250"},
+		{"negatif", -1000000000000000, "IDR", "IDR", "16250"},
+		// 10^17 JPY jadi USD: kurs kecil, tapi minor unit tujuan x100
+		{"minor unit tujuan", 100000000000000000, "JPY", "USD", "1"},
+		{"lewat satu", math.MaxInt64, "IDR", "IDR", "1.0000000000000000001"},
+	}
+	for _, tc := range cases {
+		rate, err := ParseRate(tc.rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := Convert(tc.amount, tc.from, tc.to, rate); !errors.Is(err, ErrAmountTooLarge) {
+			t.Errorf("%s: Convert = %d, %v, mau ErrAmountTooLarge", tc.name, got, err)
+		}
+	}
+}
+
+func TestParseRateRejectsInvalid(t *testing.T) {
+	for _, s := range []string{"", "0", "-1", "abc", "1/3", "1e5"} {
+		if _, err := ParseRate(s); err == nil {
+			t.Errorf("ParseRate(%q) harusnya error", s)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"backend-gin/database"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const repairUsage = `Pemakaian:
+  go run . repair-transactions          Laporkan transaksi yang melanggar aturan (tanpa mengubah data)
+  go run . repair-transactions --fix    Laporkan lalu perbaiki`
+
+// Subcommand "repair-transactions": bereskan data lama sebelum migration CHECK constraint
+func runRepairTransactionsCommand(args []string) int {
+	fs := flag.NewFlagSet("repair-transactions", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "perbaiki data (default hanya laporan)")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, repairUsage) }
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	issues, err := database.RepairTransactions(db, *fix)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	switch {
+	case len(issues) == 0:
+		fmt.Println("Semua transaksi valid.")
+	case *fix:
+		fmt.Printf("Selesai: %d transaksi diperbaiki.\n", len(issues))
+	default:
+		fmt.Printf("%d transaksi tidak valid. Jalankan ulang dengan --fix untuk memperbaiki.\n", len(issues))
+		return 1
+	}
+	return 0
+}
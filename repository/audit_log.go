@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"backend-gin/models"
+
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *auditLogRepository) filtered(filter AuditFilter) *gorm.DB {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", dbTime(*filter.From))
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", dbTime(*filter.To))
+	}
+	return query
+}
+
+func (r *auditLogRepository) List(filter AuditFilter, page, limit int) ([]models.AuditLog, int64, error) {
+	query := r.filtered(filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order("created_at desc, id desc").Limit(limit).Offset((page - 1) * limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// FindInBatches selalu urut berdasarkan ID (kronologis)
+func (r *auditLogRepository) Each(filter AuditFilter, batchSize int, fn func(batch []models.AuditLog) error) error {
+	var batch []models.AuditLog
+	return r.filtered(filter).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend-gin/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) List() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Order("base_currency asc, quote_currency asc, effective_date desc").Find(&rates).Error
+	return rates, err
+}
+
+func (r *exchangeRateRepository) FindByID(id uint) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.First(&rate, id).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) Save(rate *models.ExchangeRate) error {
+	var existing models.ExchangeRate
+	err := r.db.Where("base_currency = ? AND quote_currency = ? AND effective_date = ?",
+		rate.BaseCurrency, rate.QuoteCurrency, rate.EffectiveDate).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.db.Create(rate).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	rate.ID = existing.ID
+	rate.CreatedAt = existing.CreatedAt
+	return r.db.Save(rate).Error
+}
+
+func (r *exchangeRateRepository) Delete(rate *models.ExchangeRate) error {
+	return r.db.Delete(rate).Error
+}
+
+func (r *exchangeRateRepository) FindEffective(base, quote string, at time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.Where("base_currency = ? AND quote_currency = ? AND effective_date <= ?", base, quote, at).
+		Order("effective_date desc").First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"backend-gin/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type goalRepository struct {
+	db *gorm.DB
+}
+
+func NewGoalRepository(db *gorm.DB) GoalRepository {
+	return &goalRepository{db: db}
+}
+
+func (r *goalRepository) Create(goal *models.SavingsGoal) error {
+	return r.db.Create(goal).Error
+}
+
+func (r *goalRepository) FindForUser(userID, id uint) (*models.SavingsGoal, error) {
+	var goal models.SavingsGoal
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&goal).Error; err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *goalRepository) ListForUser(userID uint) ([]models.SavingsGoal, error) {
+	var goals []models.SavingsGoal
+	err := r.db.Where("user_id = ?", userID).Order("created_at asc, id asc").Find(&goals).Error
+	return goals, err
+}
+
+func (r *goalRepository) CountInWallet(walletID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SavingsGoal{}).Where("wallet_id = ?", walletID).Count(&count).Error
+	return count, err
+}
+
+func (r *goalRepository) MarkReached(goal *models.SavingsGoal, at time.Time) error {
+	// Hanya kalau belum pernah tercapai, supaya dua request bersamaan tidak sama-sama memberi ucapan selamat
+	result := r.db.Model(&models.SavingsGoal{}).Where("id = ? AND reached_at IS NULL", goal.ID).Update("reached_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	goal.ReachedAt = &at
+	return nil
+}
+
+func (r *goalRepository) Delete(goal *models.SavingsGoal) error {
+	return r.db.Delete(goal).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"backend-gin/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) CountCreatedSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PasswordReset{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *passwordResetRepository) CountFromIPSince(ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PasswordReset{}).
+		Where("ip = ? AND created_at > ?", ip, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *passwordResetRepository) Issue(reset *models.PasswordReset) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Kode lama yang belum dipakai langsung hangus
+		if err := tx.Model(&models.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", reset.CreatedAt).Error; err != nil {
+			return err
+		}
+		return tx.Create(reset).Error
+	})
+}
+
+// Ambil kode terbaru yang masih aktif
+func (r *passwordResetRepository) FindActive(userID uint, now time.Time) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	err := r.db.Where("user_id = ? AND used_at IS NULL AND expires_at > ?", userID, now).
+		Order("created_at desc").First(&reset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+func (r *passwordResetRepository) RecordFailedAttempt(reset *models.PasswordReset, maxAttempts int) error {
+	// Satu statement supaya tebakan salah yang bersamaan tetap terhitung semua (tidak baca-lalu-tulis).
+	// used_at ditulis duluan: MySQL mengevaluasi SET berurutan, jadi attempts di CASE masih nilai lama.
+	res := r.db.Exec(`UPDATE password_resets
+		SET used_at = CASE WHEN attempts + 1 >= ? THEN ? ELSE used_at END, attempts = attempts + 1
+		WHERE id = ? AND used_at IS NULL`, maxAttempts, time.Now(), reset.ID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) Consume(reset *models.PasswordReset, passwordHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Tandai terpakai hanya kalau belum dipakai request lain (single use)
+		res := tx.Model(&models.PasswordReset{}).
+			Where("id = ? AND used_at IS NULL", reset.ID).
+			Update("used_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		// Naikkan versi sesi supaya semua token lama tidak berlaku lagi
+		return tx.Model(&models.User{}).Where("id = ?", reset.UserID).Updates(map[string]interface{}{
+			"password":        passwordHash,
+			"session_version": gorm.Expr("session_version + 1"),
+		}).Error
+	})
+}
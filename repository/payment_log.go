@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"backend-gin/models"
+
+	"gorm.io/gorm"
+)
+
+type paymentLogRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentLogRepository(db *gorm.DB) PaymentLogRepository {
+	return &paymentLogRepository{db: db}
+}
+
+func (r *paymentLogRepository) Create(log *models.PaymentLog) error {
+	return r.db.Create(log).Error
+}
+
+func (r *paymentLogRepository) FindByID(id uint) (*models.PaymentLog, error) {
+	var log models.PaymentLog
+	if err := r.db.First(&log, id).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// Urutkan: Manual Check dulu, baru tanggal terbaru
+func (r *paymentLogRepository) ListRecent(limit int) ([]models.PaymentLog, error) {
+	var logs []models.PaymentLog
+	err := r.db.Order("CASE WHEN detected_bank = 'MANUAL_CHECK' THEN 0 ELSE 1 END, created_at desc").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+func (r *paymentLogRepository) ListAll() ([]models.PaymentLog, error) {
+	var logs []models.PaymentLog
+	err := r.db.Find(&logs).Error
+	return logs, err
+}
+
+func (r *paymentLogRepository) ListForUser(userID uint) ([]models.PaymentLog, error) {
+	var logs []models.PaymentLog
+	err := r.db.Where("user_id = ?", userID).Order("created_at, id").Find(&logs).Error
+	return logs, err
+}
+
+func (r *paymentLogRepository) Delete(log *models.PaymentLog) error {
+	return r.db.Delete(log).Error
+}
+
+// Hapus Semua Data di Tabel (Hard Delete)
+func (r *paymentLogRepository) DeleteAll() error {
+	return r.db.Exec("DELETE FROM payment_logs").Error
+}
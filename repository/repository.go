@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"backend-gin/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound dikembalikan kalau data yang dicari tidak ada (sama dengan gorm.ErrRecordNotFound,
+// jadi errors.Is tetap jalan tanpa handler perlu import gorm)
+var ErrNotFound = gorm.ErrRecordNotFound
+
+// UserRepository: akses data tabel users
+type UserRepository interface {
+	FindByID(id uint) (*models.User, error)
+	FindByUsername(username string) (*models.User, error)
+	FindByTelegramID(telegramID int64) (*models.User, error)
+	List() ([]models.User, error)
+	Create(user *models.User) error
+	Save(user *models.User) error
+	UpdateFields(id uint, fields map[string]interface{}) error
+	// User yang minta laporan bulanan otomatis & sudah menghubungkan Telegram
+	ListMonthlyReport() ([]models.User, error)
+
+	// Tong sampah: user dihapus bersama transaksinya dengan waktu hapus yang sama
+	SoftDelete(id uint, at time.Time) (transactions int64, err error)
+	ListDeleted(since time.Time) ([]models.User, error)
+	FindDeleted(id uint, since time.Time) (*models.User, error)
+	Restore(user *models.User) (transactions int64, err error)
+	PurgeDeletedBefore(cutoff time.Time) (PurgedUsers, error)
+	// PurgeDeletionDue: hapus permanen akun yang minta dihapus dan DeleteAfter-nya sudah lewat
+	PurgeDeletionDue(now time.Time) (PurgedUsers, error)
+}
+
+// PurgedUsers: hasil hapus permanen akun. Files = file bukti pembayaran milik akun tersebut,
+// dihapus dari disk oleh pemanggil setelah database berhasil dibersihkan.
+type PurgedUsers struct {
+	Count int
+	Files []string
+}
+
+// Urutan daftar transaksi
+const (
+	SortDate     = "date"
+	SortAmount   = "amount"
+	SortCategory = "category"
+)
+
+// TransactionCursor: posisi baris terakhir halaman sebelumnya (keyset pagination).
+// Hanya nilai kolom urutan yang dipakai, ditambah ID sebagai pemecah seri.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	Amount    int64
+	Category  string
+	ID        uint
+}
+
+// TransactionFilter: filter untuk daftar transaksi dengan pagination
+type TransactionFilter struct {
+	UserID     uint
+	IDs        []uint // kosong = semua (dipakai operasi massal)
+	Type       models.TransactionType
+	Search     string    // full-text kategori & catatan, semua kata harus ada (awalan kata cukup)
+	Categories []string  // salah satu sama persis (tidak peka huruf besar/kecil)
+	Tags       []string  // punya salah satu tag ini (nama sudah dinormalisasi, lihat utils.NormalizeTag)
+	Currency   string    // kosong = semua mata uang
+	From       time.Time // zero = tanpa batas bawah
+	To         time.Time // zero = tanpa batas atas (eksklusif)
+	// Batas nominal (minor unit, inklusif), 0 = tanpa batas
+	MinAmount int64
+	MaxAmount int64
+
+	Sort string // SortDate (default) / SortAmount / SortCategory
+	Asc  bool   // default terbaru / terbesar dulu
+	// After: lanjut setelah baris ini (keyset). Kalau nil, pakai offset dari Page.
+	After *TransactionCursor
+	Page  int
+	Limit int
+}
+
+// TotalsFilter: agregasi SUM(amount) transaksi user dalam rentang [From, To)
+type TotalsFilter struct {
+	UserID     uint
+	From       time.Time // zero = tanpa batas bawah
+	To         time.Time // zero = tanpa batas atas
+	ByCategory bool
+	ByTag      bool  // per tag; transaksi tanpa tag tidak ikut, yang punya beberapa tag dihitung di tiap tag
+	WalletID   *uint // nil = semua dompet (dan transaksi tanpa dompet)
+	// Baris mata uang selain BaseCurrency dipecah per tanggal (RateDay) supaya bisa
+	// dikonversi dengan kurs harian. Baris BaseCurrency tidak dipecah.
+	BaseCurrency string
+	// Buckets: kalau diisi (minimal 2 batas), total dipecah per rentang [Buckets[i], Buckets[i+1])
+	// dan From/To diganti batas pertama & terakhir. Batas dihitung pemanggil (hari, minggu, bulan
+	// di zona waktu user), jadi hasilnya sama di semua database.
+	Buckets []time.Time
+}
+
+// TransactionTotal: satu baris hasil agregasi
+type TransactionTotal struct {
+	Type     models.TransactionType
+	Category string // kosong kalau tidak dikelompokkan per kategori
+	Tag      string // kosong kalau tidak dikelompokkan per tag
+	Currency string
+	RateDay  string // YYYY-MM-DD, kosong untuk BaseCurrency
+	Bucket   int    // indeks rentang di TotalsFilter.Buckets
+	Total    int64
+	Count    int64
+}
+
+// CategoryChange: transaksi yang kategorinya diganti, Category = kategori lama
+type CategoryChange struct {
+	ID       uint
+	Category string
+}
+
+// TransactionRepository: akses data tabel transactions
+type TransactionRepository interface {
+	Create(trx *models.Transaction) error
+	// CreateBatch: simpan semua atau tidak sama sekali (satu transaksi database)
+	CreateBatch(trx []models.Transaction) error
+	FindForUser(userID, id uint) (*models.Transaction, error)
+	// List: maksimal Limit+1 baris (baris ekstra = masih ada halaman berikutnya) + total semua halaman
+	List(filter TransactionFilter) ([]models.Transaction, int64, error)
+	// FindInPeriod: from/to kosong (zero) berarti tanpa batas
+	FindInPeriod(userID uint, from, to time.Time, newestFirst bool) ([]models.Transaction, error)
+	// EachInPeriod: seperti FindInPeriod terbaru dulu, tapi dibaca per size baris (keyset created_at, id)
+	// dan diserahkan ke fn per batch. Error dari fn menghentikan pembacaan dan dikembalikan.
+	EachInPeriod(userID uint, from, to time.Time, size int, fn func([]models.Transaction) error) error
+	CountInWallet(walletID uint) (int64, error)
+	// ListAllForUser: semua transaksi user termasuk yang ada di tong sampah, urut waktu (arsip data akun)
+	ListAllForUser(userID uint) ([]models.Transaction, error)
+	Totals(filter TotalsFilter) ([]TransactionTotal, error)
+
+	Delete(userID, id uint) (bool, error)
+	// Operasi massal, semua atau tidak sama sekali (satu transaksi database)
+	DeleteMatching(filter TransactionFilter) ([]uint, error)
+	Recategorize(filter TransactionFilter, category string) ([]CategoryChange, error)
+	Restore(userID, id uint, since time.Time) (bool, error)
+	ListDeleted(userID uint, since time.Time) ([]models.Transaction, error)
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+}
+
+// PaymentLogRepository: akses data bukti pembayaran
+type PaymentLogRepository interface {
+	Create(log *models.PaymentLog) error
+	FindByID(id uint) (*models.PaymentLog, error)
+	ListRecent(limit int) ([]models.PaymentLog, error)
+	ListAll() ([]models.PaymentLog, error)
+	ListForUser(userID uint) ([]models.PaymentLog, error)
+	Delete(log *models.PaymentLog) error
+	DeleteAll() error
+}
+
+// PasswordResetRepository: kode reset password sekali pakai
+type PasswordResetRepository interface {
+	CountCreatedSince(userID uint, since time.Time) (int64, error)
+	// CountFromIPSince: jumlah kode yang diminta dari satu IP sejak since (semua user)
+	CountFromIPSince(ip string, since time.Time) (int64, error)
+	// Issue menghanguskan kode lama yang belum dipakai lalu menyimpan kode baru
+	Issue(reset *models.PasswordReset) error
+	FindActive(userID uint, now time.Time) (*models.PasswordReset, error)
+	// RecordFailedAttempt menambah hitungan salah secara atomik; kode hangus begitu mencapai maxAttempts.
+	// ErrNotFound kalau kode sudah dipakai atau hangus.
+	RecordFailedAttempt(reset *models.PasswordReset, maxAttempts int) error
+	// Consume menandai kode terpakai + ganti password + naikkan versi sesi user.
+	// ErrNotFound kalau kode sudah dipakai request lain.
+	Consume(reset *models.PasswordReset, passwordHash string) error
+}
+
+// WalletRepository: dompet milik user
+type WalletRepository interface {
+	Create(wallet *models.Wallet) error
+	FindForUser(userID, id uint) (*models.Wallet, error)
+	ListForUser(userID uint) ([]models.Wallet, error)
+	Delete(wallet *models.Wallet) error
+}
+
+// GoalRepository: target tabungan milik user
+type GoalRepository interface {
+	Create(goal *models.SavingsGoal) error
+	FindForUser(userID, id uint) (*models.SavingsGoal, error)
+	// ListForUser: urut waktu dibuat
+	ListForUser(userID uint) ([]models.SavingsGoal, error)
+	CountInWallet(walletID uint) (int64, error)
+	// MarkReached mengisi ReachedAt kalau masih kosong. ErrNotFound kalau sudah terisi lebih dulu.
+	MarkReached(goal *models.SavingsGoal, at time.Time) error
+	Delete(goal *models.SavingsGoal) error
+}
+
+// StatementRepository: rekening koran bank & mutasinya (rekonsiliasi)
+type StatementRepository interface {
+	// Create menyimpan rekening koran beserta semua mutasinya sekaligus
+	Create(statement *models.BankStatement, entries []models.StatementEntry) error
+	FindForUser(userID, id uint) (*models.BankStatement, error)
+	// ListForUser: terbaru dulu
+	ListForUser(userID uint) ([]models.BankStatement, error)
+	// Entries: mutasi satu rekening koran urut baris file
+	Entries(statementID uint) ([]models.StatementEntry, error)
+	FindEntry(statementID, entryID uint) (*models.StatementEntry, error)
+	// SetEntryTransaction menautkan (atau melepas, kalau nil) mutasi ke transaksi
+	SetEntryTransaction(entryID uint, transactionID *uint) error
+	// LinkEntry menautkan mutasi ke transaksi user yang sudah ada dalam satu DB transaction. ErrNotFound
+	// (tidak ada yang berubah) kalau mutasi sudah ditautkan request lain sejak dibaca, transaksinya sudah
+	// tertaut ke mutasi lain, atau transaksinya sudah tidak ada.
+	LinkEntry(userID uint, entry *models.StatementEntry, transactionID uint) error
+	// CreateEntryTransaction menyimpan transaksi baru & menautkannya ke mutasi dalam satu DB transaction.
+	// ErrNotFound (tidak ada yang tersimpan) kalau mutasi sudah ditautkan request lain sejak dibaca.
+	CreateEntryTransaction(entry *models.StatementEntry, trx *models.Transaction) error
+	// LinkedTransactionIDs: transaksi user yang sudah tertaut ke mutasi rekening koran mana pun
+	LinkedTransactionIDs(userID uint) ([]uint, error)
+	// Delete menghapus rekening koran + mutasinya (transaksi yang tertaut tidak ikut terhapus)
+	Delete(statement *models.BankStatement) error
+}
+
+// ExchangeRateRepository: kurs yang diisi admin
+type ExchangeRateRepository interface {
+	List() ([]models.ExchangeRate, error)
+	FindByID(id uint) (*models.ExchangeRate, error)
+	// Save: simpan kurs baru, atau timpa kurs pasangan + tanggal yang sama
+	Save(rate *models.ExchangeRate) error
+	Delete(rate *models.ExchangeRate) error
+	// FindEffective: kurs terbaru base->quote yang berlaku pada waktu "at"
+	FindEffective(base, quote string, at time.Time) (*models.ExchangeRate, error)
+}
+
+// AuditFilter: filter pencarian audit log
+type AuditFilter struct {
+	ActorID    *uint
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditLogRepository: audit log (append-only)
+type AuditLogRepository interface {
+	Create(entry *models.AuditLog) error
+	List(filter AuditFilter, page, limit int) ([]models.AuditLog, int64, error)
+	// Each membaca per batch urut ID (kronologis), dipakai untuk export
+	Each(filter AuditFilter, batchSize int, fn func(batch []models.AuditLog) error) error
+}
+
+// Repositories mengumpulkan semua repository supaya gampang di-inject
+type Repositories struct {
+	Users          UserRepository
+	Transactions   TransactionRepository
+	PaymentLogs    PaymentLogRepository
+	PasswordResets PasswordResetRepository
+	AuditLogs      AuditLogRepository
+	Wallets        WalletRepository
+	ExchangeRates  ExchangeRateRepository
+	Statements     StatementRepository
+	Goals          GoalRepository
+}
+
+// New membuat semua repository berbasis GORM dari satu koneksi database
+func New(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:          NewUserRepository(db),
+		Transactions:   NewTransactionRepository(db),
+		PaymentLogs:    NewPaymentLogRepository(db),
+		PasswordResets: NewPasswordResetRepository(db),
+		AuditLogs:      NewAuditLogRepository(db),
+		Wallets:        NewWalletRepository(db),
+		ExchangeRates:  NewExchangeRateRepository(db),
+		Statements:     NewStatementRepository(db),
+		Goals:          NewGoalRepository(db),
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"backend-gin/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type statementRepository struct {
+	db *gorm.DB
+}
+
+func NewStatementRepository(db *gorm.DB) StatementRepository {
+	return &statementRepository{db: db}
+}
+
+func (r *statementRepository) Create(statement *models.BankStatement, entries []models.StatementEntry) error {
+	statement.PeriodFrom = dbTime(statement.PeriodFrom)
+	statement.PeriodTo = dbTime(statement.PeriodTo)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(statement).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		for i := range entries {
+			entries[i].StatementID = statement.ID
+			entries[i].PostedAt = dbTime(entries[i].PostedAt)
+		}
+		return tx.CreateInBatches(entries, 200).Error
+	})
+}
+
+func (r *statementRepository) FindForUser(userID, id uint) (*models.BankStatement, error) {
+	var statement models.BankStatement
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&statement).Error; err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *statementRepository) ListForUser(userID uint) ([]models.BankStatement, error) {
+	var statements []models.BankStatement
+	err := r.db.Where("user_id = ?", userID).Order("period_from desc, id desc").Find(&statements).Error
+	return statements, err
+}
+
+func (r *statementRepository) Entries(statementID uint) ([]models.StatementEntry, error) {
+	var entries []models.StatementEntry
+	err := r.db.Where("statement_id = ?", statementID).Order("line asc, id asc").Find(&entries).Error
+	return entries, err
+}
+
+func (r *statementRepository) FindEntry(statementID, entryID uint) (*models.StatementEntry, error) {
+	var entry models.StatementEntry
+	if err := r.db.Where("id = ? AND statement_id = ?", entryID, statementID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *statementRepository) SetEntryTransaction(entryID uint, transactionID *uint) error {
+	return r.db.Model(&models.StatementEntry{}).Where("id = ?", entryID).
+		Update("transaction_id", transactionID).Error
+}
+
+func (r *statementRepository) LinkEntry(userID uint, entry *models.StatementEntry, transactionID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci baris transaksinya dulu supaya dua request yang menautkan transaksi yang sama ke mutasi
+		// berbeda berjalan bergantian (SQLite sudah menulis bergantian, klausa FOR diabaikan)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ? AND user_id = ?", transactionID, userID).First(&models.Transaction{}).Error
+		if err != nil {
+			return err
+		}
+
+		// Cek & tulis dalam satu UPDATE: tautan mutasi masih sama dengan saat dibaca, dan transaksinya
+		// belum tertaut ke mutasi lain. Subquery dibungkus tabel turunan karena MySQL menolak membaca
+		// langsung tabel yang sedang di-UPDATE.
+		link := tx.Model(&models.StatementEntry{}).Where("id = ?", entry.ID)
+		if entry.TransactionID == nil {
+			link = link.Where("transaction_id IS NULL")
+		} else {
+			link = link.Where("transaction_id = ?", *entry.TransactionID)
+		}
+		res := link.Where(`NOT EXISTS (SELECT 1 FROM (
+			SELECT statement_entries.transaction_id FROM statement_entries
+			JOIN bank_statements ON bank_statements.id = statement_entries.statement_id
+			WHERE bank_statements.user_id = ? AND statement_entries.transaction_id = ?
+		) AS linked)`, userID, transactionID).Update("transaction_id", transactionID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		entry.TransactionID = &transactionID
+		return nil
+	})
+}
+
+func (r *statementRepository) CreateEntryTransaction(entry *models.StatementEntry, trx *models.Transaction) error {
+	trx.CreatedAt = dbTime(trx.CreatedAt)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(trx.Tags) > 0 {
+			if err := resolveTags(tx, []*models.Transaction{trx}); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Tags.*").Create(trx).Error; err != nil {
+			return err
+		}
+
+		// Tautkan hanya kalau tautannya masih sama dengan saat mutasi dibaca (kosong, atau ke transaksi
+		// yang sudah dihapus). Request lain yang lebih dulu menautkan = batal semua (double klik).
+		link := tx.Model(&models.StatementEntry{}).Where("id = ?", entry.ID)
+		if entry.TransactionID == nil {
+			link = link.Where("transaction_id IS NULL")
+		} else {
+			link = link.Where("transaction_id = ?", *entry.TransactionID)
+		}
+		res := link.Update("transaction_id", trx.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		entry.TransactionID = &trx.ID
+		return nil
+	})
+}
+
+func (r *statementRepository) LinkedTransactionIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.StatementEntry{}).
+		Joins("JOIN bank_statements ON bank_statements.id = statement_entries.statement_id").
+		Where("bank_statements.user_id = ? AND statement_entries.transaction_id IS NOT NULL", userID).
+		Pluck("statement_entries.transaction_id", &ids).Error
+	return ids, err
+}
+
+func (r *statementRepository) Delete(statement *models.BankStatement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("statement_id = ?", statement.ID).Delete(&models.StatementEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(statement).Error
+	})
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"backend-gin/database/dbtest"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"errors"
+	"testing"
+)
+
+func TestStatementLinkEntry(t *testing.T) {
+	dbtest.ForEach(t, func(t *testing.T, tdb dbtest.DB) {
+		repos, user := setupRepos(t, tdb)
+		s := seedTransactions(t, repos, user.ID)
+
+		statement := &models.BankStatement{UserID: user.ID, Bank: "bca", Currency: "IDR", PeriodFrom: day(1), PeriodTo: day(31)}
+		entries := []models.StatementEntry{
+			{Line: 1, PostedAt: day(3), Description: "QRIS PADANG", Type: models.TypeExpense, Amount: 45000, Currency: "IDR"},
+			{Line: 2, PostedAt: day(4), Description: "QRIS GORENGAN", Type: models.TypeExpense, Amount: 20000, Currency: "IDR"},
+		}
+		if err := repos.Statements.Create(statement, entries); err != nil {
+			t.Fatal(err)
+		}
+		read := func(id uint) *models.StatementEntry {
+			t.Helper()
+			entry, err := repos.Statements.FindEntry(statement.ID, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return entry
+		}
+		padang, gorengan := read(entries[0].ID), read(entries[1].ID)
+
+		if err := repos.Statements.LinkEntry(user.ID, padang, s.lunch.ID); err != nil {
+			t.Fatalf("link: %v", err)
+		}
+		if got := read(padang.ID).TransactionID; got == nil || *got != s.lunch.ID {
+			t.Fatalf("transaction_id = %v, mau %d", got, s.lunch.ID)
+		}
+
+		// Transaksi yang sudah tertaut tidak bisa dipakai mutasi lain
+		if err := repos.Statements.LinkEntry(user.ID, gorengan, s.lunch.ID); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("link transaksi tertaut = %v, mau ErrNotFound", err)
+		}
+		// Mutasi yang dibaca sebelum ditautkan request lain (salinan basi) tidak menimpa tautannya
+		stale := *padang
+		stale.TransactionID = nil
+		if err := repos.Statements.LinkEntry(user.ID, &stale, s.snack.ID); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("link mutasi basi = %v, mau ErrNotFound", err)
+		}
+		// Transaksi user lain / tidak ada
+		if err := repos.Statements.LinkEntry(user.ID+1, gorengan, s.snack.ID); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("link transaksi user lain = %v, mau ErrNotFound", err)
+		}
+		if got := read(gorengan.ID).TransactionID; got != nil {
+			t.Fatalf("mutasi gorengan tertaut ke %d", *got)
+		}
+
+		// Setelah dilepas, transaksinya bebas lagi
+		if err := repos.Statements.SetEntryTransaction(padang.ID, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Statements.LinkEntry(user.ID, gorengan, s.lunch.ID); err != nil {
+			t.Fatalf("link setelah unlink: %v", err)
+		}
+		if gorengan.TransactionID == nil || *gorengan.TransactionID != s.lunch.ID {
+			t.Fatalf("entry.TransactionID = %v, mau diperbarui", gorengan.TransactionID)
+		}
+	})
+}
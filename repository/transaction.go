@@ -0,0 +1,504 @@
+package repository
+
+import (
+	"backend-gin/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type transactionRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionRepository(db *gorm.DB) TransactionRepository {
+	return &transactionRepository{db: db}
+}
+
+func (r *transactionRepository) Create(trx *models.Transaction) error {
+	trx.CreatedAt = dbTime(trx.CreatedAt)
+	if len(trx.Tags) == 0 {
+		return r.db.Create(trx).Error
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolveTags(tx, []*models.Transaction{trx}); err != nil {
+			return err
+		}
+		return tx.Omit("Tags.*").Create(trx).Error
+	})
+}
+
+func (r *transactionRepository) CreateBatch(trx []models.Transaction) error {
+	if len(trx) == 0 {
+		return nil
+	}
+	tagged := make([]*models.Transaction, 0, len(trx))
+	for i := range trx {
+		trx[i].CreatedAt = dbTime(trx[i].CreatedAt)
+		if len(trx[i].Tags) > 0 {
+			tagged = append(tagged, &trx[i])
+		}
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolveTags(tx, tagged); err != nil {
+			return err
+		}
+		return tx.Omit("Tags.*").CreateInBatches(trx, 200).Error
+	})
+}
+
+// resolveTags: Tags tiap transaksi (baru berisi Name) diganti baris tag milik user, yang belum ada dibuat dulu.
+// Tag yang sama di beberapa transaksi cukup dicari sekali.
+func resolveTags(tx *gorm.DB, trx []*models.Transaction) error {
+	names := make(map[uint][]string)
+	type tagKey struct {
+		userID uint
+		name   string
+	}
+	seen := make(map[tagKey]bool)
+	for _, t := range trx {
+		for _, tag := range t.Tags {
+			if key := (tagKey{t.UserID, tag.Name}); !seen[key] {
+				seen[key] = true
+				names[t.UserID] = append(names[t.UserID], tag.Name)
+			}
+		}
+	}
+	for userID, list := range names {
+		tags := make([]models.Tag, len(list))
+		for i, name := range list {
+			tags[i] = models.Tag{UserID: userID, Name: name}
+		}
+		// Tag yang sudah ada (juga dari request lain yang bersamaan) dilewati
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("User").Create(&tags).Error; err != nil {
+			return err
+		}
+		var existing []models.Tag
+		if err := tx.Where("user_id = ? AND name IN ?", userID, list).Find(&existing).Error; err != nil {
+			return err
+		}
+		byName := make(map[string]models.Tag, len(existing))
+		for _, tag := range existing {
+			byName[tag.Name] = tag
+		}
+		for _, t := range trx {
+			if t.UserID != userID {
+				continue
+			}
+			for i := range t.Tags {
+				t.Tags[i] = byName[t.Tags[i].Name]
+			}
+		}
+	}
+	return nil
+}
+
+func (r *transactionRepository) FindForUser(userID, id uint) (*models.Transaction, error) {
+	var trx models.Transaction
+	if err := r.db.Preload("Splits").Preload("Tags").Where("id = ? AND user_id = ?", id, userID).First(&trx).Error; err != nil {
+		return nil, err
+	}
+	return &trx, nil
+}
+
+// filtered: query transaksi user sesuai kondisi filter (tanpa urutan & pagination)
+func (r *transactionRepository) filtered(db *gorm.DB, filter TransactionFilter) *gorm.DB {
+	query := db.Model(&models.Transaction{}).Where("user_id = ?", filter.UserID)
+
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if filter.Search != "" {
+		query = r.searchCondition(query, filter.Search)
+	}
+
+	if len(filter.Categories) > 0 {
+		// Transaksi split ikut cocok kalau salah satu baris rinciannya berkategori itu
+		categories := lowerAll(filter.Categories)
+		query = query.Where("(LOWER(category) IN ? OR id IN (?))", categories,
+			db.Model(&models.TransactionSplit{}).Select("transaction_id").Where("LOWER(category) IN ?", categories))
+	}
+	if len(filter.Tags) > 0 {
+		tagged := db.Table("transaction_tags").Select("transaction_tags.transaction_id").
+			Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+			Where("tags.user_id = ? AND tags.name IN ?", filter.UserID, filter.Tags)
+		query = query.Where("id IN (?)", tagged)
+	}
+	if filter.Currency != "" {
+		query = query.Where("currency = ?", filter.Currency)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", dbTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", dbTime(filter.To))
+	}
+	if filter.MinAmount > 0 {
+		query = query.Where("amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		query = query.Where("amount <= ?", filter.MaxAmount)
+	}
+	return query
+}
+
+func lowerAll(values []string) []string {
+	lower := make([]string, len(values))
+	for i, value := range values {
+		lower[i] = strings.ToLower(value)
+	}
+	return lower
+}
+
+func (r *transactionRepository) List(filter TransactionFilter) ([]models.Transaction, int64, error) {
+	query := r.filtered(r.db, filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, dir := "created_at", "desc"
+	switch filter.Sort {
+	case SortAmount:
+		column = "amount"
+	case SortCategory:
+		column = "category"
+	}
+	if filter.Asc {
+		dir = "asc"
+	}
+
+	page := query.Order(column + " " + dir).Order("id " + dir).Limit(filter.Limit + 1)
+	if cursor := filter.After; cursor != nil {
+		var value interface{} = dbTime(cursor.CreatedAt)
+		switch column {
+		case "amount":
+			value = cursor.Amount
+		case "category":
+			value = cursor.Category
+		}
+		// Keyset: baris setelah (value, id) sesuai arah urutan, tidak bergeser walau ada data baru
+		op := "<"
+		if filter.Asc {
+			op = ">"
+		}
+		page = page.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))", value, value, cursor.ID)
+	} else {
+		page = page.Offset((filter.Page - 1) * filter.Limit)
+	}
+
+	var trx []models.Transaction
+	if err := page.Preload("Splits").Preload("Tags").Find(&trx).Error; err != nil {
+		return nil, 0, err
+	}
+	return trx, total, nil
+}
+
+// searchCondition: full-text search sesuai dialek (index dibuat migration 0009_transaction_search &
+// 0017_transaction_split_search). Input dipecah jadi kata (huruf/angka saja), tiap kata dicocokkan
+// sebagai awalan di kategori & catatan transaksi atau di salah satu baris rincian split-nya.
+func (r *transactionRepository) searchCondition(query *gorm.DB, search string) *gorm.DB {
+	words := strings.FieldsFunc(strings.ToLower(search), func(ch rune) bool {
+		return !unicode.IsLetter(ch) && !unicode.IsDigit(ch)
+	})
+
+	var parent, split string
+	var term func(word string) string
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		parent = "to_tsvector('simple', COALESCE(category, '') || ' ' || COALESCE(note, '')) @@ to_tsquery('simple', ?)"
+		split = parent
+		term = func(word string) string { return word + ":*" }
+	case "mysql":
+		parent = "MATCH(category, note) AGAINST (? IN BOOLEAN MODE)"
+		split = parent
+		term = func(word string) string { return word + "*" }
+	default:
+		parent = "id IN (SELECT rowid FROM transactions_fts WHERE transactions_fts MATCH ?)"
+		split = "id IN (SELECT rowid FROM transaction_splits_fts WHERE transaction_splits_fts MATCH ?)"
+		term = func(word string) string { return `"` + word + `"*` }
+	}
+
+	// Kolom di subquery merujuk ke transaction_splits
+	condition := "(" + parent + " OR id IN (SELECT transaction_id FROM transaction_splits WHERE " + split + "))"
+	for _, word := range words {
+		query = query.Where(condition, term(word), term(word))
+	}
+	return query
+}
+
+func (r *transactionRepository) FindInPeriod(userID uint, from, to time.Time, newestFirst bool) ([]models.Transaction, error) {
+	query := r.db.Preload("Splits").Preload("Tags").Where("user_id = ?", userID)
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", dbTime(from))
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", dbTime(to))
+	}
+	if newestFirst {
+		query = query.Order("created_at desc")
+	} else {
+		query = query.Order("created_at asc")
+	}
+
+	var trx []models.Transaction
+	err := query.Find(&trx).Error
+	return trx, err
+}
+
+func (r *transactionRepository) EachInPeriod(userID uint, from, to time.Time, size int, fn func([]models.Transaction) error) error {
+	var cursor *models.Transaction
+	for {
+		query := r.db.Preload("Splits").Preload("Tags").Where("user_id = ?", userID)
+		if !from.IsZero() {
+			query = query.Where("created_at >= ?", dbTime(from))
+		}
+		if !to.IsZero() {
+			query = query.Where("created_at < ?", dbTime(to))
+		}
+		if cursor != nil {
+			at := dbTime(cursor.CreatedAt)
+			query = query.Where("(created_at < ? OR (created_at = ? AND id < ?))", at, at, cursor.ID)
+		}
+
+		var batch []models.Transaction
+		if err := query.Order("created_at desc").Order("id desc").Limit(size).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < size {
+			return nil
+		}
+		cursor = &batch[len(batch)-1]
+	}
+}
+
+func (r *transactionRepository) CountInWallet(walletID uint) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Transaction{}).Where("wallet_id = ?", walletID).Count(&count).Error
+	return count, err
+}
+
+func (r *transactionRepository) ListAllForUser(userID uint) ([]models.Transaction, error) {
+	var trx []models.Transaction
+	err := r.db.Unscoped().Preload("Splits").Preload("Tags").Where("user_id = ?", userID).Order("created_at, id").Find(&trx).Error
+	return trx, err
+}
+
+func (r *transactionRepository) Totals(filter TotalsFilter) ([]TransactionTotal, error) {
+	rateDay := "CASE WHEN currency = ? THEN '' ELSE " + r.dayExpr() + " END"
+	group, selects, amount := "type, currency", "type, currency", "amount"
+	if filter.ByCategory {
+		// Transaksi split dihitung per baris rincian (LEFT JOIN: transaksi biasa tetap satu baris)
+		const category = "COALESCE(transaction_splits.category, transactions.category)"
+		group += ", " + category
+		selects += ", " + category + " AS category"
+		amount = "COALESCE(transaction_splits.amount, transactions.amount)"
+	}
+	if filter.ByTag {
+		group += ", tags.name"
+		selects += ", tags.name AS tag"
+	}
+	selects += ", " + rateDay + " AS rate_day"
+	args := []interface{}{filter.BaseCurrency}
+
+	from, to := filter.From, filter.To
+	if len(filter.Buckets) > 0 {
+		if len(filter.Buckets) < 2 {
+			return nil, nil
+		}
+		// Indeks rentang dihitung di SQL pakai batas dari Go (bukan fungsi tanggal database)
+		var bucket strings.Builder
+		bucket.WriteString("CASE")
+		for i, end := range filter.Buckets[1:] {
+			bucket.WriteString(" WHEN created_at < ? THEN " + strconv.Itoa(i))
+			args = append(args, dbTime(end))
+		}
+		bucket.WriteString(" END")
+		selects += ", " + bucket.String() + " AS bucket"
+		group += ", bucket"
+		from, to = filter.Buckets[0], filter.Buckets[len(filter.Buckets)-1]
+	}
+
+	query := r.db.Model(&models.Transaction{}).
+		Select(selects+", SUM("+amount+") AS total, COUNT(*) AS count", args...).
+		Where("transactions.user_id = ?", filter.UserID)
+	if filter.ByCategory {
+		query = query.Joins("LEFT JOIN transaction_splits ON transaction_splits.transaction_id = transactions.id")
+	}
+	if filter.ByTag {
+		query = query.Joins("JOIN transaction_tags ON transaction_tags.transaction_id = transactions.id").
+			Joins("JOIN tags ON tags.id = transaction_tags.tag_id")
+	}
+	if filter.WalletID != nil {
+		query = query.Where("wallet_id = ?", *filter.WalletID)
+	}
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", dbTime(from))
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", dbTime(to))
+	}
+
+	var totals []TransactionTotal
+	// GROUP BY pakai alias: didukung SQLite, Postgres & MySQL
+	err := query.Group(group + ", rate_day").Scan(&totals).Error
+	return totals, err
+}
+
+// dbTime: batas waktu disamakan ke zona server sebelum dipakai di query / disimpan.
+// SQLite menyimpan waktu sebagai teks beserta offset zona penulisnya, jadi perbandingan teks hanya
+// benar kalau offset-nya sama (batas dari zona waktu user bisa beda). Di Postgres/MySQL momennya tetap sama.
+func dbTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Local()
+}
+
+// dayExpr: tanggal (YYYY-MM-DD) created_at sesuai dialek database
+func (r *transactionRepository) dayExpr() string {
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		return "to_char(created_at, 'YYYY-MM-DD')"
+	case "mysql":
+		return "DATE_FORMAT(created_at, '%Y-%m-%d')"
+	default:
+		// SQLite menyimpan waktu sebagai teks "YYYY-MM-DD HH:MM:SS..." (waktu lokal saat disimpan)
+		return "substr(created_at, 1, 10)"
+	}
+}
+
+// Soft delete: data masuk tong sampah dan masih bisa di-restore sampai masa simpan habis
+func (r *transactionRepository) Delete(userID, id uint) (bool, error) {
+	res := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Transaction{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeleteMatching: soft delete semua transaksi yang cocok dengan filter dalam satu transaksi database,
+// dengan waktu hapus yang sama. Return ID yang terhapus (urut ID).
+func (r *transactionRepository) DeleteMatching(filter TransactionFilter) ([]uint, error) {
+	var ids []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.filtered(tx, filter).Order("id").Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Transaction{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Recategorize mengganti kategori semua transaksi (dan baris rincian split) yang kategorinya salah satu
+// filter.Categories dalam satu transaksi database. Return ID + kategori lama tiap transaksi yang berubah (urut ID).
+func (r *transactionRepository) Recategorize(filter TransactionFilter, category string) ([]CategoryChange, error) {
+	from := lowerAll(filter.Categories)
+	filter.Categories = nil
+
+	var changes []CategoryChange
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Yang sudah persis sama tidak dihitung berubah
+		if err := r.filtered(tx, filter).Where("LOWER(category) IN ? AND category <> ?", from, category).
+			Select("id, category").Scan(&changes).Error; err != nil {
+			return err
+		}
+		var lines []struct {
+			ID            uint
+			TransactionID uint
+			Category      string
+		}
+		err := tx.Model(&models.TransactionSplit{}).Select("id, transaction_id, category").
+			Where("transaction_id IN (?) AND LOWER(category) IN ? AND category <> ?", r.filtered(tx, filter).Select("id"), from, category).
+			Scan(&lines).Error
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(changes))
+		changed := make(map[uint]bool, len(changes))
+		for _, change := range changes {
+			ids = append(ids, change.ID)
+			changed[change.ID] = true
+		}
+		lineIDs := make([]uint, len(lines))
+		for i, line := range lines {
+			lineIDs[i] = line.ID
+			if !changed[line.TransactionID] {
+				changes = append(changes, CategoryChange{ID: line.TransactionID, Category: line.Category})
+				changed[line.TransactionID] = true
+			}
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+
+		if len(ids) > 0 {
+			if err := tx.Model(&models.Transaction{}).Where("id IN ?", ids).Update("category", category).Error; err != nil {
+				return err
+			}
+		}
+		if len(lineIDs) > 0 {
+			return tx.Model(&models.TransactionSplit{}).Where("id IN ?", lineIDs).Update("category", category).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Return false kalau data tidak ada / bukan miliknya / sudah lewat masa simpan
+func (r *transactionRepository) Restore(userID, id uint, since time.Time) (bool, error) {
+	res := r.db.Unscoped().Model(&models.Transaction{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", id, userID, since).
+		Update("deleted_at", nil)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *transactionRepository) ListDeleted(userID uint, since time.Time) ([]models.Transaction, error) {
+	var trx []models.Transaction
+	err := r.db.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", userID, since).
+		Order("deleted_at desc").
+		Preload("Splits").
+		Preload("Tags").
+		Find(&trx).Error
+	return trx, err
+}
+
+func (r *transactionRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		const expired = "deleted_at IS NOT NULL AND deleted_at < ?"
+		trxIDs := tx.Unscoped().Model(&models.Transaction{}).Select("id").Where(expired, cutoff)
+		if err := tx.Where("transaction_id IN (?)", trxIDs).Delete(&models.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("transaction_id IN (?)", trxIDs).Delete(&models.TransactionTag{}).Error; err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where(expired, cutoff).Delete(&models.Transaction{})
+		purged = res.RowsAffected
+		return res.Error
+	})
+	return purged, err
+}
@@ -0,0 +1,339 @@
+package repository_test
+
+import (
+	"backend-gin/database"
+	"backend-gin/database/dbtest"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Query transaksi punya cabang per dialek (full-text, tanggal, JOIN split), jadi dites di semua database
+func TestMain(m *testing.M) {
+	os.Exit(dbtest.Main(m, database.Open))
+}
+
+// setupRepos: skema hasil migration + satu user
+func setupRepos(t *testing.T, tdb dbtest.DB) (*repository.Repositories, *models.User) {
+	t.Helper()
+	if _, err := database.MigrateUp(tdb.DB, false); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repos := repository.New(tdb.DB)
+	user := &models.User{Username: "budi", Password: "x", Role: "user", Status: "trial", TrialEndsAt: time.Now()}
+	if err := repos.Users.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return repos, user
+}
+
+// day: jam 12 siang (zona lokal) supaya tanggal sama di semua database
+func day(d int) time.Time {
+	return time.Date(2025, time.March, d, 12, 0, 0, 0, time.Local)
+}
+
+type seeded struct {
+	salary, lunch, receipt, snack, coffee *models.Transaction
+}
+
+// seedTransactions: gaji, makan siang (#kantor), struk split Belanja + Jajan, jajan biasa & kopi dalam USD
+func seedTransactions(t *testing.T, repos *repository.Repositories, userID uint) seeded {
+	t.Helper()
+	s := seeded{
+		salary: &models.Transaction{Type: models.TypeIncome, Amount: 5000000, Currency: "IDR", Category: "Gaji", Note: "Gaji Maret", CreatedAt: day(1)},
+		lunch: &models.Transaction{Type: models.TypeExpense, Amount: 45000, Currency: "IDR", Category: "Makan", Note: "Nasi padang #kantor",
+			CreatedAt: day(3), Tags: []models.Tag{{Name: "kantor"}}},
+		receipt: &models.Transaction{Type: models.TypeExpense, Amount: 150000, Currency: "IDR", Category: "Belanja", Note: "struk indomaret",
+			CreatedAt: day(3), Splits: []models.TransactionSplit{
+				{Category: "Belanja", Amount: 100000, Note: "sabun cuci"},
+				{Category: "Jajan", Amount: 50000, Note: "coklat batangan"},
+			}},
+		snack:  &models.Transaction{Type: models.TypeExpense, Amount: 20000, Currency: "IDR", Category: "Jajan", Note: "Gorengan", CreatedAt: day(4)},
+		coffee: &models.Transaction{Type: models.TypeExpense, Amount: 450, Currency: "USD", Category: "Makan", Note: "Kopi bandara", CreatedAt: day(5)},
+	}
+	for _, trx := range []*models.Transaction{s.salary, s.lunch, s.receipt, s.snack, s.coffee} {
+		trx.UserID = userID
+		if err := repos.Transactions.Create(trx); err != nil {
+			t.Fatalf("create %q: %v", trx.Note, err)
+		}
+	}
+	return s
+}
+
+func ids(trx []models.Transaction) []uint {
+	result := make([]uint, len(trx))
+	for i, t := range trx {
+		result[i] = t.ID
+	}
+	return result
+}
+
+func TestTransactionCreateAndKeysetList(t *testing.T) {
+	dbtest.ForEach(t, func(t *testing.T, tdb dbtest.DB) {
+		repos, user := setupRepos(t, tdb)
+		s := seedTransactions(t, repos, user.ID)
+
+		// Terbaru dulu; makan siang & struk sama waktunya, urutan kedua pakai ID
+		want := []uint{s.coffee.ID, s.snack.ID, s.receipt.ID, s.lunch.ID, s.salary.ID}
+		var got []uint
+		filter := repository.TransactionFilter{UserID: user.ID, Limit: 2}
+		for page := 0; page < 5; page++ {
+			trx, total, err := repos.Transactions.List(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != 5 {
+				t.Fatalf("total = %d", total)
+			}
+			more := len(trx) > filter.Limit
+			if more {
+				trx = trx[:filter.Limit]
+			}
+			got = append(got, ids(trx)...)
+			if !more {
+				break
+			}
+			last := trx[len(trx)-1]
+			filter.After = &repository.TransactionCursor{CreatedAt: last.CreatedAt, Amount: last.Amount, Category: last.Category, ID: last.ID}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("keyset tanggal = %v, mau %v", got, want)
+		}
+
+		// Nominal terkecil dulu, lanjut dari kursor
+		filter = repository.TransactionFilter{UserID: user.ID, Sort: repository.SortAmount, Asc: true, Limit: 2,
+			After: &repository.TransactionCursor{Amount: s.snack.Amount, ID: s.snack.ID}}
+		trx, _, err := repos.Transactions.List(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(trx); fmt.Sprint(got) != fmt.Sprint([]uint{s.lunch.ID, s.receipt.ID, s.salary.ID}) {
+			t.Fatalf("keyset nominal = %v", got)
+		}
+
+		// Rincian split & tag ikut terbaca
+		found, err := repos.Transactions.FindForUser(user.ID, s.receipt.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found.Splits) != 2 || found.Splits[1].Note != "coklat batangan" {
+			t.Fatalf("splits = %+v", found.Splits)
+		}
+		found, err = repos.Transactions.FindForUser(user.ID, s.lunch.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found.Tags) != 1 || found.Tags[0].Name != "kantor" || !found.CreatedAt.Equal(day(3)) {
+			t.Fatalf("makan siang = %+v", found)
+		}
+
+		// Filter kategori mencocokkan baris rincian, filter tag lewat tabel penghubung
+		trx, total, err := repos.Transactions.List(repository.TransactionFilter{UserID: user.ID, Categories: []string{"JAJAN"}, Limit: 10})
+		if err != nil || total != 2 {
+			t.Fatalf("kategori jajan = %v (%d), %v", ids(trx), total, err)
+		}
+		trx, total, err = repos.Transactions.List(repository.TransactionFilter{UserID: user.ID, Tags: []string{"kantor"}, Limit: 10})
+		if err != nil || total != 1 || trx[0].ID != s.lunch.ID {
+			t.Fatalf("tag kantor = %v (%d), %v", ids(trx), total, err)
+		}
+	})
+}
+
+func TestTransactionEachInPeriod(t *testing.T) {
+	dbtest.ForEach(t, func(t *testing.T, tdb dbtest.DB) {
+		repos, user := setupRepos(t, tdb)
+		s := seedTransactions(t, repos, user.ID)
+
+		// Batch 2 baris: batas batch jatuh di antara makan siang & struk yang sama waktunya
+		var batches [][]uint
+		err := repos.Transactions.EachInPeriod(user.ID, day(2), day(5), 2, func(trx []models.Transaction) error {
+			batches = append(batches, ids(trx))
+			for _, item := range trx {
+				if item.ID == s.receipt.ID && len(item.Splits) != 2 {
+					return fmt.Errorf("splits struk = %d", len(item.Splits))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]uint{{s.snack.ID, s.receipt.ID}, {s.lunch.ID}}
+		if fmt.Sprint(batches) != fmt.Sprint(want) {
+			t.Fatalf("batch = %v, mau %v", batches, want)
+		}
+
+		// Error dari fn menghentikan pembacaan
+		stop := errors.New("stop")
+		calls := 0
+		err = repos.Transactions.EachInPeriod(user.ID, time.Time{}, time.Time{}, 1, func([]models.Transaction) error {
+			calls++
+			return stop
+		})
+		if err != stop || calls != 1 {
+			t.Fatalf("err = %v setelah %d batch", err, calls)
+		}
+	})
+}
+
+func TestTransactionTotalsWithSplits(t *testing.T) {
+	dbtest.ForEach(t, func(t *testing.T, tdb dbtest.DB) {
+		repos, user := setupRepos(t, tdb)
+		seedTransactions(t, repos, user.ID)
+
+		key := func(row repository.TransactionTotal) string {
+			return fmt.Sprintf("%s|%s|%s|%s|%s|%d", row.Type, row.Category, row.Tag, row.Currency, row.RateDay, row.Bucket)
+		}
+		totals := func(filter repository.TotalsFilter) map[string][2]int64 {
+			t.Helper()
+			rows, err := repos.Transactions.Totals(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := make(map[string][2]int64, len(rows))
+			for _, row := range rows {
+				result[key(row)] = [2]int64{row.Total, row.Count}
+			}
+			return result
+		}
+		expect := func(name string, got, want map[string][2]int64) {
+			t.Helper()
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("%s = %v\nmau %v", name, got, want)
+			}
+		}
+
+		// Tanpa kategori: transaksi split tetap satu baris (tidak dobel karena JOIN)
+		expect("per tipe", totals(repository.TotalsFilter{UserID: user.ID, BaseCurrency: "IDR"}), map[string][2]int64{
+			"income|||IDR||0":            {5000000, 1},
+			"expense|||IDR||0":           {215000, 3},
+			"expense|||USD|2025-03-05|0": {450, 1},
+		})
+
+		// Per kategori: struk dipecah per baris rincian
+		expect("per kategori", totals(repository.TotalsFilter{UserID: user.ID, BaseCurrency: "IDR", ByCategory: true}), map[string][2]int64{
+			"income|Gaji||IDR||0":             {5000000, 1},
+			"expense|Makan||IDR||0":           {45000, 1},
+			"expense|Belanja||IDR||0":         {100000, 1},
+			"expense|Jajan||IDR||0":           {70000, 2},
+			"expense|Makan||USD|2025-03-05|0": {450, 1},
+		})
+
+		// Per tag & per rentang hari (batas dari Go)
+		expect("per tag", totals(repository.TotalsFilter{UserID: user.ID, BaseCurrency: "IDR", ByTag: true}), map[string][2]int64{
+			"expense||kantor|IDR||0": {45000, 1},
+		})
+		start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.Local)
+		expect("per rentang", totals(repository.TotalsFilter{UserID: user.ID, BaseCurrency: "USD",
+			Buckets: []time.Time{start, start.AddDate(0, 0, 3), start.AddDate(0, 0, 4)}}), map[string][2]int64{
+			"income|||IDR|2025-03-01|0":  {5000000, 1},
+			"expense|||IDR|2025-03-03|0": {195000, 2},
+			"expense|||IDR|2025-03-04|1": {20000, 1},
+		})
+	})
+}
+
+func TestTransactionSearch(t *testing.T) {
+	dbtest.ForEach(t, func(t *testing.T, tdb dbtest.DB) {
+		repos, user := setupRepos(t, tdb)
+		s := seedTransactions(t, repos, user.ID)
+
+		for search, want := range map[string][]uint{
+			"PADANG":        {s.lunch.ID},
+			"pad":           {s.lunch.ID},  // awalan kata
+			"nasi kantor":   {s.lunch.ID},  // semua kata harus ada
+			"nasi bandung":  nil,           // kata kedua tidak ada
+			"gaji":          {s.salary.ID}, // kategori ikut dicari
+			"kopi, bandara": {s.coffee.ID}, // tanda baca diabaikan
+			`"pad"* OR`:     nil,           // sintaks full-text dari user tidak dijalankan ("or" jadi kata biasa)
+
+			// Baris rincian split ikut dicari, hasilnya transaksi induk
+			"coklat":         {s.receipt.ID},
+			"batang":         {s.receipt.ID},             // awalan kata di catatan rincian
+			"sabun cuci":     {s.receipt.ID},             // beberapa kata di satu baris rincian
+			"struk coklat":   {s.receipt.ID},             // kata di induk & di rincian
+			"jajan":          {s.receipt.ID, s.snack.ID}, // kategori rincian & kategori transaksi biasa
+			"sabun gorengan": nil,
+		} {
+			expectSearch(t, repos, user.ID, search, want)
+		}
+
+		// Index rincian ikut berubah saat kategorinya diganti
+		if _, err := repos.Transactions.Recategorize(repository.TransactionFilter{UserID: user.ID, Categories: []string{"Jajan"}}, "Camilan"); err != nil {
+			t.Fatal(err)
+		}
+		expectSearch(t, repos, user.ID, "camil", []uint{s.receipt.ID, s.snack.ID})
+		expectSearch(t, repos, user.ID, "jajan", nil)
+	})
+}
+
+// expectSearch: ID hasil pencarian (urut ID) harus sama dengan want
+func expectSearch(t *testing.T, repos *repository.Repositories, userID uint, search string, want []uint) {
+	t.Helper()
+	trx, _, err := repos.Transactions.List(repository.TransactionFilter{UserID: userID, Search: search, Limit: 10})
+	if err != nil {
+		t.Fatalf("search %q: %v", search, err)
+	}
+	got := ids(trx)
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	if fmt.Sprint(got) != fmt.Sprint(want) && !(len(got) == 0 && len(want) == 0) {
+		t.Errorf("search %q = %v, mau %v", search, got, want)
+	}
+}
+
+func TestTransactionDeleteMatchingAndRecategorize(t *testing.T) {
+	dbtest.ForEach(t, func(t *testing.T, tdb dbtest.DB) {
+		repos, user := setupRepos(t, tdb)
+		s := seedTransactions(t, repos, user.ID)
+
+		// Ganti kategori: transaksi biasa & baris rincian split, kategori induk struk tetap Belanja
+		changes, err := repos.Transactions.Recategorize(repository.TransactionFilter{UserID: user.ID, Categories: []string{"JAJAN"}}, "Camilan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []repository.CategoryChange{{ID: s.receipt.ID, Category: "Jajan"}, {ID: s.snack.ID, Category: "Jajan"}}
+		if fmt.Sprint(changes) != fmt.Sprint(want) {
+			t.Fatalf("changes = %+v, mau %+v", changes, want)
+		}
+		receipt, err := repos.Transactions.FindForUser(user.ID, s.receipt.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.Category != "Belanja" || receipt.Splits[1].Category != "Camilan" {
+			t.Fatalf("struk = %s / %+v", receipt.Category, receipt.Splits)
+		}
+		// Yang sudah persis sama tidak dihitung berubah
+		if changes, _ := repos.Transactions.Recategorize(repository.TransactionFilter{UserID: user.ID, Categories: []string{"camilan"}}, "Camilan"); len(changes) != 0 {
+			t.Fatalf("ganti ke nama yang sama = %+v", changes)
+		}
+
+		// Hapus massal: kategori (termasuk baris rincian) + tipe, masuk tong sampah
+		deleted, err := repos.Transactions.DeleteMatching(repository.TransactionFilter{
+			UserID: user.ID, Type: models.TypeExpense, Categories: []string{"camilan", "gaji"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(deleted) != fmt.Sprint([]uint{s.receipt.ID, s.snack.ID}) {
+			t.Fatalf("deleted = %v", deleted)
+		}
+		trx, total, err := repos.Transactions.List(repository.TransactionFilter{UserID: user.ID, Limit: 10})
+		if err != nil || total != 3 {
+			t.Fatalf("sisa = %v (%d), %v", ids(trx), total, err)
+		}
+		trash, err := repos.Transactions.ListDeleted(user.ID, day(1))
+		if err != nil || len(trash) != 2 {
+			t.Fatalf("tong sampah = %v, %v", ids(trash), err)
+		}
+		// Filter tanpa hasil: tidak ada yang terhapus
+		if deleted, err := repos.Transactions.DeleteMatching(repository.TransactionFilter{UserID: user.ID, Search: strings.Repeat("x", 5)}); err != nil || len(deleted) != 0 {
+			t.Fatalf("hapus tanpa hasil = %v, %v", deleted, err)
+		}
+	})
+}
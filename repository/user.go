@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"backend-gin/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) FindByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByTelegramID(telegramID int64) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) List() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Select("id, username, role, status, trial_ends_at, telegram_id, last_transaction_at, created_at").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) ListMonthlyReport() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("monthly_report = ? AND telegram_id IS NOT NULL", true).Order("id").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *userRepository) Save(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r *userRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// Soft delete user + transaksinya dengan waktu hapus yang SAMA,
+// supaya saat restore kita tahu transaksi mana yang ikut terhapus bersama akunnya
+func (r *userRepository) SoftDelete(id uint, at time.Time) (int64, error) {
+	var trxCount int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", id).Update("deleted_at", at).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&models.Transaction{}).Where("user_id = ?", id).Update("deleted_at", at)
+		trxCount = res.RowsAffected
+		return res.Error
+	})
+	return trxCount, err
+}
+
+func (r *userRepository) ListDeleted(since time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Unscoped().
+		Select("id, username, role, status, telegram_id, created_at, deleted_at").
+		Where("deleted_at IS NOT NULL AND deleted_at >= ?", since).
+		Order("deleted_at desc").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) FindDeleted(id uint, since time.Time) (*models.User, error) {
+	var user models.User
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", id, since).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Transaksi yang dihapus sendiri oleh user sebelumnya tetap di tong sampah,
+// hanya yang waktu hapusnya sama dengan akun yang dikembalikan
+func (r *userRepository) Restore(user *models.User) (int64, error) {
+	deletedAt := user.DeletedAt.Time
+	var trxCount int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		res := tx.Unscoped().Model(&models.Transaction{}).
+			Where("user_id = ? AND deleted_at = ?", user.ID, deletedAt).
+			Update("deleted_at", nil)
+		trxCount = res.RowsAffected
+		return res.Error
+	})
+	if err == nil {
+		user.DeletedAt = gorm.DeletedAt{}
+	}
+	return trxCount, err
+}
+
+// Hapus PERMANEN user yang sudah lewat masa simpan, beserta semua data miliknya
+func (r *userRepository) PurgeDeletedBefore(cutoff time.Time) (PurgedUsers, error) {
+	return r.purge("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+}
+
+func (r *userRepository) PurgeDeletionDue(now time.Time) (PurgedUsers, error) {
+	return r.purge("delete_after IS NOT NULL AND delete_after <= ?", now)
+}
+
+// purge menghapus user yang cocok dengan kondisi beserta semua data miliknya dalam satu transaksi database.
+// Audit log sengaja tidak ikut (append-only, username sudah tersimpan sebagai snapshot).
+func (r *userRepository) purge(condition string, arg interface{}) (PurgedUsers, error) {
+	var result PurgedUsers
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var userIDs []uint
+		if err := tx.Unscoped().Model(&models.User{}).Where(condition, arg).Pluck("id", &userIDs).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.PaymentLog{}).Where("user_id IN ? AND image_path <> ''", userIDs).Pluck("image_path", &result.Files).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.PaymentLog{}).Error; err != nil {
+			return err
+		}
+		trxIDs := tx.Unscoped().Model(&models.Transaction{}).Select("id").Where("user_id IN ?", userIDs)
+		if err := tx.Where("transaction_id IN (?)", trxIDs).Delete(&models.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("transaction_id IN (?)", trxIDs).Delete(&models.TransactionTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id IN ?", userIDs).Delete(&models.Transaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		statementIDs := tx.Model(&models.BankStatement{}).Select("id").Where("user_id IN ?", userIDs)
+		if err := tx.Where("statement_id IN (?)", statementIDs).Delete(&models.StatementEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.BankStatement{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.SavingsGoal{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.Wallet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("id IN ?", userIDs).Delete(&models.User{}).Error; err != nil {
+			return err
+		}
+		result.Count = len(userIDs)
+		return nil
+	})
+	if err != nil {
+		return PurgedUsers{}, err
+	}
+	return result, nil
+}
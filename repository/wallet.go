@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"backend-gin/models"
+
+	"gorm.io/gorm"
+)
+
+type walletRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) WalletRepository {
+	return &walletRepository{db: db}
+}
+
+func (r *walletRepository) Create(wallet *models.Wallet) error {
+	return r.db.Create(wallet).Error
+}
+
+func (r *walletRepository) FindForUser(userID, id uint) (*models.Wallet, error) {
+	var wallet models.Wallet
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (r *walletRepository) ListForUser(userID uint) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	err := r.db.Where("user_id = ?", userID).Order("name asc").Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *walletRepository) Delete(wallet *models.Wallet) error {
+	return r.db.Delete(wallet).Error
+}
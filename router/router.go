@@ -0,0 +1,134 @@
+package router
+
+import (
+	"backend-gin/handlers"
+	"backend-gin/middleware"
+	"backend-gin/repository"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// New menyusun semua route aplikasi. Dependency (handler + repository user untuk middleware)
+// disiapkan oleh pemanggil, jadi test bisa memakai database in-memory / fake.
+// uploadDir kosong berarti folder bukti pembayaran tidak di-serve.
+func New(h *handlers.Handler, users repository.UserRepository, uploadDir string) *gin.Engine {
+	r := gin.Default()
+	// Proxy (IP/CIDR dipisah koma, "none" = tanpa proxy) yang boleh mengisi X-Forwarded-For.
+	// Tanpa ini header dari siapa pun dipercaya, jadi IP di audit log & batas reset password bisa dipalsukan.
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		var trusted []string
+		if proxies != "none" {
+			trusted = strings.Fields(strings.ReplaceAll(proxies, ",", " "))
+		}
+		if err := r.SetTrustedProxies(trusted); err != nil {
+			log.Printf("TRUSTED_PROXIES tidak valid: %v", err)
+		}
+	}
+
+	config := cors.DefaultConfig()
+	config.AllowAllOrigins = true
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept-Language", "ngrok-skip-browser-warning"}
+	r.Use(cors.New(config))
+
+	// Public Routes
+	r.POST("/login", h.Login)
+	r.POST("/register", h.Register) // Dulu register-admin, sekarang register umum
+	r.POST("/telegram/webhook", h.TelegramWebhook)
+	r.POST("/setup-owner", h.RegisterOwner)
+	r.POST("/password/forgot", h.ForgotPassword) // Kirim kode reset lewat bot Telegram
+	r.POST("/password/reset", h.ResetPassword)   // Pakai kode untuk ganti password
+
+	if uploadDir != "" {
+		r.Static("/uploads", uploadDir)
+	}
+
+	// Protected Routes (Butuh Token)
+	api := r.Group("/api")
+	api.Use(middleware.JwtAuthMiddleware(users))
+
+	// 1. ROUTE BEBAS (Verify Payment bisa diakses walau status Suspended)
+	// Diletakkan LANGSUNG di bawah 'api', sebelum middleware 'RequireActiveOrTrial'
+	api.POST("/verify-payment", h.VerifyPayment)
+	api.POST("/manual-payment", h.ManualPaymentUpload)
+
+	// 2. ROUTE KETAT (Butuh Token + Status Active/Trial)
+	// Kita buat grup baru 'strictApi' yang menerapkan middleware tambahan
+	strictApi := api.Group("/")
+	// strictApi.Use(middleware.RequireActiveOrTrial(users))
+	{
+		// Fitur User Biasa
+		strictApi.GET("/transactions", h.GetTransactions)
+		strictApi.GET("/summary", h.GetSummary)
+		strictApi.GET("/chart/daily", h.GetDailyChart)
+		strictApi.GET("/categories", h.GetCategorySummary)
+		strictApi.GET("/tags", h.GetTagSummary) // Total per #tag
+		strictApi.GET("/analytics/trends", h.GetTrends)
+		strictApi.GET("/user/settings", h.GetUserSettings)
+		strictApi.PUT("/user/settings", h.UpdateUserSettings)
+		strictApi.GET("/user/data-export", h.ExportAccountData)           // Arsip ZIP semua data akun
+		strictApi.POST("/user/delete-request", h.RequestAccountDeletion)  // Minta akun dihapus (ada masa tunggu)
+		strictApi.DELETE("/user/delete-request", h.CancelAccountDeletion) // Batalkan permintaan hapus akun
+		strictApi.GET("/export", h.Export)
+		strictApi.POST("/import", h.ImportTransactions)
+
+		strictApi.POST("/transactions", h.CreateTransaction)                     // Input Data
+		strictApi.GET("/transactions/today", h.GetTodayTransactions)             // Data Hari Ini
+		strictApi.DELETE("/transactions/:id", h.DeleteTransaction)               // Hapus Data (masuk tong sampah)
+		strictApi.GET("/transactions/trash", h.GetTransactionTrash)              // Isi tong sampah
+		strictApi.POST("/transactions/:id/restore", h.RestoreTransaction)        // Kembalikan dari tong sampah
+		strictApi.POST("/transactions/bulk", h.CreateTransactionsBulk)           // Input banyak sekaligus
+		strictApi.POST("/transactions/bulk-delete", h.DeleteTransactionsBulk)    // Hapus banyak (ID / filter)
+		strictApi.POST("/transactions/recategorize", h.RecategorizeTransactions) // Ganti kategori massal
+		strictApi.PUT("/user/profile", h.UpdateUserProfile)
+
+		strictApi.GET("/currencies", h.GetCurrencies)    // Mata uang yang didukung
+		strictApi.GET("/wallets", h.GetWallets)          // Dompet milik user
+		strictApi.POST("/wallets", h.CreateWallet)       // Tambah dompet (mata uang tetap)
+		strictApi.DELETE("/wallets/:id", h.DeleteWallet) // Hapus dompet yang belum dipakai
+
+		// Target tabungan
+		strictApi.GET("/goals", h.GetGoals)                          // Semua target + progres
+		strictApi.POST("/goals", h.CreateGoal)                       // Target baru (dompet / kategori)
+		strictApi.GET("/goals/:id", h.GetGoal)                       // Progres & setoran per bulan
+		strictApi.DELETE("/goals/:id", h.DeleteGoal)                 // Hapus target (transaksi tetap)
+		strictApi.POST("/goals/:id/contributions", h.ContributeGoal) // Setor (dicatat sebagai transaksi)
+
+		// Rekonsiliasi rekening koran bank
+		strictApi.POST("/statements", h.UploadStatement)                                            // Upload CSV/PDF + hasil pencocokan
+		strictApi.GET("/statements", h.GetStatements)                                               // Rekening koran yang pernah diupload
+		strictApi.GET("/statements/:id", h.GetReconciliation)                                       // Cocok / belum dicatat / tidak ada di bank
+		strictApi.DELETE("/statements/:id", h.DeleteStatement)                                      // Hapus rekening koran (transaksi tetap)
+		strictApi.POST("/statements/:id/entries/:entry_id/transaction", h.CreateFromStatementEntry) // Catat mutasi jadi transaksi
+		strictApi.PUT("/statements/:id/entries/:entry_id/link", h.LinkStatementEntry)               // Tautkan ke transaksi yang ada
+		strictApi.DELETE("/statements/:id/entries/:entry_id/link", h.UnlinkStatementEntry)          // Lepas tautan
+
+		// Fitur Super Admin
+		// Aksesnya nanti: POST /api/admin/users
+		admin := strictApi.Group("/admin")
+		{
+			admin.GET("/users", h.GetAllUsers)              // Lihat semua user
+			admin.POST("/users", h.CreateUser)              // Tambah user baru
+			admin.DELETE("/users/:id", h.DeleteUser)        // Hapus user (masuk tong sampah)
+			admin.GET("/users/trash", h.GetUserTrash)       // User yang bisa dikembalikan
+			admin.POST("/users/:id/restore", h.RestoreUser) // Kembalikan user + transaksinya
+
+			admin.GET("/users/:id/stats", h.GetUserStats)        // Get Detail
+			admin.PUT("/users/:id", h.UpdateUser)                // Edit User
+			admin.PATCH("/users/:id/status", h.UpdateUserStatus) // Edit Status/Trial
+			admin.GET("/payments", h.GetRecentPayments)          // Bukti pembayaran terbaru
+			admin.DELETE("/payments/:id", h.DeletePaymentLog)    // Hapus Satu
+			admin.DELETE("/payments", h.DeleteAllPaymentLogs)    // Hapus Semua
+			admin.GET("/audit", h.GetAuditLogs)                  // Audit log (filter + ?format=csv)
+
+			admin.GET("/exchange-rates", h.GetExchangeRates)          // Daftar kurs
+			admin.POST("/exchange-rates", h.SaveExchangeRate)         // Tambah / timpa kurs per tanggal
+			admin.DELETE("/exchange-rates/:id", h.DeleteExchangeRate) // Hapus kurs
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"time"
+)
+
+// AccountService: data akun secara utuh (arsip unduhan untuk pemilik akun)
+type AccountService struct {
+	users        repository.UserRepository
+	transactions repository.TransactionRepository
+	wallets      repository.WalletRepository
+	goals        repository.GoalRepository
+	statements   repository.StatementRepository
+	paymentLogs  repository.PaymentLogRepository
+	auditLogs    repository.AuditLogRepository
+}
+
+func NewAccountService(repos *repository.Repositories) *AccountService {
+	return &AccountService{
+		users:        repos.Users,
+		transactions: repos.Transactions,
+		wallets:      repos.Wallets,
+		goals:        repos.Goals,
+		statements:   repos.Statements,
+		paymentLogs:  repos.PaymentLogs,
+		auditLogs:    repos.AuditLogs,
+	}
+}
+
+// Archive mengumpulkan semua data yang disimpan tentang user: profil, pengaturan, transaksi
+// (termasuk tong sampah), dompet, target tabungan, rekening koran, bukti pembayaran & audit log aksinya sendiri
+func (s *AccountService) Archive(userID uint, now time.Time) (*exporter.Archive, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	archive := &exporter.Archive{User: *user, GeneratedAt: now.In(utils.UserLocation(user.Timezone))}
+
+	if archive.Transactions, err = s.transactions.ListAllForUser(userID); err != nil {
+		return nil, err
+	}
+	if archive.Wallets, err = s.wallets.ListForUser(userID); err != nil {
+		return nil, err
+	}
+	if archive.Goals, err = s.goals.ListForUser(userID); err != nil {
+		return nil, err
+	}
+	if archive.PaymentLogs, err = s.paymentLogs.ListForUser(userID); err != nil {
+		return nil, err
+	}
+
+	statements, err := s.statements.ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range statements {
+		entries, err := s.statements.Entries(statement.ID)
+		if err != nil {
+			return nil, err
+		}
+		archive.Statements = append(archive.Statements, exporter.ArchiveStatement{BankStatement: statement, Entries: entries})
+	}
+
+	err = s.auditLogs.Each(repository.AuditFilter{ActorID: &userID}, 500, func(batch []models.AuditLog) error {
+		archive.AuditLogs = append(archive.AuditLogs, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
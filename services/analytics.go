@@ -0,0 +1,272 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"math"
+	"sort"
+	"time"
+)
+
+// Interval pengelompokan tren (query ?interval=...)
+const (
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+// MaxTrendPoints: batas jumlah titik tren (mis. 5 tahun per minggu)
+const MaxTrendPoints = 260
+
+// TrendQuery: parameter tren. From/To = rentang [From, To) di zona waktu user, wajib berbatas.
+type TrendQuery struct {
+	Interval      string
+	From          time.Time
+	To            time.Time
+	MonthStartDay int       // awal bulan versi user (interval month & perbandingan kategori)
+	Now           time.Time // waktu sekarang di zona waktu user
+}
+
+// TrendPoint: total satu minggu / bulan / tahun (dalam mata uang dasar user).
+// Titik pertama & terakhir bisa terpotong mengikuti rentang.
+type TrendPoint struct {
+	Start   string `json:"start"`
+	End     string `json:"end"` // inklusif
+	Income  int64  `json:"income"`
+	Expense int64  `json:"expense"`
+	Net     int64  `json:"net"`
+}
+
+// CategoryTrend: total kategori di bulan acuan dibanding bulan sebelumnya (MoM)
+// dan bulan yang sama tahun lalu (YoY). Persen null kalau pembandingnya 0.
+type CategoryTrend struct {
+	Type          models.TransactionType `json:"type"`
+	Category      string                 `json:"category"`
+	Current       int64                  `json:"current"`
+	PreviousMonth int64                  `json:"previous_month"`
+	MonthDelta    int64                  `json:"mom_delta"`
+	MonthPercent  *float64               `json:"mom_percent"`
+	PreviousYear  int64                  `json:"previous_year"`
+	YearDelta     int64                  `json:"yoy_delta"`
+	YearPercent   *float64               `json:"yoy_percent"`
+}
+
+// Trends: ringkasan jangka panjang untuk dashboard
+type Trends struct {
+	Interval            string       `json:"interval"`
+	Series              []TrendPoint `json:"series"`
+	TotalIncome         int64        `json:"total_income"`
+	TotalExpense        int64        `json:"total_expense"`
+	Net                 int64        `json:"net"`
+	Days                int          `json:"days"` // hari dalam rentang, tidak termasuk hari yang belum lewat
+	AverageDailyExpense int64        `json:"average_daily_expense"`
+	SavingsRate         *float64     `json:"savings_rate"` // persen dari pemasukan, null kalau pemasukan 0
+	// ComparisonMonth: awal bulan acuan perbandingan kategori (bulan yang memuat hari terakhir rentang)
+	ComparisonMonth string          `json:"comparison_month"`
+	Categories      []CategoryTrend `json:"categories"`
+}
+
+// ValidInterval: interval tren yang dikenal
+func ValidInterval(interval string) bool {
+	switch interval {
+	case IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// Trends: pemasukan/pengeluaran/net per interval dalam [q.From, q.To) + perbandingan per kategori.
+// Semua dijumlahkan di database, jadi tidak perlu mengunduh seluruh transaksi.
+func (s *TransactionService) Trends(userID uint, q TrendQuery) (*Trends, string, error) {
+	if q.MonthStartDay < 1 || q.MonthStartDay > utils.MaxMonthStartDay {
+		q.MonthStartDay = 1
+	}
+	bounds, err := trendBounds(q.Interval, q.From, q.To, q.MonthStartDay)
+	if err != nil {
+		return nil, "", err
+	}
+	currency, err := s.BaseCurrency(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := s.transactions.Totals(repository.TotalsFilter{UserID: userID, BaseCurrency: currency, Buckets: bounds})
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := &Trends{Interval: q.Interval, Series: make([]TrendPoint, len(bounds)-1)}
+	for i := range result.Series {
+		result.Series[i].Start = bounds[i].Format("2006-01-02")
+		result.Series[i].End = bounds[i+1].AddDate(0, 0, -1).Format("2006-01-02")
+	}
+
+	conv := newConverter(s.rates)
+	for _, row := range rows {
+		if row.Bucket < 0 || row.Bucket >= len(result.Series) {
+			continue
+		}
+		amount, err := conv.Convert(row.Total, row.Currency, currency, rateDay(row))
+		if err != nil {
+			return nil, "", err
+		}
+		switch row.Type {
+		case models.TypeIncome:
+			result.Series[row.Bucket].Income += amount
+		case models.TypeExpense:
+			result.Series[row.Bucket].Expense += amount
+		}
+	}
+	for i := range result.Series {
+		point := &result.Series[i]
+		point.Net = point.Income - point.Expense
+		result.TotalIncome += point.Income
+		result.TotalExpense += point.Expense
+	}
+	result.Net = result.TotalIncome - result.TotalExpense
+
+	// Rata-rata harian hanya menghitung hari yang sudah lewat (rentang bisa sampai akhir tahun)
+	end := q.To
+	if tomorrow := startOfDay(q.Now).AddDate(0, 0, 1); end.After(tomorrow) {
+		end = tomorrow
+	}
+	if result.Days = daysBetween(q.From, end); result.Days > 0 {
+		result.AverageDailyExpense = roundDiv(result.TotalExpense, int64(result.Days))
+	}
+	result.SavingsRate = percent(result.Net, result.TotalIncome)
+
+	// Bulan acuan: bulan yang memuat hari terakhir rentang (atau hari ini kalau rentang belum selesai)
+	month := utils.MonthStart(end.AddDate(0, 0, -1), q.MonthStartDay)
+	result.ComparisonMonth = month.Format("2006-01-02")
+	if result.Categories, err = s.categoryTrends(userID, currency, month); err != nil {
+		return nil, "", err
+	}
+	return result, currency, nil
+}
+
+// categoryTrends: total per kategori bulan "month" vs bulan sebelumnya & bulan yang sama tahun lalu
+func (s *TransactionService) categoryTrends(userID uint, currency string, month time.Time) ([]CategoryTrend, error) {
+	lastYear := month.AddDate(-1, 0, 0)
+	previous := month.AddDate(0, -1, 0)
+	// Satu query: rentang ke-1 (antara bulan tahun lalu & bulan lalu) ikut dihitung tapi diabaikan
+	bounds := []time.Time{lastYear, lastYear.AddDate(0, 1, 0), previous, month, month.AddDate(0, 1, 0)}
+	rows, err := s.transactions.Totals(repository.TotalsFilter{
+		UserID: userID, BaseCurrency: currency, ByCategory: true, Buckets: bounds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type categoryKey struct {
+		Type     models.TransactionType
+		Category string
+	}
+	byKey := make(map[categoryKey]*CategoryTrend)
+	conv := newConverter(s.rates)
+	for _, row := range rows {
+		if row.Bucket == 1 {
+			continue
+		}
+		amount, err := conv.Convert(row.Total, row.Currency, currency, rateDay(row))
+		if err != nil {
+			return nil, err
+		}
+		key := categoryKey{row.Type, row.Category}
+		trend := byKey[key]
+		if trend == nil {
+			trend = &CategoryTrend{Type: row.Type, Category: row.Category}
+			byKey[key] = trend
+		}
+		switch row.Bucket {
+		case 0:
+			trend.PreviousYear += amount
+		case 2:
+			trend.PreviousMonth += amount
+		case 3:
+			trend.Current += amount
+		}
+	}
+
+	results := make([]CategoryTrend, 0, len(byKey))
+	for _, trend := range byKey {
+		trend.MonthDelta = trend.Current - trend.PreviousMonth
+		trend.MonthPercent = percent(trend.MonthDelta, trend.PreviousMonth)
+		trend.YearDelta = trend.Current - trend.PreviousYear
+		trend.YearPercent = percent(trend.YearDelta, trend.PreviousYear)
+		results = append(results, *trend)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		if results[i].Current != results[j].Current {
+			return results[i].Current > results[j].Current
+		}
+		return results[i].Category < results[j].Category
+	})
+	return results, nil
+}
+
+// trendBounds: batas tiap titik tren. Batas tengah mengikuti kalender (Senin, awal bulan user,
+// 1 Januari); batas pertama & terakhir = from & to. utils.ErrPeriodTooLong kalau titiknya kebanyakan.
+func trendBounds(interval string, from, to time.Time, monthStartDay int) ([]time.Time, error) {
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return nil, utils.ErrPeriodTooLong
+	}
+	var start time.Time
+	var step func(n int) time.Time
+	switch interval {
+	case IntervalWeek:
+		start = utils.WeekStart(from)
+		step = func(n int) time.Time { return start.AddDate(0, 0, 7*n) }
+	case IntervalYear:
+		start = time.Date(from.Year(), time.January, 1, 0, 0, 0, 0, from.Location())
+		step = func(n int) time.Time { return start.AddDate(n, 0, 0) }
+	default:
+		start = utils.MonthStart(from, monthStartDay)
+		step = func(n int) time.Time { return start.AddDate(0, n, 0) }
+	}
+
+	bounds := []time.Time{from}
+	for n := 1; ; n++ {
+		next := step(n)
+		if !next.Before(to) {
+			break
+		}
+		if len(bounds) >= MaxTrendPoints {
+			return nil, utils.ErrPeriodTooLong
+		}
+		bounds = append(bounds, next)
+	}
+	return append(bounds, to), nil
+}
+
+// startOfDay: tengah malam di hari & zona waktu yang sama
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween: jumlah hari kalender dari tengah malam "from" sampai "to" (aman walau ada DST)
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if !b.After(a) {
+		return 0
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// roundDiv: a/b dibulatkan ke minor unit terdekat
+func roundDiv(a, b int64) int64 {
+	return int64(math.Round(float64(a) / float64(b)))
+}
+
+// percent: part/whole dalam persen (1 angka desimal), nil kalau whole 0
+func percent(part, whole int64) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	value := math.Round(float64(part)*1000/float64(whole)) / 10
+	return &value
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"strings"
+	"time"
+)
+
+// BulkMaxItems: batas jumlah item sekali operasi massal (create / delete berdasarkan ID)
+const BulkMaxItems = 500
+
+// CreateMany memvalidasi semua transaksi dengan aturan yang sama seperti Create, lalu menyimpan
+// semuanya dalam satu transaksi database. Mata uang harus sudah ditentukan pemanggil (ResolveCurrency),
+// karena nominal baru bisa dibaca setelah mata uangnya diketahui.
+//
+// invalid berisi error yang sudah ditemukan pemanggil per indeks (mis. nominal tidak terbaca).
+// Item lain tetap divalidasi supaya semua error terlapor sekaligus. Kalau ada satu saja yang
+// tidak valid, tidak ada yang disimpan dan semua error dikembalikan lewat invalid.
+func (s *TransactionService) CreateMany(userID uint, trx []models.Transaction, invalid map[int]error) (map[int]error, string, error) {
+	if invalid == nil {
+		invalid = make(map[int]error)
+	}
+	now := time.Now()
+	hasExpense := false
+	for i := range trx {
+		if _, found := invalid[i]; found {
+			continue
+		}
+		t := &trx[i]
+		t.UserID = userID
+		if err := validateTransaction(t); err != nil {
+			invalid[i] = err
+			continue
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		hasExpense = hasExpense || t.Type == models.TypeExpense
+	}
+	if len(invalid) > 0 {
+		return invalid, "", nil
+	}
+
+	if err := s.transactions.CreateBatch(trx); err != nil {
+		return nil, "", err
+	}
+	if err := s.users.UpdateFields(userID, map[string]interface{}{"last_transaction_at": now}); err != nil {
+		return nil, "", err
+	}
+	if !hasExpense {
+		return nil, "", nil
+	}
+	return nil, s.CheckDailyLimit(userID, 0), nil
+}
+
+// Recategorize mengganti kategori from (tidak peka huruf besar/kecil) jadi to, misalnya merapikan
+// "makan" & "MAKAN" jadi "Makan". Bisa dibatasi tipe & daftar ID. Dipakai web & bot (/recat).
+func (s *TransactionService) Recategorize(userID uint, from, to string, typ models.TransactionType, ids []uint) ([]repository.CategoryChange, error) {
+	from = strings.TrimSpace(from)
+	if from == "" {
+		return nil, utils.ErrInvalidInput.WithField("from", utils.CodeFieldRequired)
+	}
+	to, err := normalizeCategory(to)
+	if err != nil {
+		return nil, utils.ErrInvalidInput.WithField("to", utils.CodeCategoryInvalid)
+	}
+	if typ != "" && !typ.Valid() {
+		return nil, utils.ErrTransactionTypeInvalid
+	}
+	return s.transactions.Recategorize(repository.TransactionFilter{
+		UserID:     userID,
+		IDs:        ids,
+		Type:       typ,
+		Categories: []string{from},
+	}, to)
+}
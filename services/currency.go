@@ -0,0 +1,114 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/repository"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	ErrWalletNotFound   = errors.New("dompet tidak ditemukan")
+	ErrCurrencyInvalid  = errors.New("mata uang tidak didukung")
+	ErrCurrencyMismatch = errors.New("mata uang berbeda dengan mata uang dompet")
+)
+
+// MissingRateError: kurs From->To (atau kebalikannya) belum diisi admin
+type MissingRateError struct {
+	From string
+	To   string
+}
+
+func (e *MissingRateError) Error() string {
+	return fmt.Sprintf("kurs %s->%s belum tersedia", e.From, e.To)
+}
+
+// converter mengubah nominal ke mata uang dasar user pakai kurs yang berlaku pada tanggal transaksi.
+// Dibuat per request supaya kurs cukup dibaca sekali per pasangan + tanggal.
+type converter struct {
+	rates repository.ExchangeRateRepository
+	cache map[string]*big.Rat
+}
+
+func newConverter(rates repository.ExchangeRateRepository) *converter {
+	return &converter{rates: rates, cache: make(map[string]*big.Rat)}
+}
+
+// Convert: amount (minor unit "from") jadi minor unit "to" dengan kurs per tanggal "at"
+func (c *converter) Convert(amount int64, from, to string, at time.Time) (int64, error) {
+	if from == "" {
+		from = money.DefaultCurrency
+	}
+	if from == to {
+		return amount, nil
+	}
+	rate, err := c.rate(from, to, at)
+	if err != nil {
+		return 0, err
+	}
+	return money.Convert(amount, from, to, rate)
+}
+
+// transactions: total base amount per transaksi, urutan sama dengan input
+func (c *converter) transactions(trx []models.Transaction, to string) ([]int64, error) {
+	amounts := make([]int64, len(trx))
+	for i, t := range trx {
+		amount, err := c.Convert(t.Amount, t.Currency, to, t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		amounts[i] = amount
+	}
+	return amounts, nil
+}
+
+func (c *converter) rate(from, to string, at time.Time) (*big.Rat, error) {
+	// Kurs berlaku per hari (tanggal efektif disimpan tengah malam UTC)
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	key := from + ">" + to + "@" + day.Format("2006-01-02")
+	if rate, ok := c.cache[key]; ok {
+		return rate, nil
+	}
+
+	rate, err := c.lookup(from, to, day)
+	if err != nil {
+		return nil, err
+	}
+	c.cache[key] = rate
+	return rate, nil
+}
+
+func (c *converter) lookup(from, to string, day time.Time) (*big.Rat, error) {
+	direct, err := c.rates.FindEffective(from, to, day)
+	if err == nil {
+		return money.ParseRate(direct.Rate)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	// Admin cukup isi satu arah, mis. USD->IDR juga dipakai untuk IDR->USD
+	inverse, err := c.rates.FindEffective(to, from, day)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, &MissingRateError{From: from, To: to}
+	}
+	if err != nil {
+		return nil, err
+	}
+	rate, err := money.ParseRate(inverse.Rate)
+	if err != nil {
+		return nil, err
+	}
+	return rate.Inv(rate), nil
+}
+
+// baseCurrency: mata uang dasar user (data lama tanpa isian dianggap IDR)
+func baseCurrency(user *models.User) string {
+	if code := money.Normalize(user.BaseCurrency); code != "" {
+		return code
+	}
+	return money.DefaultCurrency
+}
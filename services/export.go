@@ -0,0 +1,174 @@
+package services
+
+import (
+	"backend-gin/exporter"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"sort"
+	"time"
+)
+
+// ExportService menyiapkan isi laporan unduhan (format file ditulis package exporter)
+type ExportService struct {
+	users        repository.UserRepository
+	transactions repository.TransactionRepository
+	trx          *TransactionService
+}
+
+func NewExportService(users repository.UserRepository, transactions repository.TransactionRepository, trx *TransactionService) *ExportService {
+	return &ExportService{users: users, transactions: transactions, trx: trx}
+}
+
+// exportBatchSize: jumlah transaksi yang dibaca per query saat laporan ditulis
+const exportBatchSize = 500
+
+// Report: transaksi dalam [from, to) (zero = tanpa batas) terbaru dulu, nominal asli + hasil konversi
+// ke mata uang dasar, total & rincian per kategori. Tanggal ditulis di zona waktu loc.
+// Total dihitung di SQL; baris transaksi baru dibaca per batch saat file ditulis (Report.Rows).
+// Transaksi split ditulis satu baris per rincian, jadi ringkasan per kategori ikut rinciannya.
+func (s *ExportService) Report(userID uint, from, to time.Time, loc *time.Location) (*exporter.Report, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	base := baseCurrency(user)
+	conv := newConverter(s.trx.rates)
+
+	report := &exporter.Report{
+		Owner:       user.Username,
+		Currency:    base,
+		From:        from,
+		To:          to,
+		Location:    loc,
+		GeneratedAt: time.Now().In(loc),
+	}
+	if err := s.totals(report, conv, userID); err != nil {
+		return nil, err
+	}
+
+	report.Rows = func(each func(exporter.Row) error) error {
+		return s.transactions.EachInPeriod(userID, from, to, exportBatchSize, func(batch []models.Transaction) error {
+			lines, split := splitLines(batch)
+			for i, t := range lines {
+				amount, err := conv.Convert(t.Amount, t.Currency, base, t.CreatedAt)
+				if err != nil {
+					return err
+				}
+				err = each(exporter.Row{
+					ID:       t.ID,
+					Date:     t.CreatedAt.In(loc),
+					Type:     t.Type,
+					Category: t.Category,
+					Note:     t.Note,
+					Currency: t.Currency,
+					Amount:   t.Amount,
+					Base:     amount,
+					WalletID: t.WalletID,
+					Split:    split[i],
+					Tags:     tagNames(t.Tags),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return report, nil
+}
+
+// totals mengisi jumlah transaksi, pemasukan, pengeluaran & rincian per kategori dari agregasi SQL
+func (s *ExportService) totals(report *exporter.Report, conv *converter, userID uint) error {
+	filter := repository.TotalsFilter{UserID: userID, From: report.From, To: report.To, BaseCurrency: report.Currency}
+	rows, err := s.transactions.Totals(filter)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		amount, err := conv.Convert(row.Total, row.Currency, report.Currency, rateDay(row))
+		if err != nil {
+			return err
+		}
+		// Tanpa join rincian: transaksi split dihitung sekali
+		report.Count += int(row.Count)
+		if row.Type == models.TypeIncome {
+			report.Income += amount
+		} else {
+			report.Expense += amount
+		}
+	}
+
+	filter.ByCategory = true
+	if rows, err = s.transactions.Totals(filter); err != nil {
+		return err
+	}
+	type categoryKey struct {
+		Type     models.TransactionType
+		Category string
+	}
+	index := make(map[categoryKey]int)
+	for _, row := range rows {
+		amount, err := conv.Convert(row.Total, row.Currency, report.Currency, rateDay(row))
+		if err != nil {
+			return err
+		}
+		key := categoryKey{row.Type, row.Category}
+		j, ok := index[key]
+		if !ok {
+			j = len(report.Categories)
+			index[key] = j
+			report.Categories = append(report.Categories, exporter.CategoryTotal{Type: row.Type, Category: row.Category})
+		}
+		report.Categories[j].Count += int(row.Count)
+		report.Categories[j].Total += amount
+	}
+
+	// Sama dengan CategorySummary: per tipe, lalu total terbesar
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Category < b.Category
+	})
+	return nil
+}
+
+// tagNames: nama tag transaksi untuk kolom "Tag" (nil kalau tidak ada)
+func tagNames(tags []models.Tag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// splitLines: transaksi split dipecah jadi satu transaksi per baris rincian (ID, tanggal & mata uang
+// ikut induk, catatan induk dipakai kalau baris tidak punya catatan). split[i] = true untuk baris rincian.
+func splitLines(trx []models.Transaction) (lines []models.Transaction, split []bool) {
+	lines = make([]models.Transaction, 0, len(trx))
+	split = make([]bool, 0, len(trx))
+	for _, t := range trx {
+		if len(t.Splits) == 0 {
+			lines = append(lines, t)
+			split = append(split, false)
+			continue
+		}
+		for _, s := range t.Splits {
+			line := t
+			line.Category, line.Amount, line.Splits = s.Category, s.Amount, nil
+			if s.Note != "" {
+				line.Note = s.Note
+			}
+			lines = append(lines, line)
+			split = append(split, true)
+		}
+	}
+	return lines, split
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GoalNameMaxLength: batas nama target tabungan (sama dengan ukuran kolom)
+const GoalNameMaxLength = 100
+
+var ErrGoalNotFound = errors.New("target tabungan tidak ditemukan")
+
+// GoalService: target tabungan & progresnya (dihitung dari transaksi, lihat models.SavingsGoal)
+type GoalService struct {
+	goals        repository.GoalRepository
+	transactions repository.TransactionRepository
+	trx          *TransactionService
+}
+
+func NewGoalService(goals repository.GoalRepository, transactions repository.TransactionRepository,
+	trx *TransactionService) *GoalService {
+	return &GoalService{goals: goals, transactions: transactions, trx: trx}
+}
+
+// GoalProgress: target beserta isi tabungan saat ini
+type GoalProgress struct {
+	models.SavingsGoal
+	Saved     int64   `json:"saved"`     // Minor unit dalam Currency, bisa negatif kalau lebih banyak ditarik
+	Remaining int64   `json:"remaining"` // 0 kalau sudah tercapai
+	Percent   float64 `json:"percent"`   // 0-100, satu angka desimal
+	Reached   bool    `json:"reached"`
+	// JustReached: baru tercapai sejak dicek terakhir kali (waktunya memberi ucapan selamat)
+	JustReached bool `json:"just_reached"`
+	// Tanpa deadline: nil. Lewat deadline: 0 bulan & sisa harus disetor sekaligus.
+	MonthsLeft     *int   `json:"months_left"`
+	MonthlyNeeded  *int64 `json:"monthly_needed"` // Setoran per bulan supaya tercapai tepat waktu
+	DeadlinePassed bool   `json:"deadline_passed"`
+}
+
+// GoalInput: data target baru (nominal sudah di-parse handler dalam mata uang target)
+type GoalInput struct {
+	Name         string
+	TargetAmount int64
+	Currency     string
+	Deadline     *time.Time // tengah malam hari terakhir di zona waktu user
+	WalletID     *uint
+	Category     string
+}
+
+// ResolveCurrency: mata uang target baru (ikut dompet kalau tertaut, lalu input, lalu mata uang dasar user)
+func (s *GoalService) ResolveCurrency(userID uint, walletID *uint, category, currency string) (string, error) {
+	if err := goalLink(walletID, category); err != nil {
+		return "", err
+	}
+	return s.trx.ResolveCurrency(userID, walletID, currency)
+}
+
+// goalLink: target tertaut ke dompet ATAU kategori, tidak keduanya
+func goalLink(walletID *uint, category string) error {
+	if (walletID == nil) == (strings.TrimSpace(category) == "") {
+		return utils.ErrGoalLinkInvalid
+	}
+	return nil
+}
+
+// Create menyimpan target baru. Tertaut ke dompet ATAU kategori, tidak keduanya.
+func (s *GoalService) Create(userID uint, input GoalInput, now time.Time) (*models.SavingsGoal, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > GoalNameMaxLength {
+		return nil, utils.ErrInvalidInput.WithField("name", utils.CodeFieldInvalid)
+	}
+	if input.TargetAmount <= 0 {
+		return nil, utils.ErrInvalidInput.WithField("target_amount", utils.CodeAmountNotPositive)
+	}
+	category := strings.TrimSpace(input.Category)
+	if err := goalLink(input.WalletID, category); err != nil {
+		return nil, err
+	}
+	if category != "" {
+		if _, err := normalizeCategory(category); err != nil {
+			return nil, err
+		}
+	}
+	if input.Deadline != nil && input.Deadline.Before(startOfDay(now.In(input.Deadline.Location()))) {
+		return nil, utils.ErrGoalDeadlineInvalid
+	}
+	currency, err := s.trx.ResolveCurrency(userID, input.WalletID, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	goal := &models.SavingsGoal{
+		UserID:       userID,
+		Name:         name,
+		TargetAmount: input.TargetAmount,
+		Currency:     currency,
+		Deadline:     input.Deadline,
+		WalletID:     input.WalletID,
+		Category:     category,
+		CreatedAt:    now,
+	}
+	if err := s.goals.Create(goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// Find: target milik user, ErrGoalNotFound kalau tidak ada
+func (s *GoalService) Find(userID, id uint) (*models.SavingsGoal, error) {
+	goal, err := s.goals.FindForUser(userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrGoalNotFound
+	}
+	return goal, err
+}
+
+// List: progres semua target user, urut waktu dibuat
+func (s *GoalService) List(userID uint, now time.Time, loc *time.Location) ([]GoalProgress, error) {
+	goals, err := s.goals.ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]GoalProgress, len(goals))
+	for i := range goals {
+		progress, err := s.Progress(&goals[i], now, loc)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = *progress
+	}
+	return results, nil
+}
+
+// Progress menghitung isi tabungan & setoran per bulan yang dibutuhkan. Target yang baru tercapai
+// ditandai (ReachedAt) supaya ucapan selamat hanya muncul sekali.
+func (s *GoalService) Progress(goal *models.SavingsGoal, now time.Time, loc *time.Location) (*GoalProgress, error) {
+	saved, err := s.saved(goal)
+	if err != nil {
+		return nil, err
+	}
+	p := &GoalProgress{SavingsGoal: *goal, Saved: saved}
+	p.Remaining = goal.TargetAmount - saved
+	if p.Remaining <= 0 {
+		p.Remaining, p.Reached = 0, true
+	}
+	if saved > 0 {
+		p.Percent = math.Min(100, math.Round(float64(saved)*1000/float64(goal.TargetAmount))/10)
+	}
+
+	if p.Reached && goal.ReachedAt == nil {
+		err := s.goals.MarkReached(goal, now)
+		switch {
+		case err == nil:
+			p.ReachedAt, p.JustReached = goal.ReachedAt, true
+		case !errors.Is(err, repository.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	if goal.Deadline != nil && !p.Reached {
+		months := monthsUntil(now.In(loc), goal.Deadline.In(loc))
+		p.MonthsLeft = &months
+		monthly := p.Remaining
+		if months > 0 {
+			monthly = (p.Remaining + int64(months) - 1) / int64(months)
+		} else {
+			p.DeadlinePassed = true
+		}
+		p.MonthlyNeeded = &monthly
+	}
+	return p, nil
+}
+
+// saved: dompet = saldo semua transaksinya; kategori = keluar - masuk sejak target dibuat
+// (termasuk baris rincian split), dikonversi ke mata uang target
+func (s *GoalService) saved(goal *models.SavingsGoal) (int64, error) {
+	filter := repository.TotalsFilter{UserID: goal.UserID, BaseCurrency: goal.Currency}
+	if goal.WalletID != nil {
+		filter.WalletID = goal.WalletID
+	} else {
+		filter.ByCategory = true
+		filter.From = goal.CreatedAt
+	}
+	rows, err := s.transactions.Totals(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var saved int64
+	conv := newConverter(s.trx.rates)
+	for _, row := range rows {
+		if goal.WalletID == nil && !strings.EqualFold(row.Category, goal.Category) {
+			continue
+		}
+		amount, err := conv.Convert(row.Total, row.Currency, goal.Currency, rateDay(row))
+		if err != nil {
+			return 0, err
+		}
+		// Dompet tabungan bertambah dari pemasukan; kategori tabungan dari uang yang disisihkan (pengeluaran)
+		if (row.Type == models.TypeIncome) == (goal.WalletID != nil) {
+			saved += amount
+		} else {
+			saved -= amount
+		}
+	}
+	return saved, nil
+}
+
+// Contribute mencatat setoran ke target sebagai transaksi: pemasukan ke dompet tertaut,
+// atau pengeluaran berkategori target. Progres dihitung ulang setelah transaksi tersimpan.
+func (s *GoalService) Contribute(goal *models.SavingsGoal, amount int64, note string, now time.Time,
+	loc *time.Location) (*models.Transaction, *GoalProgress, string, error) {
+	trx := &models.Transaction{
+		UserID:   goal.UserID,
+		Amount:   amount,
+		Currency: goal.Currency,
+		WalletID: goal.WalletID,
+		Type:     models.TypeExpense,
+		Category: goal.Category,
+		Note:     strings.TrimSpace(note),
+	}
+	if goal.WalletID != nil {
+		trx.Type, trx.Category = models.TypeIncome, models.GoalCategory
+	}
+	if trx.Note == "" {
+		trx.Note = goal.Name
+	}
+	alert, err := s.trx.Create(trx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	progress, err := s.Progress(goal, now, loc)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return trx, progress, alert, nil
+}
+
+// Delete menghapus target saja; transaksi setorannya tetap ada
+func (s *GoalService) Delete(goal *models.SavingsGoal) error {
+	return s.goals.Delete(goal)
+}
+
+// monthsUntil: jumlah bulan (dibulatkan ke atas) dari hari ini sampai deadline, minimal 1 selama
+// deadline belum lewat. 0 kalau deadline sudah lewat.
+func monthsUntil(now, deadline time.Time) int {
+	today, last := startOfDay(now), startOfDay(deadline)
+	if last.Before(today) {
+		return 0
+	}
+	months := (last.Year()-today.Year())*12 + int(last.Month()-today.Month())
+	if last.Day() > today.Day() {
+		months++
+	}
+	if months < 1 {
+		months = 1
+	}
+	return months
+}
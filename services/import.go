@@ -0,0 +1,155 @@
+package services
+
+import (
+	"backend-gin/importer"
+	"backend-gin/models"
+	"backend-gin/utils"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ImportOptions: pengaturan langkah cek / simpan hasil import
+type ImportOptions struct {
+	Location *time.Location // zona waktu user, dasar pencocokan duplikat per tanggal
+	Commit   bool           // false = dry-run (hanya laporan)
+	// SkipInvalid: simpan baris yang valid saja. Tanpa ini commit ditolak kalau ada baris error.
+	SkipInvalid bool
+	// IncludeDuplicates: baris yang sudah ada di database tetap disimpan
+	IncludeDuplicates bool
+}
+
+// ImportRow: baris file + hasil cek
+type ImportRow struct {
+	importer.Row
+	Duplicate bool
+	Import    bool // ikut disimpan saat commit
+}
+
+// ImportReport: laporan dry-run / commit
+type ImportReport struct {
+	Rows       []ImportRow
+	Valid      int
+	Invalid    int
+	Duplicates int
+	Importable int
+	Committed  bool
+	Imported   int
+}
+
+// Import memeriksa baris hasil baca file (aturan sama dengan input manual), menandai duplikat
+// terhadap transaksi yang sudah ada, lalu kalau opts.Commit menyimpan semuanya sekaligus:
+// berhasil semua atau tidak ada yang tersimpan.
+//
+// Duplikat = tanggal (zona waktu user), tipe, nominal & mata uang sama. Dihitung per kemunculan:
+// dua kopi Rp 20.000 di file vs satu di database = satu duplikat, satu baru.
+func (s *TransactionService) Import(userID uint, rows []importer.Row, opts ImportOptions) (*ImportReport, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	report := &ImportReport{Rows: make([]ImportRow, len(rows))}
+	var from, to time.Time
+	for i, row := range rows {
+		if row.Valid() {
+			trx := models.Transaction{Type: row.Type, Amount: row.Amount, Category: row.Category, Note: row.Note}
+			if err := validateTransaction(&trx); err != nil {
+				row.Field, row.Code = importErrorField(err)
+			}
+			row.Type, row.Category = trx.Type, trx.Category
+		}
+		report.Rows[i].Row = row
+		if !row.Valid() {
+			report.Invalid++
+			continue
+		}
+
+		report.Valid++
+		day := row.Date.In(loc)
+		day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		if from.IsZero() || day.Before(from) {
+			from = day
+		}
+		if next := day.AddDate(0, 0, 1); next.After(to) {
+			to = next
+		}
+	}
+
+	if report.Valid > 0 {
+		existing, err := s.transactions.FindInPeriod(userID, from, to, false)
+		if err != nil {
+			return nil, err
+		}
+		counts := make(map[string]int, len(existing))
+		for _, trx := range existing {
+			counts[importKey(trx.CreatedAt, trx.Type, trx.Amount, trx.Currency, loc)]++
+		}
+		for i := range report.Rows {
+			row := &report.Rows[i]
+			if !row.Valid() {
+				continue
+			}
+			if key := importKey(row.Date, row.Type, row.Amount, row.Currency, loc); counts[key] > 0 {
+				counts[key]--
+				row.Duplicate = true
+				report.Duplicates++
+			}
+			if row.Import = !row.Duplicate || opts.IncludeDuplicates; row.Import {
+				report.Importable++
+			}
+		}
+	}
+
+	if !opts.Commit {
+		return report, nil
+	}
+	if report.Invalid > 0 && !opts.SkipInvalid {
+		return nil, utils.ErrImportHasErrors.WithArgs(report.Invalid)
+	}
+
+	batch := make([]models.Transaction, 0, report.Importable)
+	for _, row := range report.Rows {
+		if !row.Import {
+			continue
+		}
+		trx := models.Transaction{
+			UserID:    userID,
+			Type:      row.Type,
+			Amount:    row.Amount,
+			Currency:  row.Currency,
+			Category:  row.Category,
+			Note:      row.Note,
+			CreatedAt: row.Date,
+		}
+		// Hashtag di catatan file ikut jadi tag, sama seperti input manual
+		trx.Tags = noteTags(&trx)
+		batch = append(batch, trx)
+	}
+	if err := s.transactions.CreateBatch(batch); err != nil {
+		return nil, err
+	}
+	// Waktu input terakhir = waktu import, bukan tanggal baris file (bisa mutasi bulan lalu)
+	if len(batch) > 0 {
+		if err := s.users.UpdateFields(userID, map[string]interface{}{"last_transaction_at": time.Now()}); err != nil {
+			return nil, err
+		}
+	}
+	report.Committed = true
+	report.Imported = len(batch)
+	return report, nil
+}
+
+// importKey: kunci pencocokan duplikat
+func importKey(at time.Time, typ models.TransactionType, amount int64, currency string, loc *time.Location) string {
+	return fmt.Sprintf("%s|%s|%d|%s", at.In(loc).Format("2006-01-02"), typ, amount, currency)
+}
+
+// importErrorField: field & kode dari error validasi transaksi
+func importErrorField(err error) (string, string) {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) && len(appErr.Details) > 0 {
+		return appErr.Details[0].Field, appErr.Details[0].Code
+	}
+	return "", utils.CodeInvalidInput
+}
@@ -0,0 +1,25 @@
+package services
+
+import "backend-gin/repository"
+
+// Services mengumpulkan logika bisnis yang dipakai bersama oleh web (handlers) dan bot Telegram
+type Services struct {
+	Transactions *TransactionService
+	Users        *UserService
+	Statements   *StatementService
+	Exports      *ExportService
+	Accounts     *AccountService
+	Goals        *GoalService
+}
+
+func New(repos *repository.Repositories) *Services {
+	trxService := NewTransactionService(repos.Users, repos.Transactions, repos.Wallets, repos.ExchangeRates)
+	return &Services{
+		Transactions: trxService,
+		Users:        NewUserService(repos.Users, repos.Transactions, trxService),
+		Statements:   NewStatementService(repos.Statements, repos.Transactions, trxService),
+		Exports:      NewExportService(repos.Users, repos.Transactions, trxService),
+		Accounts:     NewAccountService(repos),
+		Goals:        NewGoalService(repos.Goals, repos.Transactions, trxService),
+	}
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"backend-gin/importer"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Jendela pencocokan tanggal mutasi bank vs transaksi (hari). Transfer / kartu sering dibukukan 1-3 hari kemudian.
+const (
+	DefaultMatchWindow = 3
+	MaxMatchWindow     = 14
+)
+
+var (
+	ErrStatementNotFound      = errors.New("rekening koran tidak ditemukan")
+	ErrStatementEntryNotFound = errors.New("mutasi rekening koran tidak ditemukan")
+	ErrStatementEntryLinked   = errors.New("mutasi / transaksi sudah tertaut")
+	ErrTransactionNotFound    = errors.New("transaksi tidak ditemukan")
+)
+
+// StatementService: simpan rekening koran & cocokkan mutasinya dengan transaksi yang dicatat user
+type StatementService struct {
+	statements   repository.StatementRepository
+	transactions repository.TransactionRepository
+	trx          *TransactionService
+}
+
+func NewStatementService(statements repository.StatementRepository, transactions repository.TransactionRepository,
+	trx *TransactionService) *StatementService {
+	return &StatementService{statements: statements, transactions: transactions, trx: trx}
+}
+
+// StatementMatch: pasangan mutasi bank & transaksi di aplikasi
+type StatementMatch struct {
+	Entry       models.StatementEntry `json:"entry"`
+	Transaction models.Transaction    `json:"transaction"`
+	Linked      bool                  `json:"linked"`   // ditautkan user (atau transaksinya dibuat dari mutasi), bukan tebakan
+	DayDiff     int                   `json:"day_diff"` // selisih hari tanggal bank vs tanggal catatan
+}
+
+// Reconciliation: hasil pencocokan satu rekening koran
+type Reconciliation struct {
+	Statement    models.BankStatement    `json:"statement"`
+	Window       int                     `json:"window"`
+	Matched      []StatementMatch        `json:"matched"`
+	MissingInApp []models.StatementEntry `json:"missing_in_app"` // ada di bank, belum dicatat
+	ExtraInApp   []models.Transaction    `json:"extra_in_app"`   // dicatat di periode ini, tidak ada di bank
+}
+
+// Save menyimpan hasil importer.ParseStatement
+func (s *StatementService) Save(userID uint, fileName string, parsed *importer.Statement) (*models.BankStatement, error) {
+	statement := &models.BankStatement{
+		UserID:        userID,
+		Bank:          parsed.Bank,
+		AccountNumber: parsed.AccountNumber,
+		FileName:      fileName,
+		Currency:      parsed.Currency,
+		PeriodFrom:    parsed.PeriodFrom,
+		PeriodTo:      parsed.PeriodTo,
+		CreatedAt:     time.Now(),
+	}
+	entries := make([]models.StatementEntry, len(parsed.Entries))
+	for i, e := range parsed.Entries {
+		entries[i] = models.StatementEntry{
+			Line:        e.Line,
+			PostedAt:    e.Date,
+			Description: e.Description,
+			Type:        e.Type,
+			Amount:      e.Amount,
+			Currency:    parsed.Currency,
+			Balance:     e.Balance,
+			Pending:     e.Pending,
+		}
+	}
+	if err := s.statements.Create(statement, entries); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// Reconcile mencocokkan mutasi dengan transaksi user. Tautan manual selalu dipakai; sisanya dicocokkan
+// otomatis: tipe, nominal & mata uang sama persis, tanggal (zona waktu user) selisih maksimal window hari.
+// Kalau ada beberapa kandidat, yang tanggalnya paling dekat lalu keterangannya paling mirip yang dipilih.
+func (s *StatementService) Reconcile(userID, statementID uint, window int, loc *time.Location) (*Reconciliation, error) {
+	statement, err := s.statement(userID, statementID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.statements.Entries(statement.ID)
+	if err != nil {
+		return nil, err
+	}
+	linked, err := s.linkedTransactions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := startOfDay(statement.PeriodFrom.In(loc)).AddDate(0, 0, -window)
+	to := startOfDay(statement.PeriodTo.In(loc)).AddDate(0, 0, window+1)
+	candidates, err := s.transactions.FindInPeriod(userID, from, to, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Transaction, len(candidates))
+	for _, trx := range candidates {
+		byID[trx.ID] = trx
+	}
+
+	rec := &Reconciliation{
+		Statement:    *statement,
+		Window:       window,
+		Matched:      []StatementMatch{},
+		MissingInApp: []models.StatementEntry{},
+		ExtraInApp:   []models.Transaction{},
+	}
+
+	// 1. Tautan manual (transaksi yang sudah dihapus dianggap belum tercatat)
+	var open []models.StatementEntry
+	for _, entry := range entries {
+		if entry.TransactionID != nil {
+			trx, ok := byID[*entry.TransactionID]
+			if !ok {
+				// Tanggal transaksi di luar jendela (diubah user setelah ditautkan)
+				if found, err := s.transactions.FindForUser(userID, *entry.TransactionID); err == nil {
+					trx, ok = *found, true
+				}
+			}
+			if ok {
+				rec.Matched = append(rec.Matched, StatementMatch{
+					Entry: entry, Transaction: trx, Linked: true, DayDiff: dayDiff(entry.PostedAt, trx.CreatedAt, loc),
+				})
+				continue
+			}
+		}
+		open = append(open, entry)
+	}
+
+	// 2. Pencocokan otomatis: semua pasangan yang mungkin, lalu ambil yang terbaik lebih dulu
+	var available []models.Transaction
+	for _, trx := range candidates {
+		if !linked[trx.ID] {
+			available = append(available, trx)
+		}
+	}
+	type pair struct {
+		entry, trx  int
+		days, score int
+	}
+	var pairs []pair
+	for i, entry := range open {
+		words := matchWords(entry.Description)
+		for j, trx := range available {
+			if trx.Type != entry.Type || trx.Amount != entry.Amount || trx.Currency != entry.Currency {
+				continue
+			}
+			days := dayDiff(entry.PostedAt, trx.CreatedAt, loc)
+			if days > window {
+				continue
+			}
+			pairs = append(pairs, pair{entry: i, trx: j, days: days, score: sharedWords(words, trx.Category+" "+trx.Note)})
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool {
+		if pairs[a].days != pairs[b].days {
+			return pairs[a].days < pairs[b].days
+		}
+		return pairs[a].score > pairs[b].score
+	})
+	entryUsed := make([]bool, len(open))
+	trxUsed := make([]bool, len(available))
+	for _, p := range pairs {
+		if entryUsed[p.entry] || trxUsed[p.trx] {
+			continue
+		}
+		entryUsed[p.entry], trxUsed[p.trx] = true, true
+		rec.Matched = append(rec.Matched, StatementMatch{Entry: open[p.entry], Transaction: available[p.trx], DayDiff: p.days})
+	}
+
+	for i, entry := range open {
+		if !entryUsed[i] {
+			rec.MissingInApp = append(rec.MissingInApp, entry)
+		}
+	}
+	// Transaksi di luar periode rekening koran (hanya masuk jendela) bukan urusan rekening koran ini
+	periodEnd := startOfDay(statement.PeriodTo.In(loc)).AddDate(0, 0, 1)
+	for j, trx := range available {
+		if trxUsed[j] || trx.Currency != statement.Currency {
+			continue
+		}
+		if at := trx.CreatedAt.In(loc); at.Before(startOfDay(statement.PeriodFrom.In(loc))) || !at.Before(periodEnd) {
+			continue
+		}
+		rec.ExtraInApp = append(rec.ExtraInApp, trx)
+	}
+	sort.SliceStable(rec.Matched, func(a, b int) bool { return rec.Matched[a].Entry.Line < rec.Matched[b].Entry.Line })
+	return rec, nil
+}
+
+// CreateFromEntry mencatat mutasi yang belum ada di aplikasi sebagai transaksi baru lalu menautkannya.
+// category kosong = importer.DefaultCategory, note kosong = keterangan mutasi.
+func (s *StatementService) CreateFromEntry(userID, statementID, entryID uint, category, note string) (*models.Transaction, string, error) {
+	entry, err := s.openEntry(userID, statementID, entryID)
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(category) == "" {
+		category = importer.DefaultCategory
+	}
+	if strings.TrimSpace(note) == "" {
+		note = entry.Description
+	}
+
+	trx := &models.Transaction{
+		UserID:    userID,
+		Type:      entry.Type,
+		Amount:    entry.Amount,
+		Currency:  entry.Currency,
+		Category:  category,
+		Note:      note,
+		CreatedAt: entry.PostedAt,
+	}
+	if err := s.trx.prepare(trx); err != nil {
+		return nil, "", err
+	}
+	// Simpan & tautkan sekaligus: double klik tidak boleh membuat dua transaksi untuk satu mutasi
+	err = s.statements.CreateEntryTransaction(entry, trx)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, "", ErrStatementEntryLinked
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	alert, err := s.trx.afterCreate(trx)
+	if err != nil {
+		return nil, "", err
+	}
+	return trx, alert, nil
+}
+
+// Link menautkan mutasi ke transaksi yang sudah ada (cocok manual, nominal / tanggal boleh beda)
+func (s *StatementService) Link(userID, statementID, entryID, transactionID uint) error {
+	entry, err := s.openEntry(userID, statementID, entryID)
+	if err != nil {
+		return err
+	}
+	trx, err := s.transactions.FindForUser(userID, transactionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTransactionNotFound
+		}
+		return err
+	}
+	// Cek "belum tertaut" ikut di dalam UPDATE-nya, supaya dua request bersamaan tidak sama-sama lolos
+	err = s.statements.LinkEntry(userID, entry, trx.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrStatementEntryLinked
+	}
+	return err
+}
+
+// Unlink melepas tautan mutasi (mutasi kembali ikut pencocokan otomatis)
+func (s *StatementService) Unlink(userID, statementID, entryID uint) error {
+	entry, err := s.entry(userID, statementID, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.TransactionID == nil {
+		return nil
+	}
+	return s.statements.SetEntryTransaction(entry.ID, nil)
+}
+
+func (s *StatementService) statement(userID, id uint) (*models.BankStatement, error) {
+	statement, err := s.statements.FindForUser(userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrStatementNotFound
+	}
+	return statement, err
+}
+
+func (s *StatementService) entry(userID, statementID, entryID uint) (*models.StatementEntry, error) {
+	statement, err := s.statement(userID, statementID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.statements.FindEntry(statement.ID, entryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrStatementEntryNotFound
+	}
+	return entry, err
+}
+
+// openEntry: mutasi yang belum tertaut ke transaksi yang masih ada
+func (s *StatementService) openEntry(userID, statementID, entryID uint) (*models.StatementEntry, error) {
+	entry, err := s.entry(userID, statementID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.TransactionID != nil {
+		if _, err := s.transactions.FindForUser(userID, *entry.TransactionID); err == nil {
+			return nil, ErrStatementEntryLinked
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+func (s *StatementService) linkedTransactions(userID uint) (map[uint]bool, error) {
+	ids, err := s.statements.LinkedTransactionIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		linked[id] = true
+	}
+	return linked, nil
+}
+
+// dayDiff: selisih hari kalender (tanpa tanda) di zona waktu user
+func dayDiff(a, b time.Time, loc *time.Location) int {
+	a, b = a.In(loc), b.In(loc)
+	if b.Before(a) {
+		a, b = b, a
+	}
+	return daysBetween(a, b)
+}
+
+// matchWords: kata (minimal 3 huruf) dari keterangan, untuk membandingkan dengan kategori & catatan
+func matchWords(text string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) >= 3 {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+func sharedWords(words map[string]bool, text string) int {
+	n := 0
+	for w := range matchWords(text) {
+		if words[w] {
+			n++
+		}
+	}
+	return n
+}
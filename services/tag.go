@@ -0,0 +1,69 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"sort"
+	"time"
+)
+
+// TagStats: total satu tag dalam mata uang dasar user
+type TagStats struct {
+	Tag     string `json:"tag"`
+	Count   int64  `json:"count"` // jumlah transaksi
+	Income  int64  `json:"income"`
+	Expense int64  `json:"expense"`
+	Net     int64  `json:"net"`
+}
+
+// TagSummary: total per tag dalam [from, to) (zero = tanpa batas), urut pengeluaran terbesar.
+// Transaksi dengan beberapa tag dihitung di tiap tagnya, jadi jumlah semua tag bisa melebihi total periode.
+func (s *TransactionService) TagSummary(userID uint, from, to time.Time) ([]TagStats, string, error) {
+	currency, err := s.BaseCurrency(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := s.transactions.Totals(repository.TotalsFilter{
+		UserID: userID, From: from, To: to, ByTag: true, BaseCurrency: currency,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	index := make(map[string]int)
+	results := []TagStats{}
+	conv := newConverter(s.rates)
+	for _, row := range rows {
+		amount, err := conv.Convert(row.Total, row.Currency, currency, rateDay(row))
+		if err != nil {
+			return nil, "", err
+		}
+		i, ok := index[row.Tag]
+		if !ok {
+			i = len(results)
+			index[row.Tag] = i
+			results = append(results, TagStats{Tag: row.Tag})
+		}
+		results[i].Count += row.Count
+		switch row.Type {
+		case models.TypeIncome:
+			results[i].Income += amount
+		case models.TypeExpense:
+			results[i].Expense += amount
+		}
+	}
+	for i := range results {
+		results[i].Net = results[i].Income - results[i].Expense
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Expense != b.Expense {
+			return a.Expense > b.Expense
+		}
+		if a.Income != b.Income {
+			return a.Income > b.Income
+		}
+		return a.Tag < b.Tag
+	})
+	return results, currency, nil
+}
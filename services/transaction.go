@@ -0,0 +1,398 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/money"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type TransactionService struct {
+	users        repository.UserRepository
+	transactions repository.TransactionRepository
+	wallets      repository.WalletRepository
+	rates        repository.ExchangeRateRepository
+}
+
+func NewTransactionService(users repository.UserRepository, transactions repository.TransactionRepository,
+	wallets repository.WalletRepository, rates repository.ExchangeRateRepository) *TransactionService {
+	return &TransactionService{users: users, transactions: transactions, wallets: wallets, rates: rates}
+}
+
+// Statistik harian untuk grafik (dalam mata uang dasar user)
+type DailyStats struct {
+	Date    string `json:"date"`
+	Income  int64  `json:"income"`
+	Expense int64  `json:"expense"`
+}
+
+// Total per kategori (dalam mata uang dasar user)
+type CategoryStats struct {
+	Category string                 `json:"category"`
+	Total    int64                  `json:"total"`
+	Type     models.TransactionType `json:"type"`
+}
+
+// ResolveCurrency menentukan mata uang transaksi baru:
+// ikut dompet kalau ada, lalu input user, terakhir mata uang dasar user
+func (s *TransactionService) ResolveCurrency(userID uint, walletID *uint, currency string) (string, error) {
+	if currency != "" {
+		if currency = money.Normalize(currency); currency == "" {
+			return "", ErrCurrencyInvalid
+		}
+	}
+
+	if walletID != nil {
+		wallet, err := s.wallets.FindForUser(userID, *walletID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrWalletNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		if currency != "" && currency != wallet.Currency {
+			return "", ErrCurrencyMismatch
+		}
+		return wallet.Currency, nil
+	}
+
+	if currency != "" {
+		return currency, nil
+	}
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return baseCurrency(user), nil
+}
+
+// Create menyimpan transaksi (Dipakai oleh Web & Bot), mencatat waktu input terakhir user,
+// lalu mengembalikan pesan alert kalau pengeluaran hari ini sudah melewati limit
+func (s *TransactionService) Create(trx *models.Transaction) (string, error) {
+	if err := s.prepare(trx); err != nil {
+		return "", err
+	}
+	if err := s.transactions.Create(trx); err != nil {
+		return "", err
+	}
+	return s.afterCreate(trx)
+}
+
+// prepare: validasi + isi waktu & mata uang default sebelum transaksi baru disimpan
+func (s *TransactionService) prepare(trx *models.Transaction) error {
+	if err := validateTransaction(trx); err != nil {
+		return err
+	}
+	if trx.CreatedAt.IsZero() {
+		trx.CreatedAt = time.Now()
+	}
+	currency, err := s.ResolveCurrency(trx.UserID, trx.WalletID, trx.Currency)
+	if err != nil {
+		return err
+	}
+	trx.Currency = currency
+	return nil
+}
+
+// afterCreate: langkah setelah transaksi baru tersimpan, hasilnya peringatan limit harian (kalau ada)
+func (s *TransactionService) afterCreate(trx *models.Transaction) (string, error) {
+	// Catat waktu input terakhir (dipakai admin untuk lihat user yang aktif)
+	if err := s.users.UpdateFields(trx.UserID, map[string]interface{}{"last_transaction_at": trx.CreatedAt}); err != nil {
+		return "", err
+	}
+
+	if trx.Type != models.TypeExpense {
+		return "", nil
+	}
+	// Data baru SUDAH tersimpan, jadi total hari ini sudah termasuk transaksi ini
+	return s.CheckDailyLimit(trx.UserID, 0), nil
+}
+
+// validateTransaction: aturan yang sama dengan CHECK constraint di tabel transactions,
+// dipakai semua jalur input (web, teks bot, tombol bot)
+func validateTransaction(trx *models.Transaction) error {
+	trx.Type = models.TransactionType(strings.ToLower(strings.TrimSpace(string(trx.Type))))
+	trx.Category = strings.TrimSpace(trx.Category)
+
+	if !trx.Type.Valid() {
+		return utils.ErrTransactionTypeInvalid
+	}
+	if trx.Amount <= 0 {
+		return utils.ErrAmountNotPositive
+	}
+	trx.Tags = noteTags(trx)
+	if len(trx.Splits) > 0 {
+		return validateSplits(trx)
+	}
+	_, err := normalizeCategory(trx.Category)
+	return err
+}
+
+// noteTags: hashtag di catatan transaksi & catatan baris split. Baru berisi nama,
+// baris tag milik user dicari/dibuat repository saat transaksi disimpan.
+func noteTags(trx *models.Transaction) []models.Tag {
+	text := trx.Note
+	for _, split := range trx.Splits {
+		text += " " + split.Note
+	}
+	names := utils.ParseHashtags(text)
+	if len(names) == 0 {
+		return nil
+	}
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{Name: name}
+	}
+	return tags
+}
+
+// validateSplits: minimal SplitMinLines baris, tiap baris berkategori & bernominal positif, totalnya = Amount.
+// Category induk diisi kategori baris terbesar (baris pertama kalau sama besar).
+func validateSplits(trx *models.Transaction) error {
+	if len(trx.Splits) < models.SplitMinLines {
+		return utils.ErrSplitInvalid
+	}
+	var total int64
+	largest := 0
+	for i := range trx.Splits {
+		line := &trx.Splits[i]
+		category, err := normalizeCategory(line.Category)
+		if err != nil {
+			return utils.ErrInvalidInput.WithField("splits", utils.CodeCategoryInvalid)
+		}
+		if line.Amount <= 0 {
+			return utils.ErrInvalidInput.WithField("splits", utils.CodeAmountNotPositive)
+		}
+		line.Category = category
+		line.Note = strings.TrimSpace(line.Note)
+		total += line.Amount
+		if line.Amount > trx.Splits[largest].Amount {
+			largest = i
+		}
+	}
+	if total != trx.Amount {
+		return utils.ErrSplitMismatch
+	}
+	trx.Category = trx.Splits[largest].Category
+	return nil
+}
+
+// normalizeCategory: nama kategori tanpa spasi di ujung, wajib diisi & maksimal CategoryMaxLength karakter
+func normalizeCategory(category string) (string, error) {
+	category = strings.TrimSpace(category)
+	if category == "" || utf8.RuneCountInString(category) > models.CategoryMaxLength {
+		return category, utils.ErrCategoryInvalid
+	}
+	return category, nil
+}
+
+// CheckDailyLimit: cek limit harian, return pesan alert (kosong kalau aman).
+// currentAmount dalam minor unit mata uang dasar user.
+func (s *TransactionService) CheckDailyLimit(userID uint, currentAmount int64) string {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return ""
+	}
+
+	if user.DailyLimit <= 0 {
+		return ""
+	}
+
+	// "Hari ini" menurut zona waktu user, bukan zona server
+	now := time.Now().In(utils.UserLocation(user.Timezone))
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	_, totalToday, err := s.PeriodTotals(userID, baseCurrency(user), startOfDay, time.Time{})
+	if err != nil {
+		return ""
+	}
+
+	// Tambahkan transaksi yang baru saja akan diinput untuk pengecekan prediksi
+	if (totalToday + currentAmount) >= user.DailyLimit {
+		pesan := user.AlertMessage
+		if pesan == "" {
+			pesan = utils.T(utils.UserLanguage(user.Language), "alert.daily_limit")
+		}
+		return pesan
+	}
+
+	return ""
+}
+
+// PeriodTotals: total pemasukan & pengeluaran dalam [from, to) setelah dikonversi ke mata uang "base".
+// Dijumlahkan di database, jadi cepat walau transaksinya ratusan ribu.
+func (s *TransactionService) PeriodTotals(userID uint, base string, from, to time.Time) (income, expense int64, err error) {
+	rows, err := s.transactions.Totals(repository.TotalsFilter{UserID: userID, From: from, To: to, BaseCurrency: base})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	conv := newConverter(s.rates)
+	for _, row := range rows {
+		amount, err := conv.Convert(row.Total, row.Currency, base, rateDay(row))
+		if err != nil {
+			return 0, 0, err
+		}
+		switch row.Type {
+		case models.TypeIncome:
+			income += amount
+		case models.TypeExpense:
+			expense += amount
+		}
+	}
+	return income, expense, nil
+}
+
+// rateDay: tanggal kurs untuk satu baris agregasi (zero untuk mata uang dasar)
+func rateDay(row repository.TransactionTotal) time.Time {
+	if len(row.RateDay) < 10 {
+		return time.Time{}
+	}
+	day, _ := time.Parse("2006-01-02", row.RateDay[:10])
+	return day
+}
+
+// BaseCurrency: mata uang dasar user untuk ringkasan & laporan
+func (s *TransactionService) BaseCurrency(userID uint) (string, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return baseCurrency(user), nil
+}
+
+// ConvertAll: nominal tiap transaksi dalam mata uang dasar user (urutan sama dengan input).
+// *MissingRateError kalau ada kurs yang belum diisi admin.
+func (s *TransactionService) ConvertAll(userID uint, trx []models.Transaction) ([]int64, string, error) {
+	base, err := s.BaseCurrency(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	amounts, err := newConverter(s.rates).transactions(trx, base)
+	return amounts, base, err
+}
+
+// Summary: total pemasukan & pengeluaran dalam [from, to) dalam mata uang dasar user
+// (from/to zero = tanpa batas, jadi keduanya zero = sepanjang waktu)
+func (s *TransactionService) Summary(userID uint, from, to time.Time) (income, expense int64, currency string, err error) {
+	currency, err = s.BaseCurrency(userID)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	income, expense, err = s.PeriodTotals(userID, currency, from, to)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return income, expense, currency, nil
+}
+
+// MaxChartDays: batas panjang rentang grafik harian
+const MaxChartDays = 366
+
+// DailyChart: pemasukan & pengeluaran per hari dalam rentang [from, to), from = tengah malam
+// di zona waktu user (batas hari & tanggal di hasil ikut zona from). Semua hari ada di hasil (yang kosong bernilai 0), urut dari tanggal paling awal.
+func (s *TransactionService) DailyChart(userID uint, from, to time.Time) ([]DailyStats, string, error) {
+	currency, err := s.BaseCurrency(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	days := []time.Time{from}
+	for day := from; day.Before(to) && len(days) <= MaxChartDays; {
+		day = day.AddDate(0, 0, 1)
+		days = append(days, day)
+	}
+
+	rows, err := s.transactions.Totals(repository.TotalsFilter{UserID: userID, BaseCurrency: currency, Buckets: days})
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]DailyStats, len(days)-1)
+	for i := range result {
+		result[i].Date = days[i].Format("2006-01-02")
+	}
+
+	conv := newConverter(s.rates)
+	for _, row := range rows {
+		if row.Bucket < 0 || row.Bucket >= len(result) {
+			continue
+		}
+		amount, err := conv.Convert(row.Total, row.Currency, currency, rateDay(row))
+		if err != nil {
+			return nil, "", err
+		}
+		switch row.Type {
+		case models.TypeIncome:
+			result[row.Bucket].Income += amount
+		case models.TypeExpense:
+			result[row.Bucket].Expense += amount
+		}
+	}
+	return result, currency, nil
+}
+
+// CategorySummary: total per tipe + kategori dalam [from, to) (zero = tanpa batas),
+// urut per tipe lalu total terbesar
+func (s *TransactionService) CategorySummary(userID uint, from, to time.Time) ([]CategoryStats, string, error) {
+	currency, err := s.BaseCurrency(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := s.transactions.Totals(repository.TotalsFilter{
+		UserID: userID, From: from, To: to, ByCategory: true, BaseCurrency: currency,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	type categoryKey struct {
+		Type     models.TransactionType
+		Category string
+	}
+	tempMap := make(map[categoryKey]int64)
+	conv := newConverter(s.rates)
+	for _, row := range rows {
+		amount, err := conv.Convert(row.Total, row.Currency, currency, rateDay(row))
+		if err != nil {
+			return nil, "", err
+		}
+		tempMap[categoryKey{row.Type, row.Category}] += amount
+	}
+
+	results := make([]CategoryStats, 0, len(tempMap))
+	for key, total := range tempMap {
+		results = append(results, CategoryStats{
+			Type:     key.Type,
+			Category: key.Category,
+			Total:    total,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		if results[i].Total != results[j].Total {
+			return results[i].Total > results[j].Total
+		}
+		return results[i].Category < results[j].Category
+	})
+	return results, currency, nil
+}
+
+// Restore: kembalikan transaksi milik user dari tong sampah.
+// Return false kalau data tidak ada / bukan miliknya / sudah lewat masa simpan.
+func (s *TransactionService) Restore(userID, id uint) (bool, error) {
+	return s.transactions.Restore(userID, id, utils.TrashCutoff(time.Now()))
+}
+
+// Trash: transaksi yang dihapus dan masih bisa dikembalikan
+func (s *TransactionService) Trash(userID uint) ([]models.Transaction, error) {
+	return s.transactions.ListDeleted(userID, utils.TrashCutoff(time.Now()))
+}
@@ -0,0 +1,189 @@
+package services_test
+
+import (
+	"backend-gin/database"
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/services"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Benchmark agregasi di database vs cara lama (muat semua transaksi, konversi per transaksi,
+// lalu jumlahkan di Go).
+//
+//	go test ./services/ -run '^$' -bench . -benchtime 20x
+const benchTransactions = 100_000
+
+var (
+	benchOnce   sync.Once
+	benchRepos  *repository.Repositories
+	benchUserID uint
+	benchErr    error
+)
+
+// benchData: satu database SQLite in-memory berisi 100k transaksi sepanjang ±1 tahun, dipakai semua benchmark
+func benchData(b *testing.B) (*repository.Repositories, uint) {
+	b.Helper()
+	benchOnce.Do(func() {
+		var db *gorm.DB
+		db, benchErr = database.Open(database.DriverSQLite, ":memory:", &gorm.Config{Logger: logger.Discard})
+		if benchErr != nil {
+			return
+		}
+		sqlDB, _ := db.DB()
+		sqlDB.SetMaxOpenConns(1)
+		if _, benchErr = database.MigrateUp(db, false); benchErr != nil {
+			return
+		}
+
+		user := models.User{Username: "bench", Role: "user", Status: "active"}
+		if benchErr = db.Create(&user).Error; benchErr != nil {
+			return
+		}
+		// User lain supaya index user_id benar-benar dipakai
+		other := models.User{Username: "lain", Role: "user", Status: "active"}
+		if benchErr = db.Create(&other).Error; benchErr != nil {
+			return
+		}
+
+		rate := models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16250", EffectiveDate: time.Now().AddDate(-2, 0, 0)}
+		if benchErr = db.Create(&rate).Error; benchErr != nil {
+			return
+		}
+
+		categories := []string{"Makan", "Transport", "Belanja", "Tagihan", "Hiburan"}
+		start := time.Now().AddDate(-1, 0, 0)
+		batch := make([]models.Transaction, 0, 1000)
+		for i := 0; i < benchTransactions; i++ {
+			trx := models.Transaction{
+				UserID:    user.ID,
+				Type:      models.TypeExpense,
+				Amount:    int64(1000 + i%50000),
+				Currency:  "IDR",
+				Category:  categories[i%len(categories)],
+				CreatedAt: start.Add(time.Duration(i) * 5 * time.Minute),
+			}
+			if i%10 == 0 {
+				trx.Type, trx.Category = models.TypeIncome, "Gaji"
+			}
+			if i%100 == 1 {
+				trx.Currency, trx.Amount = "USD", int64(100+i%5000)
+			}
+			if i%4 == 0 {
+				trx.UserID = other.ID
+			}
+			batch = append(batch, trx)
+			if len(batch) == cap(batch) {
+				if benchErr = db.Create(&batch).Error; benchErr != nil {
+					return
+				}
+				batch = batch[:0]
+			}
+		}
+		benchRepos = repository.New(db)
+		benchUserID = user.ID
+	})
+	if benchErr != nil {
+		b.Fatal(benchErr)
+	}
+	return benchRepos, benchUserID
+}
+
+func BenchmarkSummary(b *testing.B) {
+	repos, userID := benchData(b)
+	svc := services.New(repos)
+
+	b.Run("sql", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, _, err := svc.Transactions.Summary(userID, time.Time{}, time.Time{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("in_memory", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			trx, err := repos.Transactions.FindInPeriod(userID, time.Time{}, time.Time{}, false)
+			if err != nil {
+				b.Fatal(err)
+			}
+			amounts, _, err := svc.Transactions.ConvertAll(userID, trx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var income, expense int64
+			for j, t := range trx {
+				if t.Type == models.TypeIncome {
+					income += amounts[j]
+				} else {
+					expense += amounts[j]
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkCategorySummary(b *testing.B) {
+	repos, userID := benchData(b)
+	svc := services.New(repos)
+
+	b.Run("sql", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := svc.Transactions.CategorySummary(userID, time.Time{}, time.Time{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("in_memory", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			trx, err := repos.Transactions.FindInPeriod(userID, time.Time{}, time.Time{}, false)
+			if err != nil {
+				b.Fatal(err)
+			}
+			amounts, _, err := svc.Transactions.ConvertAll(userID, trx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			totals := make(map[string]int64)
+			for j, t := range trx {
+				totals[string(t.Type)+"-"+t.Category] += amounts[j]
+			}
+		}
+	})
+}
+
+func BenchmarkDailyChart(b *testing.B) {
+	repos, userID := benchData(b)
+	svc := services.New(repos)
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	from := to.AddDate(0, 0, -31)
+
+	b.Run("sql", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := svc.Transactions.DailyChart(userID, from, to); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("in_memory", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			trx, err := repos.Transactions.FindInPeriod(userID, from, to, false)
+			if err != nil {
+				b.Fatal(err)
+			}
+			amounts, _, err := svc.Transactions.ConvertAll(userID, trx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			days := make(map[string]int64)
+			for j, t := range trx {
+				days[t.CreatedAt.Format("2006-01-02")] += amounts[j]
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"backend-gin/models"
+	"backend-gin/repository"
+	"backend-gin/utils"
+	"time"
+)
+
+type UserService struct {
+	users        repository.UserRepository
+	transactions repository.TransactionRepository
+	trxService   *TransactionService
+}
+
+func NewUserService(users repository.UserRepository, transactions repository.TransactionRepository, trxService *TransactionService) *UserService {
+	return &UserService{users: users, transactions: transactions, trxService: trxService}
+}
+
+// MonthlyStats: pemasukan & pengeluaran user sejak awal bulan ini menurut zona waktu user
+// (dalam mata uang dasar user)
+func (s *UserService) MonthlyStats(userID uint, now time.Time) (income, expense int64, currency string, err error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	now = now.In(utils.UserLocation(user.Timezone))
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	currency = baseCurrency(user)
+	income, expense, err = s.trxService.PeriodTotals(userID, currency, startOfMonth, time.Time{})
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return income, expense, currency, nil
+}
+
+// Delete memasukkan user + semua transaksinya ke tong sampah
+func (s *UserService) Delete(userID uint) (transactions int64, err error) {
+	return s.users.SoftDelete(userID, time.Now())
+}
+
+// Trash: user yang dihapus admin dan masih bisa dikembalikan
+func (s *UserService) Trash() ([]models.User, error) {
+	return s.users.ListDeleted(utils.TrashCutoff(time.Now()))
+}
+
+// Restore mengembalikan user beserta transaksi yang ikut terhapus bersamanya
+func (s *UserService) Restore(userID uint) (*models.User, int64, error) {
+	user, err := s.users.FindDeleted(userID, utils.TrashCutoff(time.Now()))
+	if err != nil {
+		return nil, 0, err
+	}
+	trxCount, err := s.users.Restore(user)
+	if err != nil {
+		return nil, 0, err
+	}
+	return user, trxCount, nil
+}
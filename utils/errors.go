@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Kode error umum (stabil, dipakai frontend). Kode validasi ada di validation.go
+const (
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeTokenRequired       = "TOKEN_REQUIRED"
+	CodeTokenInvalid        = "TOKEN_INVALID"
+	CodeSessionExpired      = "SESSION_EXPIRED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeTrialExpired        = "TRIAL_EXPIRED"
+	CodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	CodeOwnerSecretInvalid  = "OWNER_SECRET_INVALID"
+	CodeUserNotFound        = "USER_NOT_FOUND"
+	CodeProfileConflict     = "PROFILE_CONFLICT"
+	CodeTransactionNotFound = "TRANSACTION_NOT_FOUND"
+	CodePaymentNotFound     = "PAYMENT_NOT_FOUND"
+	CodeInvalidAmount       = "INVALID_AMOUNT"
+	CodeFileRequired        = "FILE_REQUIRED"
+	CodePaymentRejected     = "PAYMENT_REJECTED"
+	CodeWalletNotFound      = "WALLET_NOT_FOUND"
+	CodeWalletInUse         = "WALLET_IN_USE"
+	CodeWalletHasGoals      = "WALLET_HAS_GOALS"
+	CodeRateNotFound        = "EXCHANGE_RATE_NOT_FOUND"
+	CodeRateMissing         = "EXCHANGE_RATE_MISSING"
+	CodeAmountTooLarge      = "AMOUNT_TOO_LARGE"
+	CodeImportHasErrors     = "IMPORT_HAS_ERRORS"
+	CodeBulkHasErrors       = "BULK_HAS_ERRORS"
+	CodeStatementNotFound   = "STATEMENT_NOT_FOUND"
+	CodeEntryNotFound       = "STATEMENT_ENTRY_NOT_FOUND"
+	CodeEntryLinked         = "STATEMENT_ENTRY_LINKED"
+	CodeNoDeletionRequest   = "ACCOUNT_DELETION_NOT_REQUESTED"
+	CodeGoalNotFound        = "GOAL_NOT_FOUND"
+	CodeTooManyRequests     = "TOO_MANY_REQUESTS"
+	CodeInternal            = "INTERNAL_ERROR"
+)
+
+// FieldError: detail error per field (untuk ditampilkan di bawah input form)
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+}
+
+// AppError: error standar aplikasi. Pesan untuk user diambil dari katalog bahasa berdasarkan Code
+type AppError struct {
+	Status  int           // HTTP status
+	Code    string        // Kode stabil, sekaligus key katalog pesan
+	Args    []interface{} // Argumen untuk format pesan (opsional)
+	Details []FieldError  // Detail per field (opsional)
+	Err     error         // Penyebab internal, hanya masuk log (tidak dikirim ke client)
+}
+
+func NewAppError(status int, code string) *AppError {
+	return &AppError{Status: status, Code: code}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Code, e.Err)
+	}
+	return e.Code
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Wrap: salinan error dengan penyebab internal (error variabel global tidak ikut berubah)
+func (e *AppError) Wrap(err error) *AppError {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+// WithArgs: salinan error dengan argumen format pesan
+func (e *AppError) WithArgs(args ...interface{}) *AppError {
+	cp := *e
+	cp.Args = args
+	return &cp
+}
+
+// WithField: salinan error dengan tambahan detail field
+func (e *AppError) WithField(field, code string) *AppError {
+	cp := *e
+	cp.Details = append(append([]FieldError{}, e.Details...), FieldError{Field: field, Code: code})
+	return &cp
+}
+
+// Field: field pertama yang bermasalah (kosong kalau tidak ada)
+func (e *AppError) Field() string {
+	if len(e.Details) == 0 {
+		return ""
+	}
+	return e.Details[0].Field
+}
+
+// AsAppError: ubah error apa saja jadi *AppError (error asing dianggap INTERNAL_ERROR)
+func AsAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return ErrInternal.Wrap(err)
+}
+
+// Error siap pakai
+var (
+	ErrInvalidInput           = NewAppError(http.StatusBadRequest, CodeInvalidInput)
+	ErrUnauthorized           = NewAppError(http.StatusUnauthorized, CodeUnauthorized)
+	ErrTokenRequired          = NewAppError(http.StatusUnauthorized, CodeTokenRequired)
+	ErrTokenInvalid           = NewAppError(http.StatusUnauthorized, CodeTokenInvalid)
+	ErrSessionExpired         = NewAppError(http.StatusUnauthorized, CodeSessionExpired)
+	ErrInvalidCredentials     = NewAppError(http.StatusUnauthorized, CodeInvalidCredentials)
+	ErrForbidden              = NewAppError(http.StatusForbidden, CodeForbidden)
+	ErrTrialExpired           = NewAppError(http.StatusForbidden, CodeTrialExpired)
+	ErrOwnerSecretInvalid     = NewAppError(http.StatusForbidden, CodeOwnerSecretInvalid)
+	ErrUserNotFound           = NewAppError(http.StatusNotFound, CodeUserNotFound)
+	ErrTransactionNotFound    = NewAppError(http.StatusNotFound, CodeTransactionNotFound)
+	ErrPaymentNotFound        = NewAppError(http.StatusNotFound, CodePaymentNotFound)
+	ErrUsernameTaken          = NewAppError(http.StatusConflict, CodeUsernameTaken).WithField("username", CodeUsernameTaken)
+	ErrProfileConflict        = NewAppError(http.StatusConflict, CodeProfileConflict)
+	ErrInvalidAmount          = NewAppError(http.StatusBadRequest, CodeInvalidAmount).WithField("amount", CodeInvalidAmount)
+	ErrFileRequired           = NewAppError(http.StatusBadRequest, CodeFileRequired).WithField("file", CodeFileRequired)
+	ErrPaymentRejected        = NewAppError(http.StatusBadRequest, CodePaymentRejected)
+	ErrWalletNotFound         = NewAppError(http.StatusNotFound, CodeWalletNotFound)
+	ErrWalletInUse            = NewAppError(http.StatusConflict, CodeWalletInUse)
+	ErrWalletHasGoals         = NewAppError(http.StatusConflict, CodeWalletHasGoals)
+	ErrRateNotFound           = NewAppError(http.StatusNotFound, CodeRateNotFound)
+	ErrRateMissing            = NewAppError(http.StatusUnprocessableEntity, CodeRateMissing)
+	ErrAmountTooLarge         = NewAppError(http.StatusUnprocessableEntity, CodeAmountTooLarge)
+	ErrCurrencyInvalid        = validationError("currency", CodeCurrencyInvalid)
+	ErrCurrencyMismatch       = validationError("currency", CodeCurrencyMismatch)
+	ErrTransactionTypeInvalid = validationError("type", CodeTransactionTypeInvalid)
+	ErrAmountNotPositive      = validationError("amount", CodeAmountNotPositive)
+	ErrCategoryInvalid        = validationError("category", CodeCategoryInvalid)
+	ErrSplitInvalid           = validationError("splits", CodeSplitInvalid)
+	ErrSplitMismatch          = validationError("splits", CodeSplitMismatch)
+	ErrPeriodTooLong          = validationError("period", CodePeriodTooLong)
+	ErrCursorInvalid          = validationError("cursor", CodeCursorInvalid)
+	ErrImportFormatInvalid    = validationError("format", CodeImportFormatInvalid)
+	ErrImportFileInvalid      = validationError("file", CodeImportFileInvalid)
+	ErrImportTooLarge         = validationError("file", CodeImportTooLarge)
+	ErrImportMappingInvalid   = validationError("mapping", CodeImportMappingInvalid)
+	ErrImportHasErrors        = NewAppError(http.StatusUnprocessableEntity, CodeImportHasErrors)
+	ErrBulkHasErrors          = NewAppError(http.StatusUnprocessableEntity, CodeBulkHasErrors)
+	ErrBulkTooLarge           = NewAppError(http.StatusBadRequest, CodeBulkTooLarge)
+	ErrBankInvalid            = validationError("bank", CodeBankInvalid)
+	ErrStatementFormatInvalid = validationError("format", CodeStatementFormatInvalid)
+	ErrStatementEmpty         = validationError("file", CodeStatementEmpty)
+	ErrStatementEncrypted     = validationError("file", CodeStatementEncrypted)
+	ErrMatchWindowInvalid     = validationError("window", CodeMatchWindowInvalid)
+	ErrExportFormatInvalid    = validationError("format", CodeExportFormatInvalid)
+	ErrStatementNotFound      = NewAppError(http.StatusNotFound, CodeStatementNotFound)
+	ErrEntryNotFound          = NewAppError(http.StatusNotFound, CodeEntryNotFound)
+	ErrEntryLinked            = NewAppError(http.StatusConflict, CodeEntryLinked)
+	ErrNoDeletionRequest      = NewAppError(http.StatusNotFound, CodeNoDeletionRequest)
+	ErrGoalNotFound           = NewAppError(http.StatusNotFound, CodeGoalNotFound)
+	ErrTooManyRequests        = NewAppError(http.StatusTooManyRequests, CodeTooManyRequests)
+	ErrGoalLinkInvalid        = validationError("wallet_id", CodeGoalLinkInvalid)
+	ErrGoalDeadlineInvalid    = validationError("deadline", CodeGoalDeadlineInvalid)
+	ErrResetCodeInvalid       = NewAppError(http.StatusBadRequest, CodeResetCodeInvalid).WithField("code", CodeResetCodeInvalid)
+	ErrInternal               = NewAppError(http.StatusInternalServerError, CodeInternal)
+)
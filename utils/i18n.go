@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bahasa default (dipakai kalau Accept-Language / pilihan user tidak didukung)
+const DefaultLanguage = "id"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs[bahasa][key] = pesan (boleh berisi format fmt seperti %s / %d)
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic("Gagal baca katalog bahasa: " + err.Error())
+	}
+
+	result := make(map[string]map[string]string)
+	for _, entry := range entries {
+		content, err := localeFiles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic("Gagal baca katalog bahasa: " + err.Error())
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(content, &messages); err != nil {
+			panic("Katalog bahasa " + entry.Name() + " rusak: " + err.Error())
+		}
+		result[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return result
+}
+
+// T menerjemahkan key ke bahasa yang diminta. Urutan cadangan: bahasa default, lalu key itu sendiri
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// NormalizeLanguage: "en-US" -> "en". Kosong kalau bahasa tidak didukung
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return ""
+}
+
+// ParseAcceptLanguage memilih bahasa yang didukung dari header Accept-Language (memperhatikan nilai q)
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if lang := NormalizeLanguage(fields[0]); lang != "" && q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// UserLanguage: bahasa tersimpan milik user, atau default kalau kosong / tidak didukung
+func UserLanguage(lang string) string {
+	if lang = NormalizeLanguage(lang); lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// errorCodes: nilai semua konstanta Code* di package ini, dibaca dari source supaya kode baru ikut dicek
+func errorCodes(t *testing.T) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[string]string)
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), entry.Name(), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				for i, name := range value.Names {
+					if !strings.HasPrefix(name.Name, "Code") || i >= len(value.Values) {
+						continue
+					}
+					if lit, ok := value.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						codes[name.Name], _ = strconv.Unquote(lit.Value)
+					}
+				}
+			}
+		}
+	}
+	if len(codes) < 50 {
+		t.Fatalf("hanya %d konstanta Code* terbaca", len(codes))
+	}
+	return codes
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, lang := range []string{"id", "en"} {
+		if catalogs[lang] == nil {
+			t.Fatalf("katalog %s tidak ada", lang)
+		}
+		for name, code := range errorCodes(t) {
+			if catalogs[lang][code] == "" {
+				t.Errorf("katalog %s tidak punya pesan untuk %s (%s)", lang, name, code)
+			}
+		}
+	}
+
+	// Kedua katalog punya key yang sama, dengan format argumen (%s, %d) yang sama urutannya
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for key, id := range catalogs["id"] {
+		en, ok := catalogs["en"][key]
+		if !ok {
+			t.Errorf("key %s tidak ada di katalog en", key)
+			continue
+		}
+		if a, b := verbs.FindAllString(id, -1), verbs.FindAllString(en, -1); strings.Join(a, " ") != strings.Join(b, " ") {
+			t.Errorf("argumen %s beda: id %v, en %v", key, a, b)
+		}
+	}
+	for key := range catalogs["en"] {
+		if _, ok := catalogs["id"][key]; !ok {
+			t.Errorf("key %s tidak ada di katalog id", key)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"", "id"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"EN_gb", "en"},
+		{"id-ID,id;q=0.9,en;q=0.8", "id"},
+		{"id;q=0.4, en;q=0.8", "en"},           // q tertinggi menang, bukan urutan
+		{"en;q=0.5, id;q=0.5", "en"},           // q sama: urutan header
+		{"fr-FR, en;q=0.3", "en"},              // bahasa tak didukung dilewati
+		{"de, fr;q=0.9", "id"},                 // tidak ada yang didukung: default
+		{"en;q=0, id;q=0.1", "id"},             // q=0 berarti ditolak
+		{"en;q=0", "id"},                       // semua ditolak: default
+		{"en;q=abc, id;q=0.5", "en"},           // q rusak dianggap 1
+		{" en ; q=0.7 , id ; q=0.6 ", "en"},    // spasi diabaikan
+		{"*", "id"},                            // wildcard: default
+		{"en-US;level=1;q=0.9,id;q=0.8", "en"}, // parameter lain diabaikan
+	}
+	for _, tc := range cases {
+		if got := ParseAcceptLanguage(tc.header); got != tc.want {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, mau %q", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestTFallback(t *testing.T) {
+	if got := T("en", CodeUserNotFound); got != catalogs["en"][CodeUserNotFound] {
+		t.Errorf("T(en) = %q", got)
+	}
+	// Bahasa tak didukung: pesan bahasa default, key tak dikenal: key itu sendiri
+	if got := T("fr", CodeUserNotFound); got != catalogs["id"][CodeUserNotFound] {
+		t.Errorf("T(fr) = %q", got)
+	}
+	if got := T("en", "tidak.ada"); got != "tidak.ada" {
+		t.Errorf("T(key asing) = %q", got)
+	}
+	for input, want := range map[string]string{"en-US": "en", "ID": "id", "fr": "id", "": "id"} {
+		if got := UserLanguage(input); got != want {
+			t.Errorf("UserLanguage(%q) = %q, mau %q", input, got, want)
+		}
+	}
+}
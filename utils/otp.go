@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"math/big"
+)
+
+// GenerateNumericCode membuat kode angka acak (crypto/rand) sepanjang n digit
+func GenerateNumericCode(n int) (string, error) {
+	code := make([]byte, n)
+	for i := range code {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + d.Int64())
+	}
+	return string(code), nil
+}
+
+// HashCode menghasilkan hash SHA-256 (hex) dari kode sekali pakai
+func HashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CompareCode membandingkan kode dengan hash-nya secara constant-time
+func CompareCode(hash, code string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashCode(code))) == 1
+}
@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Preset periode laporan (query ?period=...)
+const (
+	PeriodToday      = "today"
+	PeriodYesterday  = "yesterday"
+	PeriodThisWeek   = "this_week"
+	PeriodLastWeek   = "last_week"
+	PeriodThisMonth  = "this_month"
+	PeriodLastMonth  = "last_month"
+	PeriodLast30Days = "last_30_days"
+	PeriodThisYear   = "this_year"
+	PeriodLastYear   = "last_year"
+	PeriodYTD        = "ytd"
+	PeriodAll        = "all"
+)
+
+// MaxMonthStartDay: awal bulan custom dibatasi 28 supaya ada di semua bulan (termasuk Februari)
+const MaxMonthStartDay = 28
+
+// PeriodQuery: parameter periode dari query string, semua opsional.
+// Pakai salah satu: Period (preset), From/To (YYYY-MM-DD, To ikut dihitung), atau Month/Year.
+type PeriodQuery struct {
+	Period string
+	From   string
+	To     string
+	Month  string
+	Year   string
+	// MonthStartDay: tanggal awal "bulan" versi user (mis. tanggal gajian), 1-28.
+	// Dipakai this_month, last_month & month/year. 0 dianggap tanggal 1.
+	MonthStartDay int
+}
+
+// Empty: tidak ada filter periode sama sekali (endpoint boleh pakai default sendiri)
+func (q PeriodQuery) Empty() bool {
+	return q.Period == "" && q.From == "" && q.To == "" && q.Month == "" && q.Year == ""
+}
+
+// Period: rentang waktu [From, To). Zero berarti tanpa batas di sisi itu.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Bounded: kedua sisi rentang ada batasnya
+func (p Period) Bounded() bool {
+	return !p.From.IsZero() && !p.To.IsZero()
+}
+
+// ResolvePeriod mengubah PeriodQuery jadi rentang waktu relatif terhadap "now"
+// (zona waktu ikut now.Location()). Minggu dimulai hari Senin.
+func ResolvePeriod(q PeriodQuery, now time.Time) (Period, *AppError) {
+	q.Period = strings.ToLower(strings.TrimSpace(q.Period))
+	startDay := q.MonthStartDay
+	if startDay == 0 {
+		startDay = 1
+	}
+	if startDay < 1 || startDay > MaxMonthStartDay {
+		return Period{}, validationError("month_start_day", CodeMonthStartDayInvalid)
+	}
+
+	explicit := q.From != "" || q.To != ""
+	calendar := q.Month != "" || q.Year != ""
+	if (q.Period != "" && (explicit || calendar)) || (explicit && calendar) {
+		return Period{}, validationError("period", CodePeriodConflict)
+	}
+
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch {
+	case explicit:
+		return explicitPeriod(q.From, q.To, loc)
+	case calendar:
+		return calendarPeriod(q.Month, q.Year, startDay, now)
+	}
+
+	switch q.Period {
+	case "", PeriodAll:
+		return Period{}, nil
+	case PeriodToday:
+		return Period{From: today, To: today.AddDate(0, 0, 1)}, nil
+	case PeriodYesterday:
+		return Period{From: today.AddDate(0, 0, -1), To: today}, nil
+	case PeriodThisWeek, PeriodLastWeek:
+		monday := WeekStart(today)
+		if q.Period == PeriodLastWeek {
+			monday = monday.AddDate(0, 0, -7)
+		}
+		return Period{From: monday, To: monday.AddDate(0, 0, 7)}, nil
+	case PeriodThisMonth, PeriodLastMonth:
+		start := MonthStart(today, startDay)
+		if q.Period == PeriodLastMonth {
+			start = start.AddDate(0, -1, 0)
+		}
+		return Period{From: start, To: start.AddDate(0, 1, 0)}, nil
+	case PeriodLast30Days:
+		return Period{From: today.AddDate(0, 0, -29), To: today.AddDate(0, 0, 1)}, nil
+	case PeriodThisYear, PeriodLastYear:
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc)
+		if q.Period == PeriodLastYear {
+			start = start.AddDate(-1, 0, 0)
+		}
+		return Period{From: start, To: start.AddDate(1, 0, 0)}, nil
+	case PeriodYTD:
+		return Period{From: time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc), To: today.AddDate(0, 0, 1)}, nil
+	}
+	return Period{}, validationError("period", CodePeriodInvalid)
+}
+
+// WeekStart: Senin tengah malam di minggu yang memuat "day"
+func WeekStart(day time.Time) time.Time {
+	// time.Weekday: Minggu = 0, jadi Senin = mundur (weekday+6)%7 hari
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// MonthStart: awal bulan custom yang memuat "day", mis. startDay 25 & day 10 Nov -> 25 Okt
+func MonthStart(day time.Time, startDay int) time.Time {
+	start := time.Date(day.Year(), day.Month(), startDay, 0, 0, 0, 0, day.Location())
+	if day.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start
+}
+
+func explicitPeriod(from, to string, loc *time.Location) (Period, *AppError) {
+	var p Period
+	if from != "" {
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(from), loc)
+		if err != nil {
+			return Period{}, validationError("from", CodeDateInvalid)
+		}
+		p.From = day
+	}
+	if to != "" {
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(to), loc)
+		if err != nil {
+			return Period{}, validationError("to", CodeDateInvalid)
+		}
+		// Tanggal "to" ikut dihitung: batas atas = tengah malam hari berikutnya
+		p.To = day.AddDate(0, 0, 1)
+	}
+	if p.Bounded() && !p.From.Before(p.To) {
+		return Period{}, validationError("to", CodeDateRangeInvalid)
+	}
+	return p, nil
+}
+
+// calendarPeriod: month+year = satu bulan (mulai tanggal startDay), year saja = satu tahun kalender,
+// month saja = bulan itu di tahun berjalan
+func calendarPeriod(monthStr, yearStr string, startDay int, now time.Time) (Period, *AppError) {
+	year := now.Year()
+	if yearStr != "" {
+		y, err := strconv.Atoi(strings.TrimSpace(yearStr))
+		if err != nil || y < 1 || y > 9999 {
+			return Period{}, ErrInvalidInput.WithField("year", CodeFieldInvalid)
+		}
+		year = y
+	}
+
+	if monthStr == "" {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+		return Period{From: start, To: start.AddDate(1, 0, 0)}, nil
+	}
+
+	month, err := strconv.Atoi(strings.TrimSpace(monthStr))
+	if err != nil || month < 1 || month > 12 {
+		return Period{}, ErrInvalidInput.WithField("month", CodeFieldInvalid)
+	}
+	start := time.Date(year, time.Month(month), startDay, 0, 0, 0, 0, now.Location())
+	return Period{From: start, To: start.AddDate(0, 1, 0)}, nil
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolvePeriod(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*3600)
+	// Rabu, 12 November 2025 jam 15:00
+	now := time.Date(2025, time.November, 12, 15, 0, 0, 0, loc)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+
+	cases := []struct {
+		name     string
+		query    PeriodQuery
+		from, to time.Time
+	}{
+		{"kosong = semua", PeriodQuery{}, time.Time{}, time.Time{}},
+		{"all", PeriodQuery{Period: "all"}, time.Time{}, time.Time{}},
+		{"today", PeriodQuery{Period: "today"}, day(2025, 11, 12), day(2025, 11, 13)},
+		{"yesterday", PeriodQuery{Period: "Yesterday"}, day(2025, 11, 11), day(2025, 11, 12)},
+		{"this_week mulai Senin", PeriodQuery{Period: "this_week"}, day(2025, 11, 10), day(2025, 11, 17)},
+		{"last_week", PeriodQuery{Period: "last_week"}, day(2025, 11, 3), day(2025, 11, 10)},
+		{"this_month", PeriodQuery{Period: "this_month"}, day(2025, 11, 1), day(2025, 12, 1)},
+		{"last_month", PeriodQuery{Period: "last_month"}, day(2025, 10, 1), day(2025, 11, 1)},
+		{"this_month awal tgl 25", PeriodQuery{Period: "this_month", MonthStartDay: 25}, day(2025, 10, 25), day(2025, 11, 25)},
+		{"last_month awal tgl 10", PeriodQuery{Period: "last_month", MonthStartDay: 10}, day(2025, 10, 10), day(2025, 11, 10)},
+		{"last_30_days", PeriodQuery{Period: "last_30_days"}, day(2025, 10, 14), day(2025, 11, 13)},
+		{"this_year", PeriodQuery{Period: "this_year"}, day(2025, 1, 1), day(2026, 1, 1)},
+		{"last_year", PeriodQuery{Period: "last_year"}, day(2024, 1, 1), day(2025, 1, 1)},
+		{"ytd", PeriodQuery{Period: "ytd"}, day(2025, 1, 1), day(2025, 11, 13)},
+		{"from/to inklusif", PeriodQuery{From: "2025-02-01", To: "2025-02-28"}, day(2025, 2, 1), day(2025, 3, 1)},
+		{"from saja", PeriodQuery{From: "2025-02-01"}, day(2025, 2, 1), time.Time{}},
+		{"month/year", PeriodQuery{Month: "2", Year: "2024"}, day(2024, 2, 1), day(2024, 3, 1)},
+		{"month/year awal tgl 25", PeriodQuery{Month: "12", Year: "2025", MonthStartDay: 25}, day(2025, 12, 25), day(2026, 1, 25)},
+		{"year saja", PeriodQuery{Year: "2024"}, day(2024, 1, 1), day(2025, 1, 1)},
+		{"month saja = tahun berjalan", PeriodQuery{Month: "3"}, day(2025, 3, 1), day(2025, 4, 1)},
+	}
+	for _, tc := range cases {
+		got, err := ResolvePeriod(tc.query, now)
+		if err != nil {
+			t.Errorf("%s: error %v", tc.name, err)
+			continue
+		}
+		if !got.From.Equal(tc.from) || !got.To.Equal(tc.to) {
+			t.Errorf("%s: [%v, %v), mau [%v, %v)", tc.name, got.From, got.To, tc.from, tc.to)
+		}
+	}
+}
+
+func TestResolvePeriodInvalid(t *testing.T) {
+	now := time.Date(2025, time.November, 12, 15, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name  string
+		query PeriodQuery
+		field string
+		code  string
+	}{
+		{"preset tidak dikenal", PeriodQuery{Period: "kemarin"}, "period", CodePeriodInvalid},
+		{"preset + from", PeriodQuery{Period: "today", From: "2025-01-01"}, "period", CodePeriodConflict},
+		{"from + month", PeriodQuery{From: "2025-01-01", Month: "1", Year: "2025"}, "period", CodePeriodConflict},
+		{"format tanggal", PeriodQuery{From: "01-02-2025"}, "from", CodeDateInvalid},
+		{"tanggal tidak ada", PeriodQuery{To: "2025-02-30"}, "to", CodeDateInvalid},
+		{"to sebelum from", PeriodQuery{From: "2025-03-01", To: "2025-02-01"}, "to", CodeDateRangeInvalid},
+		{"bulan 13", PeriodQuery{Month: "13", Year: "2025"}, "month", CodeFieldInvalid},
+		{"bulan bukan angka", PeriodQuery{Month: "nov", Year: "2025"}, "month", CodeFieldInvalid},
+		{"tahun 0", PeriodQuery{Month: "1", Year: "0"}, "year", CodeFieldInvalid},
+		{"awal bulan 31", PeriodQuery{Period: "this_month", MonthStartDay: 31}, "month_start_day", CodeMonthStartDayInvalid},
+	}
+	for _, tc := range cases {
+		_, err := ResolvePeriod(tc.query, now)
+		if err == nil {
+			t.Errorf("%s: harusnya error", tc.name)
+			continue
+		}
+		if err.Field() != tc.field || err.Details[0].Code != tc.code {
+			t.Errorf("%s: field %q code %q, mau %q / %q", tc.name, err.Field(), err.Details[0].Code, tc.field, tc.code)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Kode detail field hasil binding (tag validator gin)
+const (
+	CodeFieldRequired = "REQUIRED"
+	CodeFieldTooShort = "TOO_SHORT"
+	CodeFieldTooLong  = "TOO_LONG"
+	CodeFieldInvalid  = "INVALID_VALUE"
+)
+
+func init() {
+	// Nama field di detail error pakai nama JSON ("confirm_password"), bukan nama struct Go
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// Lang: bahasa response HTTP berdasarkan header Accept-Language
+func Lang(c *gin.Context) string {
+	return ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// BindError mengubah error ShouldBindJSON/ShouldBindQuery jadi INVALID_INPUT lengkap dengan detail per field
+func BindError(err error) *AppError {
+	appErr := ErrInvalidInput.Wrap(err)
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		for _, fe := range verrs {
+			appErr = appErr.WithField(fe.Field(), bindingCode(fe.Tag()))
+		}
+	}
+	return appErr
+}
+
+func bindingCode(tag string) string {
+	switch tag {
+	case "required", "required_without":
+		return CodeFieldRequired
+	case "min", "gte", "gt":
+		return CodeFieldTooShort
+	case "max", "lte", "lt":
+		return CodeFieldTooLong
+	default:
+		return CodeFieldInvalid
+	}
+}
+
+// RespondError mengirim error dengan format standar:
+// {"error": "<pesan sesuai bahasa>", "code": "KODE", "details": [{"field", "code", "message"}]}
+func RespondError(c *gin.Context, err error) {
+	RespondErrorWith(c, err, nil)
+}
+
+// RespondErrorWith: RespondError + field tambahan di body (mis. hasil per item operasi massal)
+func RespondErrorWith(c *gin.Context, err error, extra gin.H) {
+	appErr := AsAppError(err)
+	if appErr.Err != nil && appErr.Status >= 500 {
+		log.Printf("[ERROR] %s %s: %v", c.Request.Method, c.FullPath(), appErr)
+	}
+
+	lang := Lang(c)
+	body := gin.H{
+		"error": T(lang, appErr.Code, appErr.Args...),
+		"code":  appErr.Code,
+	}
+	if len(appErr.Details) > 0 {
+		details := make([]gin.H, 0, len(appErr.Details))
+		for _, d := range appErr.Details {
+			details = append(details, gin.H{"field": d.Field, "code": d.Code, "message": T(lang, d.Code)})
+		}
+		body["details"] = details
+	}
+	for key, value := range extra {
+		body[key] = value
+	}
+	c.JSON(appErr.Status, body)
+}
+
+// AbortWithError: RespondError + hentikan middleware chain
+func AbortWithError(c *gin.Context, err error) {
+	RespondError(c, err)
+	c.Abort()
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TagMaxLength: batas panjang nama tag (tanpa '#', dalam karakter)
+const TagMaxLength = 50
+
+// Hashtag di catatan: '#' di awal kata, lalu huruf/angka/'-'/'_' (mis. #liburan-bali, #kantor)
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#-])#([\p{L}\p{N}_-]+)`)
+
+// NormalizeTag: nama tag huruf kecil tanpa '#' di depan. Kosong kalau tidak valid
+// (ada karakter selain huruf/angka/'-'/'_', atau lebih dari TagMaxLength karakter).
+func NormalizeTag(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if name == "" || utf8.RuneCountInString(name) > TagMaxLength {
+		return ""
+	}
+	for _, ch := range name {
+		if ch != '-' && ch != '_' && !unicode.IsLetter(ch) && !unicode.IsDigit(ch) {
+			return ""
+		}
+	}
+	return name
+}
+
+// ParseHashtags: tag dari hashtag di teks (sudah dinormalisasi, tanpa duplikat, urutan kemunculan)
+func ParseHashtags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, match := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		tag := NormalizeTag(match[1])
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
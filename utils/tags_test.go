@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHashtags(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"tiket pesawat #liburan-bali #Kantor", []string{"liburan-bali", "kantor"}},
+		{"#kantor makan siang #KANTOR", []string{"kantor"}},
+		{"(#reimburse), #pajak_2025.", []string{"reimburse", "pajak_2025"}},
+		{"warung#1 & kopi", nil},
+		{"# kosong", nil},
+		{"#" + strings.Repeat("a", TagMaxLength+1), nil},
+	}
+	for _, tc := range cases {
+		if got := ParseHashtags(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseHashtags(%q) = %q, mau %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	for input, want := range map[string]string{
+		"#Liburan-Bali": "liburan-bali",
+		" kantor ":      "kantor",
+		"dua kata":      "",
+		"#":             "",
+	} {
+		if got := NormalizeTag(input); got != want {
+			t.Errorf("NormalizeTag(%q) = %q, mau %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+	"time"
+	// Data zona waktu ikut di-embed, jadi tetap jalan di server/container tanpa paket tzdata
+	_ "time/tzdata"
+)
+
+// DefaultTimezone: zona waktu user lama & user baru
+const DefaultTimezone = "Asia/Jakarta"
+
+// NormalizeTimezone: nama zona IANA baku ("asia/jakarta" tidak diterima), kosong kalau tidak dikenal.
+// "Local" ditolak karena artinya ikut zona server.
+func NormalizeTimezone(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return ""
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// UserLocation: zona waktu tersimpan milik user, atau DefaultTimezone kalau kosong / tidak dikenal.
+// Dipakai untuk semua batas hari/minggu/bulan (limit harian, grafik, laporan, pesan bot).
+func UserLocation(name string) *time.Location {
+	if name = NormalizeTimezone(name); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -7,13 +7,14 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Update: Menerima role juga
-func GenerateToken(userID uint, role string) (string, error) {
+// Update: Menerima role & versi sesi (naik setiap password di-reset, token lama jadi tidak berlaku)
+func GenerateToken(userID uint, role string, sessionVersion uint) (string, error) {
 	apiSecret := os.Getenv("JWT_SECRET") 
 	
 	claims := jwt.MapClaims{}
 	claims["user_id"] = userID
 	claims["role"] = role // BARU: Simpan jabatan di token
+	claims["sv"] = sessionVersion
 	claims["exp"] = time.Now().Add(time.Hour * 24).Unix()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default lama data disimpan di tong sampah sebelum dihapus permanen
+const DefaultTrashRetentionDays = 30
+
+// TrashRetentionDays membaca TRASH_RETENTION_DAYS dari .env (default 30 hari)
+func TrashRetentionDays() int {
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		return days
+	}
+	return DefaultTrashRetentionDays
+}
+
+// TrashCutoff: data yang dihapus sebelum waktu ini sudah lewat masa simpan
+func TrashCutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -TrashRetentionDays())
+}
+
+// Default masa tunggu sebelum akun yang minta dihapus benar-benar dihapus
+const DefaultAccountDeletionDays = 14
+
+// AccountDeletionDays membaca ACCOUNT_DELETION_DAYS dari .env (default 14 hari)
+func AccountDeletionDays() int {
+	if days, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_DAYS")); err == nil && days > 0 {
+		return days
+	}
+	return DefaultAccountDeletionDays
+}
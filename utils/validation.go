@@ -0,0 +1,146 @@
+package utils
+
+import (
+	_ "embed"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Kode error validasi (stabil, dipakai frontend untuk menampilkan pesan yang tepat)
+const (
+	CodeInvalidInput            = "INVALID_INPUT"
+	CodeUsernameInvalid         = "USERNAME_INVALID"
+	CodeUsernameTaken           = "USERNAME_TAKEN"
+	CodePasswordTooShort        = "PASSWORD_TOO_SHORT"
+	CodePasswordTooLong         = "PASSWORD_TOO_LONG"
+	CodePasswordContainsUser    = "PASSWORD_CONTAINS_USERNAME"
+	CodePasswordBreached        = "PASSWORD_BREACHED"
+	CodePasswordMismatch        = "PASSWORD_MISMATCH"
+	CodeCurrentPasswordRequired = "CURRENT_PASSWORD_REQUIRED"
+	CodeCurrentPasswordWrong    = "CURRENT_PASSWORD_WRONG"
+	CodeResetCodeInvalid        = "RESET_CODE_INVALID"
+	CodeCurrencyInvalid         = "CURRENCY_INVALID"
+	CodeCurrencyMismatch        = "CURRENCY_MISMATCH"
+	CodeRateInvalid             = "EXCHANGE_RATE_INVALID"
+	CodeTransactionTypeInvalid  = "TRANSACTION_TYPE_INVALID"
+	CodeAmountNotPositive       = "AMOUNT_NOT_POSITIVE"
+	CodeCategoryInvalid         = "CATEGORY_INVALID"
+	CodePeriodInvalid           = "PERIOD_INVALID"
+	CodePeriodConflict          = "PERIOD_CONFLICT"
+	CodePeriodTooLong           = "PERIOD_TOO_LONG"
+	CodeDateInvalid             = "DATE_INVALID"
+	CodeDateRangeInvalid        = "DATE_RANGE_INVALID"
+	CodeMonthStartDayInvalid    = "MONTH_START_DAY_INVALID"
+	CodeCursorInvalid           = "CURSOR_INVALID"
+	CodeTimezoneInvalid         = "TIMEZONE_INVALID"
+	CodeIntervalInvalid         = "INTERVAL_INVALID"
+	CodeImportFormatInvalid     = "IMPORT_FORMAT_INVALID"
+	CodeImportFileInvalid       = "IMPORT_FILE_INVALID"
+	CodeImportTooLarge          = "IMPORT_TOO_LARGE"
+	CodeImportMappingInvalid    = "IMPORT_MAPPING_INVALID"
+	CodeDateFormatInvalid       = "DATE_FORMAT_INVALID"
+	CodeBankInvalid             = "BANK_INVALID"
+	CodeStatementFormatInvalid  = "STATEMENT_FORMAT_INVALID"
+	CodeStatementEmpty          = "STATEMENT_EMPTY"
+	CodeStatementEncrypted      = "STATEMENT_ENCRYPTED"
+	CodeMatchWindowInvalid      = "MATCH_WINDOW_INVALID"
+	CodeExportFormatInvalid     = "EXPORT_FORMAT_INVALID"
+	CodeBulkTooLarge            = "BULK_TOO_LARGE"
+	CodeSplitInvalid            = "SPLIT_INVALID"
+	CodeSplitMismatch           = "SPLIT_TOTAL_MISMATCH"
+	CodeGoalLinkInvalid         = "GOAL_LINK_INVALID"
+	CodeGoalDeadlineInvalid     = "GOAL_DEADLINE_INVALID"
+)
+
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 32
+	PasswordMinLength = 8
+	PasswordMaxLength = 72 // Batas bcrypt, sisanya diabaikan diam-diam
+)
+
+// Huruf di depan, lalu huruf/angka/titik/underscore
+var usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.]*$`)
+
+//go:embed data/breached_passwords.txt
+var breachedPasswordsFile string
+
+var breachedPasswords = loadBreachedPasswords(breachedPasswordsFile)
+
+func loadBreachedPasswords(content string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	return set
+}
+
+// validationError: error 400 dengan satu detail field, pesan diambil dari katalog berdasarkan code
+func validationError(field, code string) *AppError {
+	return NewAppError(http.StatusBadRequest, code).WithField(field, code)
+}
+
+// NormalizeUsername membuang spasi di awal/akhir username
+func NormalizeUsername(username string) string {
+	return strings.TrimSpace(username)
+}
+
+// ValidateUsername: 3-32 karakter, diawali huruf, hanya huruf/angka/titik/underscore
+func ValidateUsername(username string) *AppError {
+	if len(username) < UsernameMinLength || len(username) > UsernameMaxLength || !usernamePattern.MatchString(username) {
+		return validationError("username", CodeUsernameInvalid)
+	}
+	return nil
+}
+
+// ValidatePassword: cek panjang, tidak mengandung username, dan tidak ada di daftar password bocor
+func ValidatePassword(password, username string) *AppError {
+	if len(password) < PasswordMinLength {
+		return validationError("password", CodePasswordTooShort)
+	}
+	if len(password) > PasswordMaxLength {
+		return validationError("password", CodePasswordTooLong)
+	}
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return validationError("password", CodePasswordContainsUser)
+	}
+	if _, found := breachedPasswords[strings.ToLower(password)]; found {
+		return validationError("password", CodePasswordBreached)
+	}
+	return nil
+}
+
+// ValidateUsernameForPassword: aturan "password tidak mengandung username" berlaku juga saat
+// username diganti tanpa ganti password (password = password lama, plaintext)
+func ValidateUsernameForPassword(username, password string) *AppError {
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return validationError("username", CodePasswordContainsUser)
+	}
+	return nil
+}
+
+// ValidateNewPassword: ValidatePassword + cek konfirmasi password
+func ValidateNewPassword(password, confirmPassword, username string) *AppError {
+	if password != confirmPassword {
+		return validationError("confirm_password", CodePasswordMismatch)
+	}
+	return ValidatePassword(password, username)
+}
+
+// ValidateCurrentPassword: wajib konfirmasi password lama sebelum user mengubah data sensitif miliknya sendiri
+func ValidateCurrentPassword(hashedPassword, currentPassword string) *AppError {
+	if currentPassword == "" {
+		return validationError("current_password", CodeCurrentPasswordRequired)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(currentPassword)); err != nil {
+		return validationError("current_password", CodeCurrentPasswordWrong)
+	}
+	return nil
+}
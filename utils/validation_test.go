@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// expectValidation: err harus nil (code kosong) atau error 400 dengan field & code tersebut
+func expectValidation(t *testing.T, name string, err *AppError, field, code string) {
+	t.Helper()
+	switch {
+	case code == "" && err != nil:
+		t.Errorf("%s: error %s (%s), mau lolos", name, err.Code, err.Field())
+	case code != "" && err == nil:
+		t.Errorf("%s: lolos, mau %s", name, code)
+	case code != "" && (err.Code != code || err.Field() != field || err.Status != 400):
+		t.Errorf("%s: %d %s (%s), mau 400 %s (%s)", name, err.Status, err.Code, err.Field(), code, field)
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	cases := []struct {
+		name     string
+		username string
+		valid    bool
+	}{
+		{"huruf saja", "budi", true},
+		{"angka, titik & underscore", "budi_s.2025", true},
+		{"huruf besar", "BudiSantoso", true},
+		{"panjang minimal", "abc", true},
+		{"panjang maksimal", "a" + strings.Repeat("b", 31), true},
+		{"terlalu pendek", "ab", false},
+		{"terlalu panjang", "a" + strings.Repeat("b", 32), false},
+		{"kosong", "", false},
+		{"diawali angka", "1budi", false},
+		{"diawali underscore", "_budi", false},
+		{"spasi", "budi santoso", false},
+		{"tanda hubung", "budi-santoso", false},
+		{"simbol", "budi@mail", false},
+		{"huruf non-ASCII", "bùdi", false},
+	}
+	for _, tc := range cases {
+		code := CodeUsernameInvalid
+		if tc.valid {
+			code = ""
+		}
+		expectValidation(t, tc.name, ValidateUsername(tc.username), "username", code)
+	}
+
+	if got := NormalizeUsername("  budi\t\n"); got != "budi" {
+		t.Errorf("NormalizeUsername = %q", got)
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	cases := []struct {
+		name     string
+		password string
+		username string
+		code     string
+	}{
+		{"valid", "RahasiaSekali123", "budi", ""},
+		{"hanya angka tapi tidak bocor", "90817263", "budi", ""},
+		{"hanya huruf kecil", "kucingorenlucu", "budi", ""},
+		{"spasi & simbol", "kopi susu @ 7 pagi!", "budi", ""},
+		{"panjang minimal", "Kx7#mQ2p", "budi", ""},
+		{"panjang maksimal", strings.Repeat("Kx7#", 18), "budi", ""},
+		{"terlalu pendek", "Kx7#mQ2", "budi", CodePasswordTooShort},
+		{"kosong", "", "budi", CodePasswordTooShort},
+		{"terlalu panjang (batas bcrypt)", strings.Repeat("Kx7#", 18) + "a", "budi", CodePasswordTooLong},
+		{"mengandung username", "budi12345678", "budi", CodePasswordContainsUser},
+		{"mengandung username beda huruf besar", "RahasiaBUDI99", "Budi", CodePasswordContainsUser},
+		{"username kosong tidak dicek", "RahasiaSekali123", "", ""},
+		{"daftar bocor", "password123", "budi", CodePasswordBreached},
+		{"daftar bocor beda huruf besar", "IloveYou", "budi", CodePasswordBreached},
+		{"daftar bocor hanya angka", "12345678", "budi", CodePasswordBreached},
+	}
+	for _, tc := range cases {
+		expectValidation(t, tc.name, ValidatePassword(tc.password, tc.username), "password", tc.code)
+	}
+}
+
+func TestValidateNewPassword(t *testing.T) {
+	cases := []struct {
+		name              string
+		password, confirm string
+		field, code       string
+	}{
+		{"cocok", "RahasiaSekali123", "RahasiaSekali123", "", ""},
+		{"konfirmasi beda", "RahasiaSekali123", "RahasiaSekali124", "confirm_password", CodePasswordMismatch},
+		{"konfirmasi beda huruf besar", "RahasiaSekali123", "rahasiasekali123", "confirm_password", CodePasswordMismatch},
+		// Konfirmasi dicek dulu, baru aturan password
+		{"beda & terlalu pendek", "pendek", "pendek1", "confirm_password", CodePasswordMismatch},
+		{"cocok tapi terlalu pendek", "pendek", "pendek", "password", CodePasswordTooShort},
+		{"cocok tapi mengandung username", "budi_rahasia", "budi_rahasia", "password", CodePasswordContainsUser},
+		{"cocok tapi bocor", "qwertyuiop", "qwertyuiop", "password", CodePasswordBreached},
+	}
+	for _, tc := range cases {
+		expectValidation(t, tc.name, ValidateNewPassword(tc.password, tc.confirm, "budi"), tc.field, tc.code)
+	}
+}
+
+func TestValidateUsernameForPassword(t *testing.T) {
+	cases := []struct {
+		name               string
+		username, password string
+		code               string
+	}{
+		{"tidak terkandung", "budiman", "RahasiaSekali123", ""},
+		{"terkandung", "Rahasia", "RahasiaSekali123", CodePasswordContainsUser},
+		{"terkandung beda huruf besar", "SEKALI", "RahasiaSekali123", CodePasswordContainsUser},
+		{"username kosong", "", "RahasiaSekali123", ""},
+	}
+	for _, tc := range cases {
+		expectValidation(t, tc.name, ValidateUsernameForPassword(tc.username, tc.password), "username", tc.code)
+	}
+}